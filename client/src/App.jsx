@@ -7,6 +7,7 @@ import { AuthProvider } from "./auth/AuthProvider";
 import LoginPage from "./pages/LoginPage";
 import RequireAuth from "./components/RequireAuth";
 import MainPage from "./components/main/MainPage";
+import CoursePage from "./pages/CoursePage";
 
 function App() {
   return (
@@ -32,6 +33,21 @@ function App() {
               }
             />
 
+            {/* Course detail (linked from recommendations) */}
+            <Route
+              path="/courses/:code"
+              element={
+                <RequireAuth>
+                  <>
+                    <Header />
+                    <div className="max-w-[70rem] mx-auto">
+                      <CoursePage />
+                    </div>
+                  </>
+                </RequireAuth>
+              }
+            />
+
             {/* Redirect unknown routes */}
             <Route path="*" element={<Navigate to="/" replace />} />
           </Routes>
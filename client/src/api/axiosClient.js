@@ -1,7 +1,13 @@
 // client/src/api/axiosClient.js
 
 import axios from "axios";
-import { getAccessToken, clearAccessToken } from "./tokenStore";
+import {
+  getAccessToken,
+  setAccessToken,
+  clearAccessToken,
+  getRefreshToken,
+  clearRefreshToken,
+} from "./tokenStore";
 
 const API_BASE = "/api"; // handled by proxy (maps to localhost:8080)
 
@@ -26,17 +32,48 @@ api.interceptors.request.use(
   (error) => Promise.reject(error),
 );
 
+// 🔹 Renew the access token with the refresh token (one request at a time)
+let renewing = null;
+const renewAccessToken = () => {
+  if (!renewing) {
+    renewing = axios
+      .post(`${API_BASE}/tokens/renew_access`, { refresh_token: getRefreshToken() })
+      .then((res) => {
+        setAccessToken(res.data.access_token);
+        return res.data.access_token;
+      })
+      .finally(() => {
+        renewing = null;
+      });
+  }
+  return renewing;
+};
+
 // 🔹 Unified response & error handling
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
     const originalUrl = error.config?.url || "";
 
     // ✅ Skip auto-logout if it’s a download endpoint
     const isDownloadRoute = originalUrl.includes("/download");
+    const isAuthRoute = originalUrl.includes("/users/login") || originalUrl.includes("/tokens/renew_access");
+
+    // 🔄 Expired access token: renew once and replay the request
+    if (error.response?.status === 401 && !isAuthRoute && !error.config._retried && getRefreshToken()) {
+      try {
+        const token = await renewAccessToken();
+        error.config._retried = true;
+        error.config.headers.Authorization = `Bearer ${token}`;
+        return api(error.config);
+      } catch {
+        // fall through to logout
+      }
+    }
 
-    if (error.response?.status === 401 && !isDownloadRoute) {
+    if (error.response?.status === 401 && !isDownloadRoute && !isAuthRoute) {
       clearAccessToken();
+      clearRefreshToken();
       if (window.location.pathname !== "/login") {
         window.location.href = "/login";
       }
@@ -80,4 +117,26 @@ export const apiDownload = async (url, filename = "file.pdf") => {
   }
 };
 
+// 🔹 Background jobs: upload, recommendation, scholarship and summary
+// requests answer 202 { job_id } and run on the server's worker pool.
+// awaitJob polls GET /jobs/:id and resolves with the job result shaped like
+// a normal response ({ data }), so callers can treat both the same way.
+const JOB_POLL_MS = 1500;
+
+export const awaitJob = async (response) => {
+  if (response.status !== 202 || !response.data?.job_id) return response;
+
+  const jobId = response.data.job_id;
+  for (;;) {
+    await new Promise((resolve) => setTimeout(resolve, JOB_POLL_MS));
+    const { data: job } = await api.get(`/jobs/${jobId}`);
+    if (job.status === "succeeded") return { ...response, status: 200, data: job.result };
+    if (job.status === "failed") {
+      const err = new Error(job.error || "Job failed");
+      err.response = { status: 500, data: { error: job.error || "Job failed" } };
+      throw err;
+    }
+  }
+};
+
 export default api;
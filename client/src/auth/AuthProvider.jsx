@@ -7,6 +7,9 @@ import {
   setAccessToken,
   getAccessToken,
   clearAccessToken,
+  setRefreshToken,
+  getRefreshToken,
+  clearRefreshToken,
 } from "../api/tokenStore";
 import Swal from "sweetalert2";
 
@@ -35,10 +38,11 @@ export function AuthProvider({ children }) {
       setLoading(true);
 
       const res = await api.post("/users/login", { username, password });
-      const { access_token, user } = res.data;
+      const { access_token, refresh_token, user } = res.data;
 
-      // Save both token and user info locally
+      // Save tokens and user info locally
       setAccessToken(access_token);
+      setRefreshToken(refresh_token);
       localStorage.setItem("user", JSON.stringify(user));
       setUser(user);
 
@@ -92,8 +96,14 @@ export function AuthProvider({ children }) {
   };
 
 
-  const logout = () => {
+  const logout = async () => {
+    const refreshToken = getRefreshToken();
+    if (refreshToken) {
+      // Block the session server-side; log out locally even if this fails
+      await api.post("/users/logout", { refresh_token: refreshToken }).catch(() => {});
+    }
     clearAccessToken();
+    clearRefreshToken();
     localStorage.removeItem("user");
     setUser(null);
     navigate("/login");
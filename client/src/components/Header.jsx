@@ -3,6 +3,7 @@
 import { useEffect, useRef, useState } from "react";
 import { useAuth } from "../auth/AuthProvider";
 import { LogOut, HelpCircle, ChevronDown, User, BookOpen } from 'lucide-react';
+import NotificationBell from "./NotificationBell";
 
 export default function Header() {
 
@@ -64,6 +65,8 @@ export default function Header() {
                 <span className="hidden sm:inline">Help</span>
               </button> */}
 
+              <NotificationBell />
+
               {/* User Toggle */}
               <div className="relative">
                 <button
@@ -0,0 +1,98 @@
+// client/src/components/NotificationBell.jsx
+
+import { useEffect, useRef, useState } from "react";
+import { Bell } from "lucide-react";
+import api from "../api/axiosClient";
+
+const POLL_INTERVAL_MS = 60000;
+
+export default function NotificationBell() {
+  const [open, setOpen] = useState(false);
+  const [items, setItems] = useState([]);
+  const [unread, setUnread] = useState(0);
+  const panelRef = useRef(null);
+
+  const load = async () => {
+    try {
+      const res = await api.get("/notifications", { params: { limit: 20 } });
+      setItems(res.data.notifications || []);
+      setUnread(res.data.unread || 0);
+    } catch (err) {
+      console.error("Failed to load notifications", err);
+    }
+  };
+
+  useEffect(() => {
+    load();
+    const timer = setInterval(load, POLL_INTERVAL_MS);
+    return () => clearInterval(timer);
+  }, []);
+
+  useEffect(() => {
+    function handleClick(e) {
+      if (panelRef.current && !panelRef.current.contains(e.target)) setOpen(false);
+    }
+    if (open) document.addEventListener("mousedown", handleClick);
+    return () => document.removeEventListener("mousedown", handleClick);
+  }, [open]);
+
+  const markRead = async (n) => {
+    if (!n.read) {
+      await api.post(`/notifications/${n.id}/read`);
+      load();
+    }
+    if (n.link) window.open(n.link, "_blank", "noopener,noreferrer");
+  };
+
+  const markAllRead = async () => {
+    await api.post("/notifications/read_all");
+    load();
+  };
+
+  return (
+    <div className="relative" ref={panelRef}>
+      <button
+        onClick={() => setOpen((s) => !s)}
+        className="relative rounded-full p-2 text-gray-500 hover:bg-gray-100 hover:text-gray-900 transition-all"
+        aria-label="Notifications"
+      >
+        <Bell size={18} />
+        {unread > 0 && (
+          <span className="absolute -right-0.5 -top-0.5 flex h-4 min-w-4 items-center justify-center rounded-full bg-red-500 px-1 text-[10px] font-bold text-white">
+            {unread > 9 ? "9+" : unread}
+          </span>
+        )}
+      </button>
+
+      {open && (
+        <div className="absolute right-0 top-full mt-3 w-80 overflow-hidden rounded-2xl border border-gray-100 bg-white shadow-xl ring-1 ring-black/5">
+          <div className="flex items-center justify-between border-b border-gray-100 px-4 py-3">
+            <h3 className="text-sm font-semibold text-gray-900">Notifications</h3>
+            {unread > 0 && (
+              <button onClick={markAllRead} className="text-xs font-medium text-indigo-600 hover:underline">
+                Mark all read
+              </button>
+            )}
+          </div>
+          <ul className="max-h-96 overflow-y-auto">
+            {items.length === 0 && (
+              <li className="px-4 py-6 text-center text-sm text-gray-500">No notifications yet</li>
+            )}
+            {items.map((n) => (
+              <li key={n.id}>
+                <button
+                  onClick={() => markRead(n)}
+                  className={`w-full px-4 py-3 text-left hover:bg-gray-50 ${n.read ? "" : "bg-indigo-50/50"}`}
+                >
+                  <p className={`text-sm ${n.read ? "text-gray-600" : "font-semibold text-gray-900"}`}>{n.title}</p>
+                  {n.body && <p className="mt-0.5 text-xs text-gray-500">{n.body}</p>}
+                  <p className="mt-1 text-[10px] text-gray-400">{new Date(n.created_at).toLocaleString()}</p>
+                </button>
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+    </div>
+  );
+}
@@ -14,8 +14,9 @@ import {
   FileText,
   X, // Added X icon for delete button
 } from "lucide-react";
-import api, { apiDownload } from "../api/axiosClient";
+import api, { apiDownload, awaitJob } from "../api/axiosClient";
 import Swal from "sweetalert2";
+import { Link } from "react-router-dom";
 
 // CHANGED: Accept 'recommendations' prop from MainPage
 export default function RecommendationsSection({ uploadedDocuments, recommendations }) {
@@ -68,7 +69,7 @@ export default function RecommendationsSection({ uploadedDocuments, recommendati
     if (generatingSummary || fetchingScholarships) return;
     setGeneratingSummary(true);
     try {
-      const res = await api.post("/summaries/generate");
+      const res = await awaitJob(await api.post("/summaries/generate"));
       setAiSummary(res.data.summary_text || res.data.text || "");
       await Swal.fire({
         icon: "success",
@@ -89,7 +90,7 @@ export default function RecommendationsSection({ uploadedDocuments, recommendati
     if (fetchingScholarships || generatingSummary) return;
     setFetchingScholarships(true);
     try {
-      const res = await api.post("/scholarships/generate");
+      const res = await awaitJob(await api.post("/scholarships/generate"));
       const list = Array.isArray(res.data?.scholarships)
         ? res.data.scholarships
         : [];
@@ -394,7 +395,15 @@ const CourseList = ({ courses, onDelete }) => (
 
           <div className="flex items-start justify-between gap-4">
             <div className="flex-1 pr-6"> {/* Added pr-6 to give space for the delete button */}
-              <h3 className="font-semibold text-gray-900">{course.title}</h3>
+              <h3 className="font-semibold text-gray-900">
+                {course.code ? (
+                  <Link to={`/courses/${course.code}`} className="hover:text-blue-700 hover:underline">
+                    {course.title}
+                  </Link>
+                ) : (
+                  course.title
+                )}
+              </h3>
               <p className="mt-1 text-sm text-gray-500">
                 {course.description}
               </p>
@@ -437,13 +446,24 @@ const ScholarshipList = ({ scholarships, loading }) => {
     <div className="grid gap-4">
       {scholarships.map((sch, idx) => (
         <div
-          key={idx}
+          key={sch.id || idx}
           className="rounded-lg border border-gray-300 bg-white p-6 hover:shadow-md transition-shadow"
         >
           <div className="flex items-start justify-between gap-4">
             <div className="flex-1">
               <h3 className="font-semibold text-gray-900">{sch.title}</h3>
               <p className="mt-1 text-sm text-gray-500">{sch.description}</p>
+              {(sch.provider || sch.amount || sch.deadline) && (
+                <p className="mt-1 text-xs text-gray-600">
+                  {[
+                    sch.provider,
+                    sch.amount && `${sch.amount} ${sch.currency || ""}`.trim(),
+                    sch.deadline && `Deadline ${sch.deadline}`,
+                  ]
+                    .filter(Boolean)
+                    .join(" · ")}
+                </p>
+              )}
               {sch.link && (
                 <a
                   href={sch.link}
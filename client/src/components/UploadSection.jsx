@@ -2,7 +2,7 @@
 
 import React, { useState } from "react"
 import { Upload, FileText, CheckCircle } from "lucide-react"
-import api from "../api/axiosClient"
+import api, { awaitJob } from "../api/axiosClient"
 
 export default function UploadSection({ onUpload }) {
 	const [dragActive, setDragActive] = useState(false)
@@ -39,11 +39,11 @@ export default function UploadSection({ onUpload }) {
 		try {
 			const form = new FormData()
 			form.append("file", pdfs[0])
-			const up = await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } })
+			const up = await awaitJob(await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } }))
 			const transcriptId = up.data.id
 
 			// create recommendation
-			const reco = await api.post("/recommendations", { transcript_id: transcriptId })
+			const reco = await awaitJob(await api.post("/recommendations", { transcript_id: transcriptId }))
 			
 			// --- PHASE 3: CONTEXT KEY STORAGE UPDATE ---
 			// Store the Recommendation ID, which holds ALL context (transcript, courses, etc.)
@@ -7,7 +7,9 @@ import ReactMarkdown from "react-markdown";
 import remarkGfm from "remark-gfm";
 import { Prism as SyntaxHighlighter } from "react-syntax-highlighter";
 import { oneDark } from "react-syntax-highlighter/dist/esm/styles/prism";
-import { Send, Bot, Loader2, MessageCircle, X, ChevronRight } from "lucide-react";
+import { Send, Bot, Loader2, MessageCircle, X, ChevronRight, Square } from "lucide-react";
+
+const maxResumeAttempts = 3;
 
 export default function ChatDrawer() {
 	const [open, setOpen] = useState(false);
@@ -15,89 +17,176 @@ export default function ChatDrawer() {
 	const [input, setInput] = useState("");
 	const [loading, setLoading] = useState(false);
 	const chatEndRef = useRef(null);
+	const activeStream = useRef(null); // { id, controller } of the reply being streamed
 
 	useEffect(() => {
 		chatEndRef.current?.scrollIntoView({ behavior: "smooth" });
 	}, [messages, loading]);
 
+	const authHeaders = () => ({
+		"Content-Type": "application/json",
+		Authorization: `Bearer ${localStorage.getItem("access_token")}`,
+	});
+
+	// The conversation is pinned to the latest recommendation, which holds all
+	// academic context. A new recommendation starts a new conversation.
+	const storedConversation = () => {
+		try {
+			const saved = JSON.parse(localStorage.getItem("chat_conversation") || "null");
+			if (saved && saved.reco === localStorage.getItem("last_reco_id")) return saved.id;
+		} catch {
+			// fall through and start a new conversation
+		}
+		return null;
+	};
+
+	const createConversation = async () => {
+		const reco = localStorage.getItem("last_reco_id");
+		const body = reco ? { recommendation_id: Number(reco) } : {};
+		const res = await fetch("/api/conversations", {
+			method: "POST",
+			headers: authHeaders(),
+			body: JSON.stringify(body),
+		});
+		if (!res.ok) throw new Error(`create conversation failed: ${res.status}`);
+		const conv = await res.json();
+		localStorage.setItem("chat_conversation", JSON.stringify({ id: conv.id, reco }));
+		return conv.id;
+	};
+
+	// Load the saved history when the drawer opens.
+	useEffect(() => {
+		if (!open || messages.length > 0) return;
+		const id = storedConversation();
+		if (!id) return;
+		fetch(`/api/conversations/${id}/messages`, { headers: authHeaders() })
+			.then((res) => {
+				if (res.status === 404 || res.status === 403) {
+					localStorage.removeItem("chat_conversation");
+					return [];
+				}
+				return res.ok ? res.json() : [];
+			})
+			.then((history) => setMessages(history.map((m) => ({ role: m.role, content: m.content }))))
+			.catch((err) => console.error("Load chat history error:", err));
+	}, [open]);
+
+	const handleNewChat = () => {
+		localStorage.removeItem("chat_conversation");
+		setMessages([]);
+	};
+
 	const handleSend = async (e) => {
 		e.preventDefault();
 		const text = input.trim();
 		if (!text || loading) return;
 
-		const nextMessages = [...messages, { role: "user", content: text }];
-		setMessages(nextMessages);
+		setMessages((prev) => [...prev, { role: "user", content: text }]);
 		setInput("");
 		setLoading(true);
-        
-        // --- PHASE 3: CONTEXT ID RETRIEVAL AND HEADER SETUP ---
-        // 1. Retrieve the Recommendation ID, which holds all academic context
-        const recommendationId = localStorage.getItem("last_reco_id");
-        
-        // 2. Prepare base headers
-		const headers = {
-			"Content-Type": "application/json",
-            // The existing authentication header
-			Authorization: `Bearer ${localStorage.getItem("access_token")}`,
-		}
-        
-        // 3. Inject the custom context header if the ID exists
-        if (recommendationId) {
-            headers["X-Recommendation-ID"] = recommendationId;
-        }
-        // --- END PHASE 3 UPDATE ---
 
+		const updateLast = (fn) =>
+			setMessages((prev) => {
+				const updated = [...prev];
+				updated[updated.length - 1] = fn({ ...updated[updated.length - 1] });
+				return updated;
+			});
+
+		// Reply events carry JSON: delta text, tool calls and results, cited
+		// sources, token usage, then saved and done (or error).
+		const handleEvent = (event, data) => {
+			switch (event) {
+				case "delta":
+					updateLast((last) => ({ ...last, content: last.content + data.text }));
+					break;
+				case "tool_result":
+					// Actions the assistant took, listed above its reply
+					updateLast((last) => ({
+						...last,
+						tools: [...(last.tools || []), { id: data.id, name: data.name, status: data.status, error: data.error }],
+					}));
+					break;
+				case "citation":
+					// Sources cited as [n] in the reply, linked below it
+					updateLast((last) => ({ ...last, citations: [...(last.citations || []), data] }));
+					break;
+				case "error":
+					updateLast((last) => ({
+						...last,
+						content: last.content || (data.message === "reply cancelled" ? "Stopped." : "Sorry, something went wrong."),
+					}));
+					break;
+				default:
+			}
+		};
+
+		const controller = new AbortController();
 		try {
-			const res = await fetch("/api/chat/stream", {
+			// The server keeps the history; only the new message is sent.
+			const conversationId = storedConversation() || (await createConversation());
+			const url = `/api/conversations/${conversationId}/messages`;
+			let res = await fetch(url, {
 				method: "POST",
-                // VITAL: Use the updated headers object
-				headers: headers, 
-				body: JSON.stringify({ messages: nextMessages }),
+				headers: authHeaders(),
+				body: JSON.stringify({ content: text }),
+				signal: controller.signal,
 			});
+			if (!res.ok) throw new Error(`send failed: ${res.status}`);
+			activeStream.current = { id: res.headers.get("X-Stream-ID"), controller };
+			setMessages((prev) => [...prev, { role: "assistant", content: "" }]);
+
+			// A dropped connection resumes after the last event received
+			let lastEventId = "";
+			let finished = false;
+			for (let attempt = 0; ; attempt++) {
+				try {
+					await readEvents(res, (id, event, data) => {
+						if (id) lastEventId = id;
+						if (event === "done" || event === "error") finished = true;
+						handleEvent(event, data);
+					});
+				} catch (err) {
+					if (err.name === "AbortError") throw err;
+				}
+				if (finished) break;
+				if (!lastEventId || attempt >= maxResumeAttempts) throw new Error("reply stream interrupted");
 
-			const reader = res.body.getReader();
-			const decoder = new TextDecoder("utf-8");
-			let aiMessage = { role: "assistant", content: "" };
-			setMessages((prev) => [...prev, aiMessage]);
-
-			while (true) {
-				const { done, value } = await reader.read();
-				if (done) break;
-				const chunk = decoder.decode(value, { stream: true });
-
-				chunk.split("\n").forEach((line) => {
-					if (line.startsWith("data: ")) {
-						const text = line.replace("data: ", "").trim();
-						if (text === "[DONE]") return;
-						if (text.length > 0) {
-							setMessages((prev) => {
-								const updated = [...prev];
-								const last = { ...updated[updated.length - 1] };
-								const clean = text
-									.replace(/\\n/g, "\n")
-									.replace(/\\t/g, "  ")
-									.replace(/\s+/g, " ")
-									.replace(/\*{3}/g, "**");
-
-								last.content = (last.content + " " + clean).trim();
-								updated[updated.length - 1] = last;
-								return updated;
-							});
-						}
-					}
+				await new Promise((resolve) => setTimeout(resolve, 1000));
+				res = await fetch(url, {
+					method: "POST",
+					headers: { ...authHeaders(), "Last-Event-ID": lastEventId },
+					signal: controller.signal,
 				});
+				if (!res.ok) throw new Error(`resume failed: ${res.status}`);
 			}
 		} catch (err) {
-			console.error("Streaming chat error:", err);
-			setMessages((prev) => [
-				...prev,
-				{ role: "assistant", content: "Sorry, something went wrong." },
-			]);
+			if (err.name !== "AbortError") {
+				console.error("Streaming chat error:", err);
+				setMessages((prev) => [
+					...prev,
+					{ role: "assistant", content: "Sorry, something went wrong." },
+				]);
+			}
 		} finally {
+			activeStream.current = null;
 			setLoading(false);
 		}
 	};
 
+	// Stops the reply on the server, then drops the connection.
+	const handleStop = async () => {
+		const stream = activeStream.current;
+		if (!stream) return;
+		try {
+			if (stream.id) {
+				await fetch(`/api/chat/streams/${stream.id}`, { method: "DELETE", headers: authHeaders() });
+			}
+		} catch (err) {
+			console.error("Stop chat error:", err);
+		}
+		stream.controller.abort();
+	};
+
 	return (
 		<>
 			{!open && (
@@ -137,16 +226,21 @@ export default function ChatDrawer() {
 					<h2 className="text-lg font-semibold flex items-center gap-2">
 						<Bot size={18} /> EduSphere AI
 					</h2>
-					<button onClick={() => setOpen(false)}>
-						<X className="w-5 h-5" />
-					</button>
+					<div className="flex items-center gap-3">
+						<button onClick={handleNewChat} className="text-xs underline" aria-label="New chat">
+							New chat
+						</button>
+						<button onClick={() => setOpen(false)}>
+							<X className="w-5 h-5" />
+						</button>
+					</div>
 				</div>
 
 				{/* Chat Area */}
 				<div className="flex flex-col h-[calc(100%-140px)]">
 					<div className="flex-1 overflow-y-auto p-4 space-y-4">
 						{messages.map((msg, idx) => (
-							<ChatBubble key={idx} role={msg.role} content={msg.content} />
+							<ChatBubble key={idx} role={msg.role} content={msg.content} citations={msg.citations} tools={msg.tools} />
 						))}
 
 						{loading && (
@@ -175,6 +269,16 @@ export default function ChatDrawer() {
 							  bg-white/60 backdrop-blur-md
 							  focus:outline-none focus:ring-2 focus:ring-indigo-500"
 						/>
+						{loading && (
+							<button
+								type="button"
+								onClick={handleStop}
+								className="text-gray-600 hover:text-red-600 rounded-xl px-2 py-2"
+								aria-label="Stop"
+							>
+								<Square className="w-4 h-4" />
+							</button>
+						)}
 						<button
 							type="submit"
 							disabled={loading || !input.trim()}
@@ -195,8 +299,46 @@ export default function ChatDrawer() {
 	);
 }
 
+/* --- Reads a reply stream: calls onEvent(id, event, data) for each event
+   with JSON data, skipping keep-alive comments. Frames split across chunks
+   are buffered until complete. --- */
+async function readEvents(res, onEvent) {
+	const reader = res.body.getReader();
+	const decoder = new TextDecoder("utf-8");
+	let buffer = "";
+	while (true) {
+		const { done, value } = await reader.read();
+		if (done) return;
+		buffer += decoder.decode(value, { stream: true });
+
+		let end;
+		while ((end = buffer.indexOf("\n\n")) >= 0) {
+			const frame = buffer.slice(0, end);
+			buffer = buffer.slice(end + 2);
+
+			let id = "";
+			let event = "message";
+			let data = "";
+			for (const line of frame.split("\n")) {
+				if (line.startsWith("id: ")) id = line.slice(4);
+				else if (line.startsWith("event: ")) event = line.slice(7);
+				else if (line.startsWith("data: ")) data += line.slice(6);
+			}
+			if (!data) continue;
+
+			let parsed;
+			try {
+				parsed = JSON.parse(data);
+			} catch {
+				continue;
+			}
+			onEvent(id, event, parsed);
+		}
+	}
+}
+
 /* --- Chat bubble stays the same --- */
-function ChatBubble({ role, content }) {
+function ChatBubble({ role, content, citations, tools }) {
 	const isUser = role === "user";
 
 	return (
@@ -213,6 +355,16 @@ function ChatBubble({ role, content }) {
 						<span className="font-medium text-xs">EduSphere AI</span>
 					</div>
 				)}
+				{tools?.length > 0 && (
+					<div className="mb-2 text-xs text-gray-500 space-y-0.5">
+						{tools.map((t) => (
+							<div key={t.id} title={t.error}>
+								🔧 {t.name.replace(/_/g, " ")}
+								<span className={t.status === "ok" ? "text-green-600" : "text-red-500"}> · {t.status}</span>
+							</div>
+						))}
+					</div>
+				)}
 				<div className="prose prose-sm max-w-none">
 					<ReactMarkdown
 						remarkPlugins={[remarkGfm]}
@@ -242,6 +394,23 @@ function ChatBubble({ role, content }) {
 						{content || ""}
 					</ReactMarkdown>
 				</div>
+				{citations?.length > 0 && (
+					<div className="mt-2 pt-2 border-t border-gray-200 text-xs text-gray-600 space-y-1">
+						{citations.map((c) => (
+							<div key={c.marker} title={c.snippet}>
+								[{c.marker}]{" "}
+								{c.url ? (
+									<a href={c.url} target="_blank" rel="noopener noreferrer" className="text-indigo-600 underline">
+										{c.title}
+									</a>
+								) : (
+									c.title
+								)}
+								<span className="text-gray-400"> · {c.source}</span>
+							</div>
+						))}
+					</div>
+				)}
 			</div>
 		</div>
 	);
@@ -5,7 +5,7 @@ import PreferenceInput from './PreferenceInput';
 import { BarChart3, Brain, ChartSpline, LineChart, ScanSearch } from 'lucide-react';
 import ChatDrawer from './ChatDrawer';
 import UploadDocument from './UploadDocument';
-import api from '../../api/axiosClient';
+import api, { awaitJob } from '../../api/axiosClient';
 import RecommendationsSection from '../RecommendationsSection';
 
 export default function MainPage() {
@@ -53,16 +53,16 @@ export default function MainPage() {
             // 1. Upload Transcript
             const form = new FormData()
             form.append("file", fileForAnalyze)
-            const up = await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } })
+            const up = await awaitJob(await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } }))
             const transcriptId = up.data.id
 
             const userPreference = preference; // Capture the state
 
             // 2. Create Recommendation (Phase 2 Logic)
-            const reco = await api.post("/recommendations", { 
+            const reco = await awaitJob(await api.post("/recommendations", { 
                 transcript_id: transcriptId,
                 preference: userPreference 
-            })
+            }))
             
             // 3. Save IDs for Phase 3 (Chat context)
             localStorage.setItem("last_reco_id", reco.data.id);
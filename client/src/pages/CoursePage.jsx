@@ -0,0 +1,164 @@
+// client/src/pages/CoursePage.jsx
+
+import React, { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import { ArrowLeft, BookOpen, Loader2, Link2 } from "lucide-react";
+import api from "../api/axiosClient";
+
+const RELATION_LABELS = {
+  prerequisite: "Prerequisite",
+  follow_up: "Builds on this course",
+};
+
+export default function CoursePage() {
+  const { code } = useParams();
+  const [course, setCourse] = useState(null);
+  const [related, setRelated] = useState([]);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState("");
+
+  useEffect(() => {
+    let cancelled = false;
+    setLoading(true);
+    setError("");
+
+    Promise.all([api.get(`/courses/${code}`), api.get(`/courses/${code}/related`)])
+      .then(([courseRes, relatedRes]) => {
+        if (cancelled) return;
+        setCourse(courseRes.data);
+        setRelated(Array.isArray(relatedRes.data) ? relatedRes.data : []);
+      })
+      .catch((err) => {
+        if (cancelled) return;
+        console.error("Failed to load course:", err);
+        setError(err.response?.data?.error || "Failed to load course.");
+      })
+      .finally(() => {
+        if (!cancelled) setLoading(false);
+      });
+
+    return () => {
+      cancelled = true;
+    };
+  }, [code]);
+
+  if (loading) {
+    return (
+      <div className="flex items-center justify-center py-16 text-gray-600">
+        <Loader2 className="h-5 w-5 animate-spin mr-2 text-blue-600" />
+        Loading course...
+      </div>
+    );
+  }
+
+  if (error || !course) {
+    return (
+      <div className="py-10 text-center">
+        <p className="text-red-600">⚠️ {error || "Course not found."}</p>
+        <BackLink />
+      </div>
+    );
+  }
+
+  return (
+    <div className="space-y-8 py-8 px-4">
+      <BackLink />
+
+      <div className="rounded-lg border border-gray-300 bg-white p-6">
+        <div className="flex items-start justify-between gap-4">
+          <div>
+            <p className="text-sm font-semibold text-blue-600">{course.code}</p>
+            <h1 className="text-2xl font-bold text-gray-900">{course.name}</h1>
+            {course.organiser && (
+              <p className="mt-1 text-sm text-gray-500">{course.organiser}</p>
+            )}
+          </div>
+          {course.archived_at && (
+            <span className="rounded-full bg-gray-100 px-3 py-1 text-xs font-semibold text-gray-600">
+              No longer offered
+            </span>
+          )}
+        </div>
+
+        <dl className="mt-6 grid gap-4 md:grid-cols-2 text-sm">
+          <Detail label="Language" value={course.language} />
+          <Detail label="Grading" value={course.grading_scale} />
+          <Detail label="Teacher" value={course.teacher_name} />
+          <Detail label="Prerequisites" value={course.prerequisites} />
+        </dl>
+
+        {course.learning_outcomes && (
+          <div className="mt-6">
+            <h2 className="font-semibold text-gray-900 mb-1">Learning outcomes</h2>
+            <p className="text-sm text-gray-700 whitespace-pre-line">
+              {course.learning_outcomes}
+            </p>
+          </div>
+        )}
+
+        {course.course_link && (
+          <a
+            href={course.course_link}
+            target="_blank"
+            rel="noopener noreferrer"
+            className="mt-4 inline-flex items-center gap-1 text-sm text-blue-600 hover:text-blue-800 hover:underline"
+          >
+            <Link2 className="w-4 h-4" /> Course page →
+          </a>
+        )}
+      </div>
+
+      <div>
+        <h2 className="mb-4 text-xl font-bold text-gray-900">Related Courses</h2>
+        {related.length === 0 ? (
+          <p className="text-gray-500 text-sm">No related courses found.</p>
+        ) : (
+          <div className="grid gap-3">
+            {related.map((r) => (
+              <Link
+                key={r.course.id}
+                to={`/courses/${r.course.code}`}
+                className="flex items-start justify-between gap-4 rounded-lg border border-gray-300 bg-white p-4 hover:shadow-md transition-shadow"
+              >
+                <div>
+                  <p className="font-medium text-gray-900">
+                    <span className="text-blue-600">{r.course.code}</span> {r.course.name}
+                  </p>
+                  <p className="mt-1 text-xs text-gray-500">
+                    {[
+                      RELATION_LABELS[r.relation],
+                      r.shared_prerequisites?.length > 0 &&
+                        `Shared prerequisites: ${r.shared_prerequisites.join(", ")}`,
+                      r.outcome_overlap > 0 &&
+                        `${Math.round(r.outcome_overlap * 100)}% outcome overlap`,
+                    ]
+                      .filter(Boolean)
+                      .join(" · ")}
+                  </p>
+                </div>
+                <BookOpen className="h-5 w-5 text-gray-400 shrink-0" />
+              </Link>
+            ))}
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
+
+const BackLink = () => (
+  <Link
+    to="/"
+    className="inline-flex items-center gap-1 text-sm text-blue-600 hover:text-blue-800"
+  >
+    <ArrowLeft className="w-4 h-4" /> Back to dashboard
+  </Link>
+);
+
+const Detail = ({ label, value }) =>
+  value ? (
+    <div>
+      <dt className="text-gray-500">{label}</dt>
+      <dd className="text-gray-900">{value}</dd>
+    </div>
+  ) : null;
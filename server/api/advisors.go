@@ -0,0 +1,172 @@
+// server/api/advisors.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// ---------------------------
+// Request Structs
+// ---------------------------
+
+// updateUserRoleRequest sets the role of a user
+// @Description Update user role request payload
+type updateUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=student advisor admin"`
+}
+
+// assignStudentRequest assigns a student to an advisor
+// @Description Assign student request payload
+type assignStudentRequest struct {
+	StudentUsername string `json:"student_username" validate:"required"`
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// checkReadAccess allows reading owner's transcripts, recommendations and
+// summaries to the owner, admins and advisors the owner is assigned to.
+// A denial is a 403 *fiber.Error.
+func (s *Server) checkReadAccess(ctx context.Context, payload *token.Payload, owner string) error {
+	switch {
+	case payload.Username == owner, payload.Role == util.AdminRole:
+		return nil
+	case payload.Role == util.AdvisorRole:
+		assigned, err := s.store.IsAdvisorOf(ctx, db.IsAdvisorOfParams{
+			AdvisorUsername: payload.Username,
+			StudentUsername: owner,
+		})
+		if err != nil {
+			return err
+		}
+		if assigned {
+			return nil
+		}
+	}
+	return fiber.NewError(fiber.StatusForbidden, "forbidden")
+}
+
+// getUserWithRole loads a user and checks it has the given role (400 if not,
+// 404 if there is no such user).
+func (s *Server) getUserWithRole(ctx context.Context, username, role string) (db.User, error) {
+	user, err := s.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.User{}, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("user %q not found", username))
+		}
+		return db.User{}, err
+	}
+	if user.Role != role {
+		return db.User{}, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("user %q is not a %s", username, role))
+	}
+	return user, nil
+}
+
+// ---------------------------
+// Advisor Handlers
+// ---------------------------
+
+// GET /api/advisor/students
+// Students assigned to the calling advisor.
+func (s *Server) listAdvisorStudents(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	students, err := s.store.ListAdvisorStudents(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	resp := make([]userResponse, 0, len(students))
+	for _, student := range students {
+		resp = append(resp, newUserResponse(student))
+	}
+	return c.JSON(resp)
+}
+
+// ---------------------------
+// Admin Handlers
+// ---------------------------
+
+// PUT /api/admin/users/:username/role
+// The new role takes effect on the user's next login or token renewal.
+func (s *Server) updateUserRole(c *fiber.Ctx) error {
+	var req updateUserRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	user, err := s.store.UpdateUserRole(c.Context(), db.UpdateUserRoleParams{
+		Username: c.Params("username"),
+		Role:     req.Role,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("user not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newUserResponse(user))
+}
+
+// POST /api/admin/advisors/:username/students
+func (s *Server) assignAdvisorStudent(c *fiber.Ctx) error {
+	var req assignStudentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	advisor, err := s.getUserWithRole(c.Context(), c.Params("username"), util.AdvisorRole)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+	student, err := s.getUserWithRole(c.Context(), req.StudentUsername, util.StudentRole)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	err = s.store.AssignAdvisorStudent(c.Context(), db.AssignAdvisorStudentParams{
+		AdvisorUsername: advisor.Username,
+		StudentUsername: student.Username,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"advisor_username": advisor.Username,
+		"student_username": student.Username,
+	})
+}
+
+// DELETE /api/admin/advisors/:username/students/:student
+func (s *Server) unassignAdvisorStudent(c *fiber.Ctx) error {
+	removed, err := s.store.UnassignAdvisorStudent(c.Context(), db.UnassignAdvisorStudentParams{
+		AdvisorUsername: c.Params("username"),
+		StudentUsername: c.Params("student"),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if removed == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("assignment not found")))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
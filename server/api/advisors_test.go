@@ -0,0 +1,372 @@
+// server/api/advisors_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTranscriptAccess(t *testing.T) {
+	student := util.RandomOwner()
+	transcript := db.Transcript{ID: 9, UserUsername: student, FilePath: "uploads/t.pdf"}
+
+	testCases := []struct {
+		name       string
+		caller     string
+		role       string
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name:   "Owner",
+			caller: student,
+			role:   util.StudentRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "AssignedAdvisor",
+			caller: "advisor1",
+			role:   util.AdvisorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Eq(db.IsAdvisorOfParams{AdvisorUsername: "advisor1", StudentUsername: student})).
+					Times(1).Return(true, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "UnassignedAdvisor",
+			caller: "advisor2",
+			role:   util.AdvisorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Admin",
+			caller: "root",
+			role:   util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "OtherStudent",
+			caller: "someoneelse",
+			role:   util.StudentRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "AdvisorLookupError",
+			caller: "advisor1",
+			role:   util.AdvisorRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(1).Return(false, sql.ErrConnDone)
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return(transcript, nil)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/transcripts/%d", transcript.ID), nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, tc.caller, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestDeleteCourseFromRecommendationAccess(t *testing.T) {
+	student := util.RandomOwner()
+	reco := db.Recommendation{
+		ID:           7,
+		UserUsername: student,
+		Payload:      []byte(`{"courses":[{"type":"course","title":"Machine Learning","code":"TIES454","course_id":3}]}`),
+	}
+
+	testCases := []struct {
+		name       string
+		caller     string
+		role       string
+		wantSaved  int
+		wantStatus int
+	}{
+		{name: "Owner", caller: student, role: util.StudentRole, wantSaved: 1, wantStatus: http.StatusOK},
+		{name: "Advisor", caller: "advisor1", role: util.AdvisorRole, wantStatus: http.StatusForbidden},
+		{name: "Admin", caller: "root", role: util.AdminRole, wantStatus: http.StatusForbidden},
+		{name: "OtherStudent", caller: "someoneelse", role: util.StudentRole, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(reco.ID)).Times(1).Return(reco, nil)
+			store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().UpdateRecommendationPayload(gomock.Any(), gomock.Any()).Times(tc.wantSaved).Return(reco, nil)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/recommendations/%d/courses/3", reco.ID), nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, tc.caller, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAdvisorStudentRoutes(t *testing.T) {
+	advisor := util.RandomOwner()
+	student := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		role       string
+		path       string
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name: "ListStudents",
+			role: util.AdvisorRole,
+			path: "/api/advisor/students",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAdvisorStudents(gomock.Any(), gomock.Eq(advisor)).Times(1).
+					Return([]db.User{{Username: student, Role: util.StudentRole}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "StudentSummaries",
+			role: util.AdvisorRole,
+			path: fmt.Sprintf("/api/advisor/students/%s/summaries", student),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Eq(db.IsAdvisorOfParams{AdvisorUsername: advisor, StudentUsername: student})).
+					Times(1).Return(true, nil)
+				store.EXPECT().ListSummaries(gomock.Any(), gomock.Eq(student)).Times(1).Return([]db.Summary{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "UnassignedStudentRecommendations",
+			role: util.AdvisorRole,
+			path: fmt.Sprintf("/api/advisor/students/%s/recommendations", student),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().IsAdvisorOf(gomock.Any(), gomock.Any()).Times(1).Return(false, nil)
+				store.EXPECT().ListRecommendations(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "StudentRole",
+			role: util.StudentRole,
+			path: fmt.Sprintf("/api/advisor/students/%s/transcripts", student),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTranscripts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, advisor, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	admin := util.RandomOwner()
+	advisor := util.RandomOwner()
+	student := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		role       string
+		method     string
+		path       string
+		body       any
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name:   "UpdateRole",
+			role:   util.AdminRole,
+			method: http.MethodPut,
+			path:   fmt.Sprintf("/api/admin/users/%s/role", advisor),
+			body:   updateUserRoleRequest{Role: util.AdvisorRole},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Eq(db.UpdateUserRoleParams{Username: advisor, Role: util.AdvisorRole})).
+					Times(1).Return(db.User{Username: advisor, Role: util.AdvisorRole}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "UpdateRoleUnsupported",
+			role:   util.AdminRole,
+			method: http.MethodPut,
+			path:   fmt.Sprintf("/api/admin/users/%s/role", advisor),
+			body:   updateUserRoleRequest{Role: "banker"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "UpdateRoleNotAdmin",
+			role:   util.AdvisorRole,
+			method: http.MethodPut,
+			path:   fmt.Sprintf("/api/admin/users/%s/role", admin),
+			body:   updateUserRoleRequest{Role: util.AdminRole},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "AssignStudent",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students", advisor),
+			body:   assignStudentRequest{StudentUsername: student},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(advisor)).Times(1).Return(db.User{Username: advisor, Role: util.AdvisorRole}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(student)).Times(1).Return(db.User{Username: student, Role: util.StudentRole}, nil)
+				store.EXPECT().AssignAdvisorStudent(gomock.Any(), gomock.Eq(db.AssignAdvisorStudentParams{AdvisorUsername: advisor, StudentUsername: student})).
+					Times(1).Return(nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "AssignMissingStudent",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students", advisor),
+			body:   assignStudentRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AssignAdvisorStudent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "AssignToNonAdvisor",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students", student),
+			body:   assignStudentRequest{StudentUsername: advisor},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(student)).Times(1).Return(db.User{Username: student, Role: util.StudentRole}, nil)
+				store.EXPECT().AssignAdvisorStudent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "AssignUnknownStudent",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students", advisor),
+			body:   assignStudentRequest{StudentUsername: "ghost"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(advisor)).Times(1).Return(db.User{Username: advisor, Role: util.AdvisorRole}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq("ghost")).Times(1).Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().AssignAdvisorStudent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "UnassignStudent",
+			role:   util.AdminRole,
+			method: http.MethodDelete,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students/%s", advisor, student),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UnassignAdvisorStudent(gomock.Any(), gomock.Eq(db.UnassignAdvisorStudentParams{AdvisorUsername: advisor, StudentUsername: student})).
+					Times(1).Return(int64(1), nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "UnassignMissing",
+			role:   util.AdminRole,
+			method: http.MethodDelete,
+			path:   fmt.Sprintf("/api/admin/advisors/%s/students/%s", advisor, student),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UnassignAdvisorStudent(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			req := httptest.NewRequest(tc.method, tc.path, &body)
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, admin, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
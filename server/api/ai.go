@@ -3,106 +3,110 @@
 package api
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
-	"net/http"
-	"time"
+	"strings"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
 )
 
 // aiMessage is the internal representation we use for chat messages.
+// Assistant messages that call tools carry ToolCalls; the "tool" message
+// answering a call carries its ToolCallID.
 type aiMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string        `json:"role"`
+	Content    string        `json:"content"`
+	ToolCalls  []llmToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
 }
 
-// openAIChatRequest models the payload sent to OpenAI's chat completions endpoint.
-type openAIChatRequest struct {
-	Model          string            `json:"model"`
-	Messages       []aiMessage       `json:"messages"`
-	Stream         bool              `json:"stream"`
-	ResponseFormat map[string]string `json:"response_format,omitempty"`
+// llmTool offers a function to the model, in OpenAI's tools format.
+type llmTool struct {
+	Type     string          `json:"type"` // always "function"
+	Function llmToolFunction `json:"function"`
 }
 
-// openAIChatResponse models the non-streaming response structure from OpenAI.
-type openAIChatResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error,omitempty"`
+type llmToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Parameters  json.RawMessage `json:"parameters"` // JSON Schema of the arguments
 }
 
-// ------------------------------------------------------------------
-// callOpenAIChat: shared helper that hits OpenAI /v1/chat/completions
-// ------------------------------------------------------------------
-func callOpenAIChat(ctx context.Context, apiKey, model string, messages []aiMessage, expectJSON bool) (string, error) {
-	if apiKey == "" {
-		return "", fmt.Errorf("missing OpenAI API key")
-	}
-	if model == "" {
-		model = "gpt-4o-mini"
-	}
+// llmToolCall is a function call made by the model. Arguments is a JSON
+// object as text, which the model may get wrong.
+type llmToolCall struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Function llmFunctionCall `json:"function"`
+}
 
-	reqBody := openAIChatRequest{
-		Model:    model,
-		Messages: messages,
-		Stream:   false,
-	}
-	if expectJSON {
-		reqBody.ResponseFormat = map[string]string{"type": "json_object"}
-	}
+type llmFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
 
-	b, _ := json.Marshal(reqBody)
+// llmUsage is the token usage a provider reports for one completion.
+type llmUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
 
-	httpClient := &http.Client{
-		Timeout: 480 * time.Second,
-	}
+// llmStreamResult is what a streamed completion returns besides its text.
+type llmStreamResult struct {
+	ToolCalls []llmToolCall
+	Usage     *llmUsage // nil when the provider reported none
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(b))
-	if err != nil {
-		return "", fmt.Errorf("failed to create OpenAI request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+// LLM provider names accepted in LLM_PROVIDER.
+const (
+	llmProviderOpenAI           = "openai"
+	llmProviderOpenAICompatible = "openai_compatible"
+	llmProviderFake             = "fake"
+)
 
-	log.Printf("[AI] Sending request to OpenAI: model=%s, len(messages)=%d", model, len(messages))
+// LLMProvider is the single entry point every AI feature uses to talk to a
+// chat model. Implementations must be safe for concurrent use.
+type LLMProvider interface {
+	// Name identifies the provider in logs.
+	Name() string
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to reach OpenAI: %w", err)
-	}
-	defer resp.Body.Close()
+	// Chat returns the full assistant reply for the given conversation.
+	Chat(ctx context.Context, messages []aiMessage) (string, error)
 
-	if resp.StatusCode >= 400 {
-		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
-		return "", fmt.Errorf("openai returned %d: %s", resp.StatusCode, string(bodyBytes))
-	}
+	// ChatJSON is like Chat but asks the model to reply with a JSON object.
+	ChatJSON(ctx context.Context, messages []aiMessage) (string, error)
 
-	var out openAIChatResponse
-	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
-		log.Printf("[AI ERROR] failed to decode OpenAI response: %v\nResponse body (truncated): %s", err, string(body))
-		return "", fmt.Errorf("invalid OpenAI response: %w", err)
-	}
+	// ChatStream calls onDelta for every content fragment as it arrives.
+	// Returning an error from onDelta aborts the stream.
+	ChatStream(ctx context.Context, messages []aiMessage, onDelta func(delta string) error) error
+}
 
-	if out.Error != nil && out.Error.Message != "" {
-		return "", fmt.Errorf("openai error: %s (%s)", out.Error.Message, out.Error.Type)
-	}
-	if len(out.Choices) == 0 {
-		return "", fmt.Errorf("openai response had no choices")
-	}
+// ToolCaller is implemented by providers whose models can call tools. The
+// chat only offers tools when the configured provider implements it.
+type ToolCaller interface {
+	// ChatStreamTools is ChatStream with tools offered to the model (none
+	// for a plain reply). It returns the tool calls the model made, where
+	// none means the reply is final, and the token usage when reported.
+	ChatStreamTools(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error)
+}
 
-	content := out.Choices[0].Message.Content
-	log.Printf("[AI] OpenAI response (first 200 chars): %s", truncate(content, 200))
-	return content, nil
+// newLLMProvider builds the provider selected by LLM_PROVIDER.
+func newLLMProvider(config util.Config) (LLMProvider, error) {
+	switch strings.ToLower(strings.TrimSpace(config.LLMProvider)) {
+	case "", llmProviderOpenAI:
+		return newOpenAIProvider(config.OpenAIAPIKey, config.OpenAIModel), nil
+	case llmProviderOpenAICompatible:
+		if strings.TrimSpace(config.LLMBaseURL) == "" {
+			return nil, fmt.Errorf("LLM_BASE_URL is required for the %s provider", llmProviderOpenAICompatible)
+		}
+		return newOpenAICompatibleProvider(config.LLMBaseURL, config.OpenAIAPIKey, config.OpenAIModel), nil
+	case llmProviderFake:
+		return newFakeLLMProvider(), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider %q", config.LLMProvider)
+	}
 }
 
 // Helper: safe truncation for log output
@@ -3,9 +3,11 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
 	"sort"
 	"strings"
 	"time"
@@ -30,12 +32,16 @@ type Recommendation struct {
 	Code        string  `json:"code,omitempty"`
 	Link        string  `json:"link,omitempty"`
 	CourseID    int64   `json:"course_id,omitempty"`
+
+	// Prerequisite check result (see prerequisites.go)
+	Eligibility          string   `json:"eligibility,omitempty"`
+	MissingPrerequisites []string `json:"missing_prerequisites"`
 }
 
 // -----------------------------------------------------------------------------
 // 1. HELPER: Extract Completed Courses (AI)
 // -----------------------------------------------------------------------------
-func (s *Server) extractCompletedCourses(c *fiber.Ctx, transcriptText string) ([]string, error) {
+func (s *Server) extractCompletedCourses(ctx context.Context, transcriptText string) ([]string, error) {
 	messages := []aiMessage{
 		{
 			Role:    "system",
@@ -47,7 +53,7 @@ func (s *Server) extractCompletedCourses(c *fiber.Ctx, transcriptText string) ([
 		},
 	}
 
-	raw, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
+	raw, err := s.llm.ChatJSON(ctx, messages)
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +72,26 @@ func (s *Server) extractCompletedCourses(c *fiber.Ctx, transcriptText string) ([
 	return normalized, nil
 }
 
+// completedCourseCodes returns the codes of completed courses for a transcript,
+// preferring the rows parsed at upload time and asking the LLM only when none exist.
+func (s *Server) completedCourseCodes(ctx context.Context, transcript db.Transcript) ([]string, error) {
+	rows, err := s.store.ListTranscriptCourses(ctx, transcript.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Status == courseStatusCompleted {
+			codes = append(codes, strings.ToUpper(strings.TrimSpace(row.CourseCode)))
+		}
+	}
+	if len(rows) > 0 {
+		return codes, nil
+	}
+	return s.extractCompletedCourses(ctx, transcript.TextExtracted.String)
+}
+
 // -----------------------------------------------------------------------------
 // 2. HELPER: Filter Logic
 // -----------------------------------------------------------------------------
@@ -85,56 +111,28 @@ func filterAvailableCourses(allCourses []db.Course, completedCodes []string) []d
 	return available
 }
 
-// -----------------------------------------------------------------------------
-// 3. MAIN HANDLER: Create (Smart Filter)
-// -----------------------------------------------------------------------------
-func (s *Server) createRecommendation(c *fiber.Ctx) error {
-	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
-	if !ok || payload == nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
-	}
-
-	var req createRecommendationRequest
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
-	}
-
-	// Fetch Transcript
-	transcript, err := s.store.GetTranscript(c.Context(), req.TranscriptID)
-	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("transcript not found")))
-	}
-	if !transcript.TextExtracted.Valid || transcript.TextExtracted.String == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no text content")))
-	}
-
-	// AI Step A: Extract History
-	completedCodes, err := s.extractCompletedCourses(c, transcript.TextExtracted.String)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to analyze transcript history: %w", err)))
-	}
-
-	// DB: Get All Courses
-	allCourses, err := s.store.ListAllCourses(c.Context())
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
-	}
-
-	// Go: Filter Available
-	candidates := filterAvailableCourses(allCourses, completedCodes)
-	if len(candidates) == 0 {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"courses": []Recommendation{},
-			"message": "No new courses available.",
-		})
+// filterEligibleCourses drops candidates whose prerequisites block enrolment.
+func filterEligibleCourses(candidates []db.Course, eligibility map[int64]courseEligibility) []db.Course {
+	var out []db.Course
+	for _, course := range candidates {
+		if eligibility[course.ID].Status != eligibilityBlocked {
+			out = append(out, course)
+		}
 	}
+	return out
+}
 
+// rankCoursesLLM asks the LLM to pick and explain the best candidates for
+// the preference. Only candidates that passed the prerequisite check are kept.
+func (s *Server) rankCoursesLLM(ctx context.Context, preference string, candidates []db.Course, eligibility map[int64]courseEligibility) ([]Recommendation, error) {
 	// Prepare AI Prompt
 	type PromptCourse struct {
-		ID   int64  `json:"id"`
-		Code string `json:"code"`
-		Name string `json:"name"`
-		Desc string `json:"desc"`
+		ID          int64    `json:"id"`
+		Code        string   `json:"code"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		Eligibility string   `json:"eligibility"`
+		Missing     []string `json:"missing_prerequisites,omitempty"`
 	}
 	var promptList []PromptCourse
 	for _, c := range candidates {
@@ -146,10 +144,12 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 			}
 		}
 		promptList = append(promptList, PromptCourse{
-			ID:   c.ID,
-			Code: c.Code,
-			Name: c.Name,
-			Desc: desc,
+			ID:          c.ID,
+			Code:        c.Code,
+			Name:        c.Name,
+			Desc:        desc,
+			Eligibility: eligibility[c.ID].Status,
+			Missing:     eligibility[c.ID].Missing,
 		})
 	}
 	candidateBytes, _ := json.Marshal(promptList)
@@ -158,6 +158,7 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 	Task:
 	1. Analyze the 'Available Courses' list and the 'User Preference'.
 	2. Select the top 3-5 courses that best match the preference.
+	   Prefer courses whose "eligibility" is "eligible" over "partially_eligible" ones.
 	3. Return a JSON object with a key "recommendations" which is an array.
 	4. Each item must have: 
 		- "course_id" (integer, copied exactly from input)
@@ -166,16 +167,16 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		- "rationale" (string, why it fits)
 		- "match" (number 0-100)`
 
-	userPrompt := fmt.Sprintf("User Preference: %s\n\nAvailable Courses:\n%s", req.Preference, string(candidateBytes))
+	userPrompt := fmt.Sprintf("User Preference: %s\n\nAvailable Courses:\n%s", preference, string(candidateBytes))
 
 	messages := []aiMessage{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: userPrompt},
 	}
 
-	rawResponse, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
+	rawResponse, err := s.llm.ChatJSON(ctx, messages)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return nil, err
 	}
 
 	// Parse AI Response
@@ -202,9 +203,14 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		}
 	}
 
-	// Map back to Recommendation Struct
+	// Map back to Recommendation Struct (only courses that passed the prerequisite check)
 	finalRecs := make([]Recommendation, 0)
 	for _, r := range aiResult.Recommendations {
+		check, ok := eligibility[r.CourseID]
+		if !ok || check.Status == eligibilityBlocked {
+			continue
+		}
+
 		link := ""
 		for _, c := range candidates {
 			if c.ID == r.CourseID && c.CourseLink.Valid {
@@ -214,25 +220,127 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		}
 
 		finalRecs = append(finalRecs, Recommendation{
-			Type:        "course",
-			Title:       r.Title,
-			Code:        r.Code,
-			Description: r.Rationale,
-			Match:       r.Match,
-			Link:        link,
-			CourseID:    r.CourseID,
+			Type:                 "course",
+			Title:                r.Title,
+			Code:                 r.Code,
+			Description:          r.Rationale,
+			Match:                r.Match,
+			Link:                 link,
+			CourseID:             r.CourseID,
+			Eligibility:          check.Status,
+			MissingPrerequisites: check.Missing,
 		})
 	}
 
 	sort.Slice(finalRecs, func(i, j int) bool { return finalRecs[i].Match > finalRecs[j].Match })
+	return finalRecs, nil
+}
+
+// -----------------------------------------------------------------------------
+// 3. MAIN HANDLER: Create (Smart Filter)
+// -----------------------------------------------------------------------------
+func (s *Server) createRecommendation(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req createRecommendationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	return s.dispatchJob(c, payload.Username, jobKindRecommendation, req)
+}
+
+// recommendCourses filters the catalog against the transcript, checks
+// prerequisites, ranks the rest and saves the recommendation. It runs as a
+// "recommendation" job.
+func (s *Server) recommendCourses(ctx context.Context, username string, req createRecommendationRequest) (fiber.Map, error) {
+
+	// Fetch Transcript
+	transcript, err := s.store.GetTranscript(ctx, req.TranscriptID)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "transcript not found")
+	}
+	if transcript.UserUsername != username {
+		return nil, fiber.NewError(fiber.StatusForbidden, "forbidden")
+	}
+	if !transcript.TextExtracted.Valid || transcript.TextExtracted.String == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "transcript has no text content")
+	}
+
+	// Step A: Completed history from parsed transcript rows (AI only as fallback)
+	completedCodes, err := s.completedCourseCodes(ctx, transcript)
+	if err != nil {
+		// LLM extraction failed: fall back to the heuristic parser
+		log.Printf("[WARN] Completed course extraction failed, using heuristic parser: %v", err)
+		completedCodes = []string{}
+		for _, pc := range parseTranscriptCourses(transcript.TextExtracted.String) {
+			if pc.Status == courseStatusCompleted {
+				completedCodes = append(completedCodes, pc.Code)
+			}
+		}
+	}
+
+	s.publishEvent(ctx, username, eventRecoHistoryExtracted, fiber.Map{"completed_courses": len(completedCodes)})
+
+	// DB: Get all courses still on offer (archived ones are never recommended)
+	allCourses, err := s.store.ListActiveCourses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Go: Filter Available
+	candidates := filterAvailableCourses(allCourses, completedCodes)
+
+	// Go: Prerequisite check (drop blocked, annotate the rest)
+	eligibility, err := s.checkEligibility(ctx, candidates, completedCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check prerequisites: %w", err)
+	}
+	candidates = filterEligibleCourses(candidates, eligibility)
+	s.publishEvent(ctx, username, eventRecoCandidates, fiber.Map{"catalog": len(allCourses), "eligible": len(candidates)})
+	if len(candidates) == 0 {
+		return fiber.Map{
+			"courses": []Recommendation{},
+			"message": "No new courses available.",
+		}, nil
+	}
+
+	// Go: Semantic retrieval (only the top-K closest courses reach the prompt)
+	query := retrievalQuery(req.Preference, allCourses, completedCodes)
+	candidates = s.retrieveCourses(ctx, candidates, query, s.config.RecommendationTopK)
+	s.publishEvent(ctx, username, eventRecoRetrieved, fiber.Map{"candidates": len(candidates)})
+
+	// Rank: LLM by default, lexical (BM25) when configured or when the LLM fails
+	engine := recoEngineLLM
+	if strings.EqualFold(strings.TrimSpace(s.config.RecommendationEngine), recoEngineLexical) {
+		engine = recoEngineLexical
+	}
+
+	var finalRecs []Recommendation
+	if engine == recoEngineLLM {
+		finalRecs, err = s.rankCoursesLLM(ctx, req.Preference, candidates, eligibility)
+		if err != nil || len(finalRecs) == 0 {
+			log.Printf("[WARN] LLM ranking unavailable, using lexical recommender: %v", err)
+			engine = recoEngineLexical
+		}
+	}
+	if engine == recoEngineLexical {
+		finalRecs = lexicalRecommend(candidates, eligibility, req.Preference, transcript.TextExtracted.String, lexicalRecoLimit)
+	}
+	s.publishEvent(ctx, username, eventRecoRanked, fiber.Map{"engine": engine, "courses": len(finalRecs)})
 
 	// ***************************************************************
 	// FIX: Merge Scholarships into the Payload before saving
 	// ***************************************************************
 
-	// 1. Fetch the latest scholarships for this user
-	// FIX: Use the specific method signature provided by the user.
-	scholarships, err := s.store.ListScholarshipsByUser(c.Context(), payload.Username)
+	// 1. Fetch the best open scholarship matches for this user
+	scholarships, err := s.store.ListUserScholarships(ctx, db.ListUserScholarshipsParams{
+		UserUsername: username,
+		Limit:        scholarshipContextLimit,
+	})
 	if err != nil {
 		// Log but do not fail the process if scholarships cannot be found
 		fmt.Printf("[WARN] Failed to list scholarships for payload merge: %v\n", err)
@@ -241,6 +349,7 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 	// 2. Prepare the full payload map (which goes into the DB Payload column)
 	fullResultWrapper := fiber.Map{
 		"courses": finalRecs, // Already populated above
+		"engine":  engine,
 	}
 
 	// 3. Add scholarships to the wrapper if found
@@ -252,25 +361,27 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 	// 4. Marshal and Save to DB
 	resultJSON, _ := json.Marshal(fullResultWrapper)
 
-	reco, err := s.store.CreateRecommendation(c.Context(), db.CreateRecommendationParams{
-		UserUsername: payload.Username,
+	reco, err := s.store.CreateRecommendation(ctx, db.CreateRecommendationParams{
+		UserUsername: username,
 		TranscriptID: sql.NullInt64{Int64: req.TranscriptID, Valid: true},
 		Payload:      resultJSON,
 		Summary:      sql.NullString{String: "Course Recommendation", Valid: true},
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return nil, err
 	}
+	s.publishEvent(ctx, username, eventRecoSaved, fiber.Map{"recommendation_id": reco.ID})
 
 	// Response includes the final, combined results
-	return c.JSON(fiber.Map{
+	return fiber.Map{
 		"id":           reco.ID,
 		"created_at":   reco.CreatedAt,
 		"courses":      finalRecs,
 		"scholarships": scholarships, // Include for frontend display if needed
 		"user_pref":    req.Preference,
+		"engine":       engine,
 		"analyzed_at":  time.Now(),
-	})
+	}, nil
 }
 
 // -----------------------------------------------------------------------------
@@ -278,14 +389,20 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 // -----------------------------------------------------------------------------
 
 // GET /recommendations
+// GET /advisor/students/:username/recommendations
 func (s *Server) listRecommendations(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
+	username := c.Params("username", payload.Username)
+	if err := s.checkReadAccess(c.Context(), payload, username); err != nil {
+		return statusErrorResponse(c, err)
+	}
+
 	// Calls the SQLC generated method ListRecommendations
-	recos, err := s.store.ListRecommendations(c.Context(), payload.Username)
+	recos, err := s.store.ListRecommendations(c.Context(), username)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
@@ -313,9 +430,9 @@ func (s *Server) getRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	// Security check: ensure user owns this recommendation
-	if reco.UserUsername != payload.Username {
-		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	// Security check: owner, assigned advisor or admin
+	if err := s.checkReadAccess(c.Context(), payload, reco.UserUsername); err != nil {
+		return statusErrorResponse(c, err)
 	}
 
 	return c.JSON(reco)
@@ -350,27 +467,52 @@ func (s *Server) deleteCourseFromRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	// Security check: ensure user owns this recommendation
+	// Security check: only the owner may change a recommendation (advisors
+	// and admins only read it)
 	if reco.UserUsername != payload.Username {
 		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
 	}
 
-	// 3. Unmarshal the existing Payload
-	// Use a struct that mirrors the Recommendation structure saved in the Payload column
-	var payloadMap struct {
-		Courses      []Recommendation    `json:"courses"`
-		Scholarships json.RawMessage     `json:"scholarships,omitempty"` // Preserve existing scholarships
+	// 3. Remove the course and save the payload
+	updatedCourses, err := s.removeRecommendedCourse(c.Context(), reco, payload.Username, func(course Recommendation) bool {
+		// Filter criteria: Match the unique CourseID
+		return course.CourseID == courseID
+	})
+	if err != nil {
+		return statusErrorResponse(c, err)
 	}
+
+	// 4. Return the updated course list to the frontend for state refresh
+	return c.JSON(fiber.Map{
+		"message": "Course deleted.",
+		"courses": updatedCourses,
+	})
+}
+
+// removeRecommendedCourse drops the courses matching match from the
+// recommendation's payload, preserving its scholarships, and saves it as
+// username's. It returns the remaining courses, or a 404 fiber.Error when no
+// course matches. Used by the DELETE handler and the chat's
+// remove_course_from_recommendation tool.
+func (s *Server) removeRecommendedCourse(ctx context.Context, reco db.Recommendation, username string, match func(Recommendation) bool) ([]Recommendation, error) {
+	// 1. Unmarshal the existing Payload, keeping every field other than the
+	// courses (scholarships, engine, ...) as it was saved
+	var payloadMap map[string]json.RawMessage
 	if err := json.Unmarshal(reco.Payload, &payloadMap); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to parse recommendation payload: %w", err)))
+		return nil, fmt.Errorf("failed to parse recommendation payload: %w", err)
+	}
+	var courses []Recommendation
+	if raw, ok := payloadMap["courses"]; ok {
+		if err := json.Unmarshal(raw, &courses); err != nil {
+			return nil, fmt.Errorf("failed to parse recommendation payload: %w", err)
+		}
 	}
 
-	// 4. Filter the Courses array to remove the specified course
+	// 2. Filter the Courses array to remove the specified course
 	var updatedCourses []Recommendation
 	found := false
-	for _, course := range payloadMap.Courses {
-		// Filter criteria: Match the unique CourseID
-		if course.CourseID != courseID {
+	for _, course := range courses {
+		if !match(course) {
 			updatedCourses = append(updatedCourses, course)
 		} else {
 			found = true
@@ -378,37 +520,29 @@ func (s *Server) deleteCourseFromRecommendation(c *fiber.Ctx) error {
 	}
 
 	if !found {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course ID %d not found in recommendation %d", courseID, recoID)))
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("course not found in recommendation %d", reco.ID))
 	}
 
-	// 5. Re-package the entire payload (preserving scholarships)
-	newPayloadMap := fiber.Map{
-		"courses": updatedCourses,
-	}
-	if len(payloadMap.Scholarships) > 0 {
-		newPayloadMap["scholarships"] = payloadMap.Scholarships
+	// 3. Replace only the courses in the payload
+	coursesJSON, err := json.Marshal(updatedCourses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal new payload: %w", err)
 	}
+	payloadMap["courses"] = coursesJSON
 
-	newPayloadJSON, err := json.Marshal(newPayloadMap)
+	newPayloadJSON, err := json.Marshal(payloadMap)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to marshal new payload: %w", err)))
+		return nil, fmt.Errorf("failed to marshal new payload: %w", err)
 	}
 
-	// 6. Save the updated payload back to the database
-	// NOTE: This assumes you have the following SQLC method/query defined:
-	// db.UpdateRecommendationPayloadParams{ID: recoID, UserUsername: payload.Username, Payload: newPayloadJSON}
-	_, err = s.store.UpdateRecommendationPayload(c.Context(), db.UpdateRecommendationPayloadParams{
-		ID:           recoID,
-		UserUsername: payload.Username, // Added security check to the params
+	// 4. Save the updated payload back to the database
+	_, err = s.store.UpdateRecommendationPayload(ctx, db.UpdateRecommendationPayloadParams{
+		ID:           reco.ID,
+		UserUsername: username, // Added security check to the params
 		Payload:      newPayloadJSON,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to save updated recommendation: %w", err)))
+		return nil, fmt.Errorf("failed to save updated recommendation: %w", err)
 	}
-
-	// 7. Return the updated course list to the frontend for state refresh
-	return c.JSON(fiber.Map{
-		"message": "Course deleted.",
-		"courses": updatedCourses,
-	})
-}
\ No newline at end of file
+	return updatedCourses, nil
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"sort"
@@ -13,16 +14,27 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 )
 
 // ScholarshipReco represents one AI-generated scholarship recommendation
 type ScholarshipReco struct {
+	ID          int64   `json:"id,omitempty"` // scholarship_catalog.id once saved
 	Title       string  `json:"title"`
 	Description string  `json:"description"`
 	Match       float64 `json:"match"`
 	Link        string  `json:"link"`
+
+	// Optional details, normalized in scholarship_catalog.go
+	Provider              string   `json:"provider,omitempty"`
+	Amount                float64  `json:"amount,omitempty"`
+	Currency              string   `json:"currency,omitempty"`
+	Deadline              string   `json:"deadline,omitempty"` // YYYY-MM-DD
+	EligibleNationalities []string `json:"eligible_nationalities,omitempty"`
+	DegreeLevels          []string `json:"degree_levels,omitempty"`
+	FieldsOfStudy         []string `json:"fields_of_study,omitempty"`
+
+	Query string `json:"query,omitempty"` // web search query that surfaced it
 }
 
 // POST /api/scholarships/generate
@@ -32,36 +44,59 @@ func (s *Server) generateScholarships(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
+	return s.dispatchJob(c, payload.Username, jobKindScholarships, fiber.Map{})
+}
+
+// findScholarships searches the web for scholarships matching the user's
+// latest transcript, ranks them with the LLM and saves them. It runs as a
+// "scholarships" job.
+func (s *Server) findScholarships(ctx context.Context, username string) (fiber.Map, error) {
 	// 1️⃣ Get latest transcript
-	transcripts, err := s.store.ListTranscripts(c.Context(), payload.Username)
+	transcripts, err := s.store.ListTranscripts(ctx, username)
 	if err != nil || len(transcripts) == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("no transcripts found")))
+		return nil, fiber.NewError(fiber.StatusNotFound, "no transcripts found")
 	}
-	fullTr, err := s.store.GetTranscript(c.Context(), transcripts[0].ID)
+	fullTr, err := s.store.GetTranscript(ctx, transcripts[0].ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return nil, err
 	}
 	txText := strings.TrimSpace(fullTr.TextExtracted.String)
 	if txText == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no extracted text")))
+		return nil, fiber.NewError(fiber.StatusBadRequest, "transcript has no extracted text")
 	}
 
-	// 2️⃣ Perform Brave web search
-	query := "scholarships for international students studying computer science OR artificial intelligence"
-	webResults, werr := fetchBraveResults(query, s.config.BraveAPIKey, s.config.BraveAPIURL, s.config.WebSearchMaxResults)
+	profile, err := s.store.GetStudentProfile(ctx, username)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	courses, err := s.store.ListTranscriptCourses(ctx, fullTr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2️⃣ Perform web search with queries built from the profile and transcript
+	queries := scholarshipSearchQueries(profile, transcriptTopics(courses, transcriptTopicCount))
+	webResults, werr := s.searchScholarshipQueries(ctx, queries)
 
 	log.Println("------------------------------------------------------------")
-	log.Printf("[DEBUG] Brave Search Results (%d):", len(webResults))
+	log.Printf("[DEBUG] Web Search Results (%d) for %q:", len(webResults), queries)
 	for i, w := range webResults {
-		log.Printf("%d) %s -> %s", i+1, w.Title, w.URL)
+		log.Printf("%d) %s -> %s [%s]", i+1, w.Title, w.URL, w.Query)
 	}
 	log.Println("------------------------------------------------------------")
 
 	if werr != nil {
-		log.Printf("[WEB] Brave web search failed: %v", werr)
+		log.Printf("[WEB] Web search failed: %v", werr)
 	}
 	if len(webResults) == 0 {
-		log.Println("[WEB] No Brave results found — continuing with transcript only.")
+		log.Println("[WEB] No web results found — continuing with transcript only.")
+	}
+	s.publishEvent(ctx, username, eventScholarshipsSearched, fiber.Map{"results": len(webResults), "queries": queries})
+
+	// Download the result pages for the details the snippets leave out
+	pages := s.fetchScholarshipPages(ctx, webResults)
+	if pages != nil {
+		s.publishEvent(ctx, username, eventScholarshipsFetched, fiber.Map{"pages": len(pages)})
 	}
 
 	// 3️⃣ Build AI prompt
@@ -71,6 +106,7 @@ You are an academic scholarship advisor.
 Your task: identify scholarships — NOT university courses or degrees.
 Use the student's transcript only to understand their background (e.g. Software Engineering, AI, Data Science).
 From the provided web search results, list the most relevant scholarships for this profile.
+Where a result includes text from its page, take the deadline, amount and eligibility from that text.
 
 Return ONLY scholarships (no courses, no degrees, no projects).
 Each result must include:
@@ -78,10 +114,19 @@ Each result must include:
 - description (what it offers or who it's for)
 - match (number 0–100)
 - link (URL to the scholarship)
+and, when the results state them:
+- provider, amount (number), currency (ISO code), deadline (YYYY-MM-DD)
+- eligible_nationalities, degree_levels (bachelor, master, doctoral), fields_of_study (arrays of strings)
 
 Respond ONLY in valid JSON format.
 `)
 
+	if p := profilePromptText(profile); p != "" {
+		sb.WriteString("Student profile:\n")
+		sb.WriteString(p)
+		sb.WriteString("\n\n")
+	}
+
 	sb.WriteString("Transcript:\n\"\"\"\n")
 	sb.WriteString(txText)
 	sb.WriteString("\n\"\"\"\n\n")
@@ -94,6 +139,11 @@ Respond ONLY in valid JSON format.
 				w.URL,
 				truncate(w.Snippet, 200),
 			))
+			if _, key, ok := canonicalScholarshipURL(w.URL); ok {
+				if p, ok := pages[key]; ok {
+					sb.WriteString(pagePromptText(p))
+				}
+			}
 		}
 	}
 
@@ -111,6 +161,10 @@ Each scholarship must contain:
 - "description": string
 - "match": number (0–100)
 - "link": string (valid URL)
+
+Optional, only when known: "provider", "amount" (number), "currency",
+"deadline" (YYYY-MM-DD), "eligible_nationalities", "degree_levels",
+"fields_of_study" (arrays of strings).
 			`,
 		},
 		{
@@ -120,9 +174,9 @@ Each scholarship must contain:
 	}
 
 	// 5️⃣ Call OpenAI inference
-	resp, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
+	resp, err := s.llm.ChatJSON(ctx, messages)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("openai failed: %v", err)))
+		return nil, fmt.Errorf("%s failed: %v", s.llm.Name(), err)
 	}
 
 	log.Println("------------------------------------------------------------")
@@ -160,23 +214,16 @@ Each scholarship must contain:
 	}
 	recs = filtered
 
-	// Sort by match score (desc)
+	// Sort by match score (desc) and keep the best result per canonical URL
 	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Match > recs[j].Match })
+	recs = dedupeScholarships(recs)
+	attachScholarshipQueries(recs, webResults)
+	applyScholarshipFacts(recs, pages)
 
-	// 8️⃣ Persist all valid results
-	ctx := context.Background()
-	for _, r := range recs {
-		_, err := s.store.CreateScholarship(ctx, db.CreateScholarshipParams{
-			UserUsername: payload.Username,
-			Title:        r.Title,
-			Description:  sql.NullString{String: r.Description, Valid: r.Description != ""},
-			MatchScore:   sql.NullFloat64{Float64: r.Match, Valid: r.Match > 0},
-			Link:         sql.NullString{String: r.Link, Valid: r.Link != ""},
-		})
-		if err != nil {
-			log.Printf("[DB] Save scholarship failed for %s: %v", r.Title, err)
-		}
-	}
+	// 8️⃣ Persist into the shared catalog + this user's matches
+	recs = s.saveScholarshipMatches(ctx, username, recs)
+
+	s.publishEvent(ctx, username, eventScholarshipsSaved, fiber.Map{"scholarships": len(recs)})
 
 	// 9️⃣ Log parsed results
 	log.Println("------------------------------------------------------------")
@@ -187,12 +234,12 @@ Each scholarship must contain:
 	log.Println("------------------------------------------------------------")
 
 	// 🔟 Return all scholarships to frontend
-	return c.JSON(fiber.Map{
-		"user":         payload.Username,
+	return fiber.Map{
+		"user":         username,
 		"count":        len(recs),
 		"scholarships": recs,
 		"generated_at": time.Now(),
-	})
+	}, nil
 }
 
 // Helper: extract array if AI wraps JSON in text
@@ -3,6 +3,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -18,19 +19,25 @@ func (s *Server) generateSummary(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
+	return s.dispatchJob(c, payload.Username, jobKindSummary, fiber.Map{})
+}
+
+// summarizeTranscript writes a short AI summary of the user's latest
+// transcript. It runs as a "summary" job.
+func (s *Server) summarizeTranscript(ctx context.Context, username string) (fiber.Map, error) {
 	// Fetch latest transcript
-	transcripts, err := s.store.ListTranscripts(c.Context(), payload.Username)
+	transcripts, err := s.store.ListTranscripts(ctx, username)
 	if err != nil || len(transcripts) == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("no transcripts found")))
+		return nil, fiber.NewError(fiber.StatusNotFound, "no transcripts found")
 	}
-	fullTr, err := s.store.GetTranscript(c.Context(), transcripts[0].ID)
+	fullTr, err := s.store.GetTranscript(ctx, transcripts[0].ID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return nil, err
 	}
 
 	txText := strings.TrimSpace(fullTr.TextExtracted.String)
 	if txText == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no extracted text")))
+		return nil, fiber.NewError(fiber.StatusBadRequest, "transcript has no extracted text")
 	}
 
 	// Build messages
@@ -47,16 +54,17 @@ Transcript:
 		{Role: "user", Content: prompt},
 	}
 
-	resp, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, false)
+	resp, err := s.llm.Chat(ctx, messages)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("openai failed: %v", err)))
+		return nil, fmt.Errorf("%s failed: %v", s.llm.Name(), err)
 	}
 
 	summaryText := strings.TrimSpace(resp)
+	s.publishEvent(ctx, username, eventSummaryGenerated, fiber.Map{"characters": len(summaryText)})
 
-	return c.JSON(fiber.Map{
-		"user":         payload.Username,
+	return fiber.Map{
+		"user":         username,
 		"summary_text": summaryText,
 		"generated_at": time.Now(),
-	})
+	}, nil
 }
@@ -3,13 +3,11 @@
 package api
 
 import (
-	"bufio"
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
 
@@ -24,6 +22,8 @@ type ChatMessage struct {
 }
 
 // POST /api/chat/stream
+// Streams the reply as Server-Sent Events (see chat_stream.go). With a
+// Last-Event-ID header it resumes that reply instead.
 func (s *Server) chatStream(c *fiber.Ctx) error {
 	// --- Auth check ---
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
@@ -31,6 +31,11 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
+	// --- Reconnect to a running or recent reply ---
+	if lastEventID := c.Get("Last-Event-ID"); lastEventID != "" {
+		return s.resumeReplyStream(c, payload.Username, lastEventID)
+	}
+
 	// --- Parse request body ---
 	var req struct {
 		Messages []ChatMessage `json:"messages"`
@@ -39,132 +44,8 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("invalid request body")))
 	}
 
-	// --- Build System Context (VITAL PHASE 3 UPDATE) ---
-
-	// 1. Base System Prompt
-	systemContext := "You are EduSphere AI, an academic advisor who provides personalized advice based on the provided user's full academic context (transcript, recommended courses, and potential scholarships). Be concise and professional. You must use the provided context to justify your answers."
-
-	// 2. Read the Recommendation context header
-	recoIDStr := c.Get("X-Recommendation-ID")
-
-	if recoIDStr != "" {
-		recoID, err := strconv.ParseInt(recoIDStr, 10, 64)
-
-		if err == nil {
-			// Fetch the full Recommendation record
-			reco, err := s.store.GetRecommendation(c.Context(), recoID)
-
-			if err == nil {
-				var contextBuilder strings.Builder
-
-				// 3a. Inject Transcript Text
-				if reco.TranscriptID.Valid {
-					tr, trErr := s.store.GetTranscript(c.Context(), reco.TranscriptID.Int64)
-					if trErr == nil && tr.TextExtracted.Valid && strings.TrimSpace(tr.TextExtracted.String) != "" {
-						contextBuilder.WriteString(fmt.Sprintf("\n\n[USER ACADEMIC TRANSCRIPT TEXT]\n%s\n", tr.TextExtracted.String))
-					}
-				}
-
-				// --- DEBUG STEP: Log the raw payload to find the correct key ---
-				if len(reco.Payload) > 2 {
-					log.Printf("[AI-CHAT] DEBUG RAW PAYLOAD: %s", string(reco.Payload))
-				}
-				// ------------------------------------------------------------------
-
-				// 3b. Inject Recommendation Payload (Courses, Rationale)
-				if len(reco.Payload) > 2 {
-					var payloadMap map[string]json.RawMessage
-					if json.Unmarshal(reco.Payload, &payloadMap) == nil {
-
-						// i. Inject Recommended Courses
-						if coursesJSON, ok := payloadMap["courses"]; ok && len(coursesJSON) > 2 {
-							contextBuilder.WriteString(fmt.Sprintf("\n\n[RECOMMENDED COURSES JSON]\n%s\n", string(coursesJSON)))
-						}
-
-						// ii. We remove "scholarships" key from here if it exists in the JSON blob,
-						// because we will fetch the FRESH list from the DB below.
-						delete(payloadMap, "courses")
-						delete(payloadMap, "scholarships") // Ignore blob scholarships, use DB instead
-
-						// iii. Inject remaining payload
-						if remainingJSON, err := json.Marshal(payloadMap); err == nil && len(remainingJSON) > 2 {
-							contextBuilder.WriteString(fmt.Sprintf("\n\n[OTHER RECOMMENDATION DATA JSON]\n%s\n", string(remainingJSON)))
-						}
-
-					} else {
-						// Fallback: If parsing fails, inject the entire payload raw
-						contextBuilder.WriteString(fmt.Sprintf("\n\n[RAW RECOMMENDATION PAYLOAD JSON]\n%s\n", string(reco.Payload)))
-					}
-				}
-
-				// Append the combined context to the system prompt
-				if contextBuilder.Len() > 0 {
-					systemContext += "\n\n[FULL ACADEMIC CONTEXT INJECTED BELOW]\n"
-					systemContext += contextBuilder.String()
-					log.Printf("[AI-CHAT] Injecting %d bytes of total context for Recommendation ID: %d", len(contextBuilder.String()), recoID)
-				}
-			} else {
-				log.Printf("[AI-CHAT] Failed to fetch Recommendation for ID: %d. Error: %v", recoID, err)
-			}
-		}
-	}
-
-	// -------------------------------------------------------------------------
-	// NEW: Inject Real-time Scholarships from Database
-	// -------------------------------------------------------------------------
-	scholarships, err := s.store.ListRecentScholarshipsByUser(c.Context(), db.ListRecentScholarshipsByUserParams{
-		UserUsername: payload.Username,
-		Limit:        10, // Fetch top 10 most recent/relevant
-	})
-
-	if err == nil && len(scholarships) > 0 {
-		var sb strings.Builder
-		sb.WriteString("\n\n=== AVAILABLE SCHOLARSHIP OPPORTUNITIES (FROM DATABASE) ===\n")
-		sb.WriteString("Use this list if the user asks about financial aid, funding, or scholarships.\n\n")
-
-		for i, sch := range scholarships {
-			matchScore := 0.0
-			if sch.MatchScore.Valid {
-				matchScore = sch.MatchScore.Float64
-			}
-
-			// Format: 1. Title (Match: X%)
-			sb.WriteString(fmt.Sprintf("%d. %s (Match: %.0f%%)\n", i+1, sch.Title, matchScore))
-
-			// Add Description (truncated)
-			if sch.Description.Valid {
-				desc := sch.Description.String
-				if len(desc) > 200 {
-					desc = desc[:200] + "..."
-				}
-				sb.WriteString(fmt.Sprintf("   Context: %s\n", desc))
-			}
-
-			// Add Link
-			if sch.Link.Valid {
-				sb.WriteString(fmt.Sprintf("   Link: %s\n", sch.Link.String))
-			}
-			sb.WriteString("\n")
-		}
-		
-		// Append to the main system context
-		systemContext += sb.String()
-		log.Printf("[AI-CHAT] Injected %d scholarships from DB into context.", len(scholarships))
-	} else if err != nil {
-		log.Printf("[AI-CHAT] Failed to fetch scholarships for user %s: %v", payload.Username, err)
-	}
-	// -------------------------------------------------------------------------
-
-	// --- Build messages for OpenAI (incorporate systemContext) ---
-	var openAIMessages []map[string]string
-
-	// VITAL: Insert the enhanced system context as the first message
-	openAIMessages = append(openAIMessages, map[string]string{
-		"role":    "system",
-		"content": systemContext,
-	})
-
-	// Append user history and current message
+	// --- Build messages for the LLM ---
+	var history []aiMessage
 	for _, m := range req.Messages {
 		role := strings.ToLower(strings.TrimSpace(m.Role))
 		if role != "user" && role != "assistant" && role != "system" {
@@ -172,107 +53,128 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		}
 		content := strings.TrimSpace(m.Content)
 		if content != "" {
-			openAIMessages = append(openAIMessages, map[string]string{
-				"role":    role,
-				"content": content,
-			})
+			history = append(history, aiMessage{Role: role, Content: content})
 		}
 	}
-	// --- End Message Build ---
 
-	// --- Setup streaming response headers ---
-	c.Set("Content-Type", "text/event-stream")
-	c.Set("Cache-Control", "no-cache")
-	c.Set("Connection", "keep-alive")
+	// --- Build System Context (VITAL PHASE 3 UPDATE) ---
+	// X-Recommendation-ID pins a recommendation; conversations store it instead.
+	// Sources are retrieved for the latest user question.
+	recoID, _ := strconv.ParseInt(c.Get("X-Recommendation-ID"), 10, 64)
+	ctx := withLLMCaller(c.Context(), payload.Username, llmFeatureChat)
+	systemContext, sources := s.chatSystemContext(ctx, payload.Username, recoID, lastUserMessage(history))
 
-	rawWriter := c.Response().BodyWriter()
-	writer := bufio.NewWriter(rawWriter)
+	// VITAL: Insert the enhanced system context as the first message
+	llmMessages := append([]aiMessage{{Role: "system", Content: systemContext}}, history...)
+	// --- End Message Build ---
 
-	// --- Prepare OpenAI streaming request ---
-	body := map[string]any{
-		"model":    s.config.OpenAIModel,
-		"messages": openAIMessages,
-		"stream":   true,
-	}
-	bodyBytes, _ := json.Marshal(body)
+	// --- Generate in the background; the reply streams as SSE ---
+	scope := chatToolScope{Username: payload.Username, RecommendationID: recoID}
+	stream := s.replies.start(payload.Username, func(ctx context.Context, stream *replyStream) {
+		reply, err := s.streamChatReply(ctx, stream, scope, llmMessages)
+		if err != nil {
+			return
+		}
+		writeCitations(stream, reply, sources)
+		stream.send(replyEventDone, fiber.Map{})
+	})
+	return serveReplyStream(c, stream, 0)
+}
 
-	reqOpenAI, err := http.NewRequest("POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		log.Printf("[CHAT-STREAM] Build OpenAI request error: %v", err)
-		fmt.Fprintf(writer, "event: error\ndata: build error\n\n")
-		writer.Flush()
+// streamChatReply sends each fragment of the model's reply as a "delta"
+// event, then the token usage, and returns the whole reply. When chat tools
+// are enabled and the provider supports them, the model may call tools on
+// scope's data first (see runChatAgent). On failure it sends an error event.
+func (s *Server) streamChatReply(ctx context.Context, stream *replyStream, scope chatToolScope, messages []aiMessage) (string, error) {
+	ctx = withLLMCaller(ctx, scope.Username, llmFeatureChat)
+	var reply strings.Builder
+	onDelta := func(text string) error {
+		reply.WriteString(text)
+		stream.send(replyEventDelta, fiber.Map{"text": text})
 		return nil
 	}
-	reqOpenAI.Header.Set("Content-Type", "application/json")
-	reqOpenAI.Header.Set("Authorization", "Bearer "+s.config.OpenAIAPIKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(reqOpenAI)
-	if err != nil {
-		log.Printf("[CHAT-STREAM] OpenAI connection error: %v", err)
-		fmt.Fprintf(writer, "event: error\ndata: connection failed\n\n")
-		writer.Flush()
-		return nil
+	usage := chatUsage{Provider: s.llm.Name()}
+	var err error
+	if caller, ok := s.llm.(ToolCaller); ok {
+		err = s.runChatAgent(ctx, stream, scope, caller, messages, onDelta, &usage)
+	} else if err = s.llm.ChatStream(ctx, messages, onDelta); err == nil {
+		usage.add(nil, messages, reply.String())
 	}
-	defer resp.Body.Close()
-
-	// --- Process Stream ---
-	reader := bufio.NewReader(resp.Body)
-
-	for {
-		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			if err != io.EOF {
-				log.Printf("[CHAT-STREAM] Read error: %v", err)
-			}
-			break
-		}
-
-		text := strings.TrimSpace(string(line))
-		if text == "" {
-			continue
-		}
-
-		if !strings.HasPrefix(text, "data: ") {
-			continue
+	if err != nil {
+		log.Printf("[CHAT-STREAM] %s stream error: %v", s.llm.Name(), err)
+		message := "stream failed"
+		switch {
+		case ctx.Err() != nil:
+			message = "reply cancelled"
+		case errors.Is(err, errLLMBudgetExceeded):
+			message = err.Error()
 		}
+		stream.send(replyEventError, fiber.Map{"message": message})
+		return reply.String(), err
+	}
+	stream.send(replyEventUsage, usage)
+	return reply.String(), nil
+}
 
-		data := strings.TrimSpace(strings.TrimPrefix(text, "data: "))
-		if data == "" {
-			continue
+// chatSystemContext builds the advisor system prompt for question: the
+// computed transcript analytics and recommended courses of recommendation
+// recoID (0 for none), then the transcript, course and scholarship chunks
+// retrieved for the question under citation markers. It returns the
+// retrieved chunks in marker order.
+func (s *Server) chatSystemContext(ctx context.Context, username string, recoID int64, question string) (string, []chatChunk) {
+	// 1. Base System Prompt
+	systemContext := "You are EduSphere AI, an academic advisor who provides personalized advice based on the user's academic context (transcript, recommended courses, and potential scholarships). Be concise and professional. You must use the provided context and sources to justify your answers."
+
+	// 2. Inject the pinned Recommendation context
+	var reco *db.Recommendation
+	if recoID > 0 {
+		// Fetch the full Recommendation record
+		r, err := s.store.GetRecommendation(ctx, recoID)
+		if err == nil && r.UserUsername != username {
+			err = fmt.Errorf("recommendation belongs to another user")
 		}
 
-		if data == "[DONE]" {
-			break
-		}
+		if err == nil {
+			reco = &r
+			var contextBuilder strings.Builder
+
+			// 2a. Inject computed credits/GPA so advice uses real numbers
+			if r.TranscriptID.Valid {
+				analytics, anErr := s.loadTranscriptAnalytics(ctx, r.TranscriptID.Int64)
+				if anErr == nil && analytics != nil {
+					contextBuilder.WriteString(fmt.Sprintf("\n\n[TRANSCRIPT ANALYTICS (COMPUTED, AUTHORITATIVE)]\n%s", analytics.promptText()))
+				}
+			}
 
-		// Parse OpenAI streaming chunk
-		var chunk struct {
-			Choices []struct {
-				Delta struct {
-					Content string `json:"content"`
-				} `json:"delta"`
-			} `json:"choices"`
-		}
-		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
-			continue
-		}
-		if len(chunk.Choices) == 0 {
-			continue
-		}
+			// 2b. Inject the recommended courses, one line each
+			var payload struct {
+				Courses []Recommendation `json:"courses"`
+			}
+			if json.Unmarshal(r.Payload, &payload) == nil && len(payload.Courses) > 0 {
+				contextBuilder.WriteString("\n\n[RECOMMENDED COURSES]\n")
+				for _, c := range payload.Courses {
+					contextBuilder.WriteString(fmt.Sprintf("- %s %s (Match: %.0f%%)\n", c.Code, c.Title, c.Match))
+				}
+			}
 
-		token := chunk.Choices[0].Delta.Content
-		if token == "" {
-			continue
+			if contextBuilder.Len() > 0 {
+				systemContext += contextBuilder.String()
+			}
+		} else {
+			log.Printf("[AI-CHAT] Failed to fetch Recommendation for ID: %d. Error: %v", recoID, err)
 		}
+	}
 
-		// Escape newlines for SSE format
-		escaped := strings.ReplaceAll(token, "\n", "\\n")
-		fmt.Fprintf(writer, "data: %s\n\n", escaped)
-		writer.Flush()
+	// 3. Inject only the sources relevant to the question
+	topK := s.config.ChatRetrievalTopK
+	if topK <= 0 {
+		topK = defaultChatRetrievalTopK
 	}
+	chunks := s.chatChunks(ctx, username, reco)
+	retrieved := s.retrieveChatChunks(ctx, chunks, question, topK)
+	systemContext += chatSourcesPrompt(retrieved)
+	log.Printf("[AI-CHAT] Retrieved %d of %d chunks for user %s", len(retrieved), len(chunks), username)
 
-	fmt.Fprint(writer, "data: [DONE]\n\n")
-	writer.Flush()
-	return nil
-}
\ No newline at end of file
+	return systemContext, retrieved
+}
@@ -0,0 +1,364 @@
+// server/api/chat_retrieval.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+const (
+	defaultChatRetrievalTopK = 6
+	transcriptChunkChars     = 800
+	chatChunkPromptChars     = 1000
+	citationSnippetChars     = 200
+
+	// Chunks less similar than this to the question are never injected.
+	chatRetrievalMinScore = 0.05
+)
+
+// Sources a chat chunk comes from, sent as "source" in citation events.
+const (
+	chunkSourceTranscript  = "transcript"
+	chunkSourceCourse      = "course"
+	chunkSourceScholarship = "scholarship"
+)
+
+// chatChunk is a passage the chat can ground a reply on.
+type chatChunk struct {
+	Source string
+	RefID  int64 // transcript, course or scholarship ID
+	Title  string
+	URL    string
+	Text   string
+
+	course *db.Course // course chunks reuse the stored course vectors
+}
+
+// chatCitation is the data of an "event: citation" SSE event: the source
+// behind marker [n] in the reply.
+type chatCitation struct {
+	Marker  int    `json:"marker"`
+	Source  string `json:"source"`
+	RefID   int64  `json:"ref_id"`
+	Title   string `json:"title"`
+	URL     string `json:"url,omitempty"`
+	Snippet string `json:"snippet"`
+}
+
+var citationMarkerRe = regexp.MustCompile(`\[(\d{1,2})\]`)
+
+// -----------------------------------------------------------------------------
+// CHUNKING
+// -----------------------------------------------------------------------------
+
+// chunkText splits text into chunks of about size characters, breaking
+// between lines, or between words for lines longer than size.
+func chunkText(text string, size int) []string {
+	var pieces []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		for len(line) > size {
+			cut := strings.LastIndex(line[:size], " ")
+			if cut <= 0 {
+				// No space: cut at the last rune boundary
+				cut = size
+				for cut > 1 && !utf8.RuneStart(line[cut]) {
+					cut--
+				}
+			}
+			pieces = append(pieces, strings.TrimSpace(line[:cut]))
+			line = strings.TrimSpace(line[cut:])
+		}
+		if line != "" {
+			pieces = append(pieces, line)
+		}
+	}
+
+	var chunks []string
+	var sb strings.Builder
+	for _, p := range pieces {
+		if sb.Len() > 0 && sb.Len()+1+len(p) > size {
+			chunks = append(chunks, sb.String())
+			sb.Reset()
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(p)
+	}
+	if sb.Len() > 0 {
+		chunks = append(chunks, sb.String())
+	}
+	return chunks
+}
+
+func transcriptChunks(tr db.Transcript) []chatChunk {
+	if !tr.TextExtracted.Valid {
+		return nil
+	}
+	parts := chunkText(tr.TextExtracted.String, transcriptChunkChars)
+	chunks := make([]chatChunk, 0, len(parts))
+	for i, text := range parts {
+		chunks = append(chunks, chatChunk{
+			Source: chunkSourceTranscript,
+			RefID:  tr.ID,
+			Title:  fmt.Sprintf("Transcript, part %d of %d", i+1, len(parts)),
+			Text:   text,
+		})
+	}
+	return chunks
+}
+
+// courseChunk returns false for courses without learning outcomes.
+func courseChunk(c db.Course) (chatChunk, bool) {
+	if !c.LearningOutcomes.Valid || strings.TrimSpace(c.LearningOutcomes.String) == "" {
+		return chatChunk{}, false
+	}
+	text := "Learning outcomes: " + strings.TrimSpace(c.LearningOutcomes.String)
+	if c.Prerequisites.Valid && strings.TrimSpace(c.Prerequisites.String) != "" {
+		text += "\nPrerequisites: " + strings.TrimSpace(c.Prerequisites.String)
+	}
+	return chatChunk{
+		Source: chunkSourceCourse,
+		RefID:  c.ID,
+		Title:  c.Code + " " + c.Name,
+		URL:    c.CourseLink.String,
+		Text:   text,
+		course: &c,
+	}, true
+}
+
+func scholarshipChunk(sch db.ListUserScholarshipsRow) chatChunk {
+	parts := []string{sch.Title}
+	if details := scholarshipDetails(sch); details != "" {
+		parts = append(parts, details)
+	}
+	if sch.Description.Valid {
+		parts = append(parts, sch.Description.String)
+	}
+	return chatChunk{
+		Source: chunkSourceScholarship,
+		RefID:  sch.ID,
+		Title:  sch.Title,
+		URL:    sch.CanonicalUrl,
+		Text:   strings.Join(parts, "\n"),
+	}
+}
+
+// chatChunks collects what the chat may cite: the transcript of the pinned
+// recommendation (nil for none), the catalog's learning outcomes and the
+// user's scholarship matches. Sources that fail to load are logged and left out.
+func (s *Server) chatChunks(ctx context.Context, username string, reco *db.Recommendation) []chatChunk {
+	var chunks []chatChunk
+	if reco != nil && reco.TranscriptID.Valid {
+		tr, err := s.store.GetTranscript(ctx, reco.TranscriptID.Int64)
+		if err != nil {
+			log.Printf("[AI-CHAT] Load transcript %d failed: %v", reco.TranscriptID.Int64, err)
+		} else {
+			chunks = append(chunks, transcriptChunks(tr)...)
+		}
+	}
+
+	courses, err := s.store.ListActiveCourses(ctx)
+	if err != nil {
+		log.Printf("[AI-CHAT] Load courses failed: %v", err)
+	}
+	for _, c := range courses {
+		if chunk, ok := courseChunk(c); ok {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	scholarships, err := s.store.ListUserScholarships(ctx, db.ListUserScholarshipsParams{
+		UserUsername: username,
+		Limit:        scholarshipContextLimit,
+	})
+	if err != nil {
+		log.Printf("[AI-CHAT] Failed to fetch scholarships for user %s: %v", username, err)
+	}
+	for _, sch := range scholarships {
+		chunks = append(chunks, scholarshipChunk(sch))
+	}
+	return chunks
+}
+
+// -----------------------------------------------------------------------------
+// RANKING
+// -----------------------------------------------------------------------------
+
+// retrieveChatChunks returns up to topK chunks relevant to question, most
+// similar first. Similarity comes from the configured embedder; when
+// embedding fails the chunks are ranked by BM25 instead.
+func (s *Server) retrieveChatChunks(ctx context.Context, chunks []chatChunk, question string, topK int) []chatChunk {
+	if topK <= 0 || len(chunks) == 0 || strings.TrimSpace(question) == "" {
+		return nil
+	}
+
+	scores, err := s.chunkSimilarities(ctx, chunks, question)
+	minScore := chatRetrievalMinScore
+	if err != nil {
+		log.Printf("[WARN] Chat retrieval falls back to BM25: %v", err)
+		scores, minScore = lexicalChunkScores(chunks, question), 0
+	}
+
+	order := make([]int, len(chunks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	var out []chatChunk
+	for _, i := range order {
+		if len(out) == topK || scores[i] <= minScore {
+			break
+		}
+		out = append(out, chunks[i])
+	}
+	return out
+}
+
+// chunkSimilarities returns the cosine similarity of each chunk to question.
+// Course chunks use the stored course vectors; the rest are embedded here.
+func (s *Server) chunkSimilarities(ctx context.Context, chunks []chatChunk, question string) ([]float64, error) {
+	qv, err := s.embedder.Embed(ctx, []string{question})
+	if err != nil {
+		return nil, err
+	}
+	if len(qv) != 1 {
+		return nil, fmt.Errorf("%s returned %d vectors for the question", s.embedder.Name(), len(qv))
+	}
+
+	var courses []db.Course
+	var others []int
+	for i, c := range chunks {
+		if c.course != nil {
+			courses = append(courses, *c.course)
+		} else {
+			others = append(others, i)
+		}
+	}
+	var courseVecs map[int64][]float32
+	if len(courses) > 0 {
+		if courseVecs, err = s.courseVectors(ctx, courses); err != nil {
+			return nil, err
+		}
+	}
+
+	vectors := make([][]float32, len(chunks))
+	for i, c := range chunks {
+		if c.course != nil {
+			vectors[i] = courseVecs[c.course.ID]
+		}
+	}
+	for start := 0; start < len(others); start += embedBatchSize {
+		batch := others[start:min(start+embedBatchSize, len(others))]
+		texts := make([]string, len(batch))
+		for j, i := range batch {
+			texts[j] = chunks[i].Title + "\n" + chunks[i].Text
+		}
+		embedded, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(embedded) != len(batch) {
+			return nil, fmt.Errorf("%s returned %d vectors for %d texts", s.embedder.Name(), len(embedded), len(batch))
+		}
+		for j, i := range batch {
+			vectors[i] = embedded[j]
+		}
+	}
+
+	scores := make([]float64, len(chunks))
+	for i, v := range vectors {
+		scores[i] = cosineSimilarity(qv[0], v)
+	}
+	return scores, nil
+}
+
+func lexicalChunkScores(chunks []chatChunk, question string) []float64 {
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Title + "\n" + c.Text
+	}
+	idx := newBM25Index(texts)
+	query := make(map[string]float64)
+	for _, tok := range embedTokens(question) {
+		query[tok] = 1
+	}
+	scores := make([]float64, len(chunks))
+	for i := range chunks {
+		scores[i] = idx.score(i, query)
+	}
+	return scores
+}
+
+// -----------------------------------------------------------------------------
+// CITATIONS
+// -----------------------------------------------------------------------------
+
+// chatSourcesPrompt lists the retrieved chunks under their citation markers.
+func chatSourcesPrompt(chunks []chatChunk) string {
+	if len(chunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n[SOURCES]\n")
+	sb.WriteString("Ground your answer in these sources. Cite a source with its marker, e.g. [1], right after the sentence that uses it. Cite only the sources listed here.\n")
+	for i, c := range chunks {
+		sb.WriteString(fmt.Sprintf("\n[%d] (%s) %s\n%s\n", i+1, c.Source, c.Title, truncate(c.Text, chatChunkPromptChars)))
+	}
+	return sb.String()
+}
+
+// replyCitations returns the sources cited in reply, in order of first
+// citation. Markers without a matching source are ignored.
+func replyCitations(reply string, chunks []chatChunk) []chatCitation {
+	var citations []chatCitation
+	seen := make(map[int]bool)
+	for _, m := range citationMarkerRe.FindAllStringSubmatch(reply, -1) {
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 || n > len(chunks) || seen[n] {
+			continue
+		}
+		seen[n] = true
+		c := chunks[n-1]
+		citations = append(citations, chatCitation{
+			Marker:  n,
+			Source:  c.Source,
+			RefID:   c.RefID,
+			Title:   c.Title,
+			URL:     c.URL,
+			Snippet: citationSnippet(c.Text),
+		})
+	}
+	return citations
+}
+
+// citationSnippet is the start of a chunk on one line, cut between words.
+func citationSnippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= citationSnippetChars {
+		return text
+	}
+	cut := strings.LastIndex(text[:citationSnippetChars], " ")
+	if cut <= 0 {
+		cut = citationSnippetChars
+	}
+	return strings.ToValidUTF8(text[:cut], "") + "..."
+}
+
+// writeCitations sends one "citation" event per source cited in reply.
+func writeCitations(stream *replyStream, reply string, chunks []chatChunk) {
+	for _, citation := range replyCitations(reply, chunks) {
+		stream.send(replyEventCitation, citation)
+	}
+}
@@ -0,0 +1,145 @@
+// server/api/chat_retrieval_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkText(t *testing.T) {
+	text := "Line one\n\n   Line   two  \n" + strings.Repeat("word ", 30)
+
+	chunks := chunkText(text, 40)
+	require.Equal(t, "Line one\nLine two", chunks[0])
+	for _, c := range chunks {
+		require.LessOrEqual(t, len(c), 40)
+	}
+	require.Equal(t, 30, strings.Count(strings.Join(chunks, " "), "word"))
+
+	// A long word is cut on a rune boundary
+	chunks = chunkText(strings.Repeat("ä", 10), 5)
+	require.Equal(t, []string{"ää", "ää", "ää", "ää", "ää"}, chunks)
+
+	require.Empty(t, chunkText("  \n ", 40))
+}
+
+func TestRetrieveChatChunks(t *testing.T) {
+	server := &Server{embedder: newHashingEmbedder(hashingEmbedderDims)}
+	chunks := []chatChunk{
+		{Source: chunkSourceTranscript, Title: "Transcript, part 1 of 2", Text: "History of Finnish literature 5 cr grade 4"},
+		{Source: chunkSourceScholarship, RefID: 3, Title: "Nordic Master Scholarship", Text: "Funding for master's students in machine learning and data science"},
+		{Source: chunkSourceTranscript, Title: "Transcript, part 2 of 2", Text: "Introduction to machine learning 5 cr grade 5"},
+	}
+
+	got := server.retrieveChatChunks(context.Background(), chunks, "Is there funding for machine learning?", 2)
+	require.Len(t, got, 2)
+	require.Equal(t, "Nordic Master Scholarship", got[0].Title)
+	require.Equal(t, "Transcript, part 2 of 2", got[1].Title)
+
+	require.Empty(t, server.retrieveChatChunks(context.Background(), chunks, "quantum chromodynamics", 2))
+	require.Empty(t, server.retrieveChatChunks(context.Background(), chunks, " ", 2))
+	require.Empty(t, server.retrieveChatChunks(context.Background(), nil, "machine learning", 2))
+}
+
+func TestRetrieveChatChunksFallsBackToBM25(t *testing.T) {
+	embeddings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer embeddings.Close()
+
+	server := &Server{embedder: newOpenAICompatibleEmbedder(embeddings.URL, "", "test-model")}
+	chunks := []chatChunk{
+		{Source: chunkSourceTranscript, Title: "Transcript", Text: "History of Finnish literature"},
+		{Source: chunkSourceScholarship, Title: "Nordic Master Scholarship", Text: "Funding for machine learning students"},
+	}
+
+	got := server.retrieveChatChunks(context.Background(), chunks, "machine learning funding", 5)
+	require.Len(t, got, 1)
+	require.Equal(t, "Nordic Master Scholarship", got[0].Title)
+}
+
+func TestChatChunks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := &Server{store: store}
+	username := "student1"
+	reco := &db.Recommendation{ID: 1, TranscriptID: sql.NullInt64{Int64: 4, Valid: true}}
+
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(int64(4))).Times(1).
+		Return(db.Transcript{ID: 4, TextExtracted: sql.NullString{String: "TIES454 Machine Learning 5 cr 5", Valid: true}}, nil)
+	store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).Return([]db.Course{
+		{ID: 9, Code: "TIES454", Name: "Machine Learning", LearningOutcomes: sql.NullString{String: "Train models.", Valid: true}},
+		{ID: 10, Code: "TIES100", Name: "No Outcomes"},
+	}, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Eq(db.ListUserScholarshipsParams{UserUsername: username, Limit: scholarshipContextLimit})).
+		Times(1).Return([]db.ListUserScholarshipsRow{{
+		ID: 3, Title: "Nordic Master Scholarship", CanonicalUrl: "https://example.org/nordic",
+		Amount: sql.NullFloat64{Float64: 5000, Valid: true}, Currency: sql.NullString{String: "EUR", Valid: true},
+	}}, nil)
+
+	chunks := server.chatChunks(context.Background(), username, reco)
+	require.Len(t, chunks, 3)
+
+	require.Equal(t, chunkSourceTranscript, chunks[0].Source)
+	require.Equal(t, "Transcript, part 1 of 1", chunks[0].Title)
+
+	require.Equal(t, chunkSourceCourse, chunks[1].Source)
+	require.Equal(t, "TIES454 Machine Learning", chunks[1].Title)
+	require.Equal(t, "Learning outcomes: Train models.", chunks[1].Text)
+	require.Equal(t, int64(9), chunks[1].course.ID)
+
+	require.Equal(t, chunkSourceScholarship, chunks[2].Source)
+	require.Equal(t, "https://example.org/nordic", chunks[2].URL)
+	require.Equal(t, "Nordic Master Scholarship\nAmount: 5000 EUR", chunks[2].Text)
+}
+
+func TestReplyCitations(t *testing.T) {
+	chunks := []chatChunk{
+		{Source: chunkSourceCourse, RefID: 9, Title: "TIES454 Machine Learning", URL: "https://example.edu/ties454", Text: "Learning outcomes: Train models."},
+		{Source: chunkSourceScholarship, RefID: 3, Title: "Nordic Master Scholarship", Text: strings.Repeat("funding ", 40)},
+	}
+
+	citations := replyCitations("Apply for the scholarship [2] and take TIES454 [1][2]. See [7].", chunks)
+	require.Len(t, citations, 2)
+	require.Equal(t, 2, citations[0].Marker)
+	require.Equal(t, int64(3), citations[0].RefID)
+	require.LessOrEqual(t, len(citations[0].Snippet), citationSnippetChars+3)
+	require.True(t, strings.HasSuffix(citations[0].Snippet, "funding..."))
+	require.Equal(t, chatCitation{
+		Marker: 1, Source: chunkSourceCourse, RefID: 9, Title: "TIES454 Machine Learning",
+		URL: "https://example.edu/ties454", Snippet: "Learning outcomes: Train models.",
+	}, citations[1])
+
+	require.Empty(t, replyCitations("No sources used.", chunks))
+
+	stream := newReplyStream("student")
+	writeCitations(stream, "Take TIES454 [1].", chunks)
+	require.Equal(t, []replyEvent{{
+		Seq:  1,
+		Name: replyEventCitation,
+		Data: []byte(`{"marker":1,"source":"course","ref_id":9,"title":"TIES454 Machine Learning","url":"https://example.edu/ties454","snippet":"Learning outcomes: Train models."}`),
+	}}, stream.events)
+}
+
+func TestChatSourcesPrompt(t *testing.T) {
+	require.Empty(t, chatSourcesPrompt(nil))
+
+	prompt := chatSourcesPrompt([]chatChunk{
+		{Source: chunkSourceTranscript, Title: "Transcript, part 1 of 1", Text: "TIES454 5 cr"},
+		{Source: chunkSourceCourse, Title: "TIES454 Machine Learning", Text: "Learning outcomes: Train models."},
+	})
+	require.Contains(t, prompt, "[1] (transcript) Transcript, part 1 of 1\nTIES454 5 cr\n")
+	require.Contains(t, prompt, "[2] (course) TIES454 Machine Learning\nLearning outcomes: Train models.\n")
+}
@@ -0,0 +1,288 @@
+// server/api/chat_stream.go
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Events of a chat reply stream. Every event has an "id: <stream>:<seq>"
+// field and JSON data.
+const (
+	replyEventDelta      = "delta"
+	replyEventToolCall   = "tool_call"
+	replyEventToolResult = "tool_result"
+	replyEventUsage      = "usage"
+	replyEventCitation   = "citation"
+	replyEventSaved      = "saved"
+	replyEventError      = "error"
+	replyEventDone       = "done"
+)
+
+const (
+	// A reply keeps generating this long after its last client went away,
+	// so that a reconnect with Last-Event-ID can pick it up; then it is cancelled.
+	replyResumeGrace = 10 * time.Second
+	// A finished reply can be replayed for this long.
+	replyRetention = 2 * time.Minute
+)
+
+// replyEvent is one event of a reply stream. Seq starts at 1.
+type replyEvent struct {
+	Seq  int64
+	Name string
+	Data []byte
+}
+
+// chatUsage is the data of the "usage" event: the tokens used by the reply,
+// over every tool round. Estimated is set when the provider reported no
+// usage for some round and it was estimated from the text instead.
+type chatUsage struct {
+	Provider         string `json:"provider"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	Estimated        bool   `json:"estimated"`
+}
+
+// add counts one completion: the provider's usage, or an estimate from the
+// prompt and reply when it is nil.
+func (u *chatUsage) add(usage *llmUsage, messages []aiMessage, reply string) {
+	if usage == nil {
+		usage = estimateLLMUsage(messages, reply)
+		u.Estimated = true
+	}
+	u.PromptTokens += usage.PromptTokens
+	u.CompletionTokens += usage.CompletionTokens
+	u.TotalTokens += usage.TotalTokens
+}
+
+// replyStream is one chat reply being generated in the background. Its
+// events are kept until replyRetention after it ends, so a client that
+// reconnects with Last-Event-ID receives the ones it missed.
+type replyStream struct {
+	id       string
+	username string
+	cancel   context.CancelFunc
+
+	mu      sync.Mutex
+	events  []replyEvent
+	done    bool
+	changed chan struct{} // closed and replaced when an event is added or the stream ends
+	clients int
+	idle    *time.Timer
+}
+
+func newReplyStream(username string) *replyStream {
+	return &replyStream{
+		id:       uuid.NewString(),
+		username: username,
+		cancel:   func() {},
+		changed:  make(chan struct{}),
+	}
+}
+
+// send appends an event with data encoded as JSON. Events sent after the
+// stream ended are dropped.
+func (st *replyStream) send(name string, data any) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("[CHAT-STREAM] Encode %s event failed: %v", name, err)
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.done {
+		return
+	}
+	st.events = append(st.events, replyEvent{Seq: int64(len(st.events) + 1), Name: name, Data: b})
+	close(st.changed)
+	st.changed = make(chan struct{})
+}
+
+// finish ends the stream and cancels its context.
+func (st *replyStream) finish() {
+	st.mu.Lock()
+	st.done = true
+	close(st.changed)
+	st.changed = make(chan struct{})
+	if st.idle != nil {
+		st.idle.Stop()
+		st.idle = nil
+	}
+	st.mu.Unlock()
+	st.cancel()
+}
+
+// pending returns the events after seq, whether the stream has ended and a
+// channel closed on the next change.
+func (st *replyStream) pending(seq int64) ([]replyEvent, bool, <-chan struct{}) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	seq = max(0, min(seq, int64(len(st.events))))
+	return st.events[seq:], st.done, st.changed
+}
+
+// attach and detach count the connected clients. Once none is left the
+// reply is cancelled after replyResumeGrace.
+func (st *replyStream) attach() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.clients++
+	if st.idle != nil {
+		st.idle.Stop()
+		st.idle = nil
+	}
+}
+
+func (st *replyStream) detach() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.clients--
+	if st.clients == 0 && !st.done {
+		st.idle = time.AfterFunc(replyResumeGrace, st.cancel)
+	}
+}
+
+// serve writes the events after seq to w as they are sent, until the
+// stream ends or the client goes away.
+func (st *replyStream) serve(w *bufio.Writer, seq int64) {
+	st.attach()
+	defer st.detach()
+
+	keepAlive := time.NewTicker(eventsKeepAliveTime)
+	defer keepAlive.Stop()
+
+	for {
+		events, done, changed := st.pending(seq)
+		for _, ev := range events {
+			fmt.Fprintf(w, "id: %s:%d\nevent: %s\ndata: %s\n\n", st.id, ev.Seq, ev.Name, ev.Data)
+			seq = ev.Seq
+		}
+		if len(events) > 0 {
+			if err := w.Flush(); err != nil {
+				return // client went away
+			}
+		}
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// replyStreamHub holds the running and recently finished reply streams.
+type replyStreamHub struct {
+	mu      sync.Mutex
+	streams map[string]*replyStream
+}
+
+func newReplyStreamHub() *replyStreamHub {
+	return &replyStreamHub{streams: make(map[string]*replyStream)}
+}
+
+// start runs generate in the background on a new stream of the user. The
+// stream ends when generate returns; generate's context is cancelled when
+// the stream is cancelled or left without clients.
+func (h *replyStreamHub) start(username string, generate func(ctx context.Context, stream *replyStream)) *replyStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newReplyStream(username)
+	stream.cancel = cancel
+
+	h.mu.Lock()
+	h.streams[stream.id] = stream
+	h.mu.Unlock()
+
+	go func() {
+		defer func() {
+			stream.finish()
+			time.AfterFunc(replyRetention, func() { h.remove(stream.id) })
+		}()
+		generate(ctx, stream)
+	}()
+	return stream
+}
+
+// get returns the user's stream with the given ID.
+func (h *replyStreamHub) get(username, id string) (*replyStream, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stream, ok := h.streams[id]
+	if !ok || stream.username != username {
+		return nil, false
+	}
+	return stream, true
+}
+
+func (h *replyStreamHub) remove(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.streams, id)
+}
+
+// serveReplyStream sets the SSE headers and streams the events after seq as
+// the response body.
+func serveReplyStream(c *fiber.Ctx, stream *replyStream, seq int64) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Stream-ID", stream.id)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		stream.serve(w, seq)
+	})
+	return nil
+}
+
+// resumeReplyStream serves the stream named by a Last-Event-ID header
+// ("<stream>:<seq>") from the event after seq.
+func (s *Server) resumeReplyStream(c *fiber.Ctx, username, lastEventID string) error {
+	id, seqText, ok := strings.Cut(strings.TrimSpace(lastEventID), ":")
+	seq, err := strconv.ParseInt(seqText, 10, 64)
+	if !ok || err != nil || seq < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("invalid Last-Event-ID")))
+	}
+
+	stream, ok := s.replies.get(username, id)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("stream not found or expired")))
+	}
+	return serveReplyStream(c, stream, seq)
+}
+
+// DELETE /api/chat/streams/:id
+// Stops generating a reply; its stream ends with an error event.
+func (s *Server) cancelReplyStream(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	stream, ok := s.replies.get(payload.Username, c.Params("id"))
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("stream not found or expired")))
+	}
+	stream.cancel()
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,193 @@
+// server/api/chat_stream_test.go
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// errWriter fails every write, like a client that went away.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestReplyStreamServe(t *testing.T) {
+	stream := newReplyStream("student")
+	stream.send(replyEventDelta, fiber.Map{"text": "Hello\nthere"})
+	stream.send(replyEventDelta, fiber.Map{"text": "!"})
+	stream.finish()
+	stream.send(replyEventDelta, fiber.Map{"text": "dropped"})
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	stream.serve(w, 1)
+	require.Equal(t, fmt.Sprintf("id: %s:2\nevent: delta\ndata: {\"text\":\"!\"}\n\n", stream.id), buf.String())
+
+	// Newlines in a fragment stay inside the JSON string
+	buf.Reset()
+	stream.serve(w, 0)
+	require.Contains(t, buf.String(), `data: {"text":"Hello\nthere"}`)
+
+	// A seq past the end replays nothing
+	buf.Reset()
+	stream.serve(w, 9)
+	require.Empty(t, buf.String())
+}
+
+func TestReplyStreamDisconnect(t *testing.T) {
+	hub := newReplyStreamHub()
+	cancelled := make(chan struct{})
+	stream := hub.start("student", func(ctx context.Context, stream *replyStream) {
+		stream.send(replyEventDelta, fiber.Map{"text": "Hi"})
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	// The write fails, so the reply is left without clients and will be
+	// cancelled after the grace period unless a client reconnects
+	stream.serve(bufio.NewWriterSize(errWriter{}, 16), 0)
+	stream.mu.Lock()
+	require.Zero(t, stream.clients)
+	require.NotNil(t, stream.idle)
+	stream.mu.Unlock()
+
+	stream.attach()
+	stream.mu.Lock()
+	require.Nil(t, stream.idle)
+	stream.mu.Unlock()
+	stream.detach()
+
+	stream.cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("generation was not cancelled")
+	}
+}
+
+func TestResumeReplyStreamAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newFiberTestServer(t, mockdb.NewMockStore(ctrl))
+	stream := server.replies.start(username, func(ctx context.Context, stream *replyStream) {
+		stream.send(replyEventDelta, fiber.Map{"text": "Take "})
+		stream.send(replyEventDelta, fiber.Map{"text": "TIES454."})
+		stream.send(replyEventDone, fiber.Map{})
+	})
+
+	testCases := []struct {
+		name          string
+		username      string
+		lastEventID   string
+		checkResponse func(t *testing.T, resp *http.Response, body string)
+	}{
+		{
+			name:        "OK",
+			username:    username,
+			lastEventID: stream.id + ":1",
+			checkResponse: func(t *testing.T, resp *http.Response, body string) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, stream.id, resp.Header.Get("X-Stream-ID"))
+				require.Equal(t, fmt.Sprintf("id: %[1]s:2\nevent: delta\ndata: {\"text\":\"TIES454.\"}\n\nid: %[1]s:3\nevent: done\ndata: {}\n\n", stream.id), body)
+			},
+		},
+		{
+			name:        "OtherUser",
+			username:    util.RandomOwner(),
+			lastEventID: stream.id + ":1",
+			checkResponse: func(t *testing.T, resp *http.Response, body string) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:        "Invalid",
+			username:    username,
+			lastEventID: stream.id,
+			checkResponse: func(t *testing.T, resp *http.Response, body string) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", nil)
+			req.Header.Set("Last-Event-ID", tc.lastEventID)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, tc.username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp, string(body))
+		})
+	}
+}
+
+func TestCancelReplyStreamAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newFiberTestServer(t, mockdb.NewMockStore(ctrl))
+	server.llm = blockingLLM{newFakeLLMProvider()}
+	stream := server.replies.start(username, func(ctx context.Context, stream *replyStream) {
+		server.streamChatReply(ctx, stream, chatToolScope{Username: username}, []aiMessage{{Role: "user", Content: "hi"}})
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/chat/streams/"+stream.id, nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), util.StudentRole, time.Minute)
+	resp, err := server.app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/chat/streams/"+stream.id, nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+	resp, err = server.app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// The stream ends with an error event
+	var buf bytes.Buffer
+	stream.serve(bufio.NewWriter(&buf), 0)
+	require.Contains(t, buf.String(), "event: error\ndata: {\"message\":\"reply cancelled\"}\n\n")
+}
+
+func TestChatUsageAdd(t *testing.T) {
+	var usage chatUsage
+	usage.add(&llmUsage{PromptTokens: 20, CompletionTokens: 7, TotalTokens: 27}, nil, "")
+	require.Equal(t, chatUsage{PromptTokens: 20, CompletionTokens: 7, TotalTokens: 27}, usage)
+
+	messages := []aiMessage{{Role: "user", Content: "12345678"}}
+	usage.add(nil, messages, "1234")
+	require.Equal(t, chatUsage{PromptTokens: 26, CompletionTokens: 12, TotalTokens: 38, Estimated: true}, usage)
+}
+
+// blockingLLM streams nothing until its context is cancelled.
+type blockingLLM struct {
+	*fakeLLMProvider
+}
+
+func (blockingLLM) ChatStreamTools(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error) {
+	<-ctx.Done()
+	return llmStreamResult{}, ctx.Err()
+}
@@ -0,0 +1,513 @@
+// server/api/chat_tools.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+const (
+	// The model may call tools for this many rounds before it must answer.
+	maxChatToolRounds = 4
+	// Tool results longer than this are cut before they go back to the model.
+	chatToolResultChars = 4000
+
+	defaultToolCourseLimit = 5
+	defaultToolCallLimit   = 50
+)
+
+// Tools the chat assistant may call.
+const (
+	chatToolSearchCourses      = "search_courses"
+	chatToolCheckPrerequisites = "check_prerequisites"
+	chatToolRemoveCourse       = "remove_course_from_recommendation"
+	chatToolFindScholarships   = "find_scholarships"
+	chatToolCreateSummaryPDF   = "create_summary_pdf"
+)
+
+// Statuses of chat_tool_calls rows.
+const (
+	chatToolStatusOK    = "ok"
+	chatToolStatusError = "error"
+)
+
+// chatToolScope is whose data the chat's tools act on.
+type chatToolScope struct {
+	Username         string
+	ConversationID   int64 // 0 for /api/chat/stream
+	RecommendationID int64 // the pinned recommendation, 0 for none
+}
+
+// chatToolFunc runs a tool with the model's arguments. Errors are reported
+// back to the model, which may correct its call.
+type chatToolFunc func(ctx context.Context, scope chatToolScope, args json.RawMessage) (any, error)
+
+// chatTool is a server-side function offered to the chat model.
+type chatTool struct {
+	Description string
+	Parameters  string // JSON Schema of the arguments
+	Run         chatToolFunc
+}
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+type searchCoursesArgs struct {
+	Query string `json:"query" validate:"required,max=200"`
+	Limit int    `json:"limit" validate:"min=0,max=10"`
+}
+
+type checkPrerequisitesArgs struct {
+	CourseCode string `json:"course_code" validate:"required,max=20"`
+}
+
+type removeCourseArgs struct {
+	RecommendationID int64  `json:"recommendation_id" validate:"min=0"`
+	CourseCode       string `json:"course_code" validate:"required,max=20"`
+}
+
+type createSummaryPDFArgs struct {
+	RecommendationID    int64 `json:"recommendation_id" validate:"min=0"`
+	IncludeScholarships bool  `json:"include_scholarships"`
+}
+
+type listChatToolCallsRequest struct {
+	Username string `query:"username" validate:"max=100"`
+	Limit    int    `query:"limit" validate:"min=0,max=500"`
+}
+
+type chatToolCallResponse struct {
+	ID             int64           `json:"id"`
+	Username       string          `json:"username"`
+	ConversationID *int64          `json:"conversation_id,omitempty"`
+	ToolCallID     string          `json:"tool_call_id"`
+	ToolName       string          `json:"tool_name"`
+	Arguments      json.RawMessage `json:"arguments"`
+	Result         json.RawMessage `json:"result,omitempty"`
+	Status         string          `json:"status"`
+	Error          string          `json:"error,omitempty"`
+	DurationMs     int32           `json:"duration_ms"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func newChatToolCallResponse(row db.ChatToolCall) chatToolCallResponse {
+	resp := chatToolCallResponse{
+		ID:         row.ID,
+		Username:   row.UserUsername,
+		ToolCallID: row.ToolCallID,
+		ToolName:   row.ToolName,
+		Arguments:  row.Arguments,
+		Result:     row.Result,
+		Status:     row.Status,
+		Error:      row.Error.String,
+		DurationMs: row.DurationMs,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.ConversationID.Valid {
+		resp.ConversationID = &row.ConversationID.Int64
+	}
+	return resp
+}
+
+// ---------------------------
+// Tools
+// ---------------------------
+
+// chatTools maps each tool name to its definition.
+func (s *Server) chatTools() map[string]chatTool {
+	return map[string]chatTool{
+		chatToolSearchCourses: {
+			Description: "Search the course catalog by topic. Returns the closest active courses with their codes and learning outcomes.",
+			Parameters:  `{"type":"object","properties":{"query":{"type":"string","description":"Topic or skills to search for"},"limit":{"type":"integer","minimum":1,"maximum":10}},"required":["query"]}`,
+			Run:         s.toolSearchCourses,
+		},
+		chatToolCheckPrerequisites: {
+			Description: "Check whether the student meets the prerequisites of a course, using their latest transcript.",
+			Parameters:  `{"type":"object","properties":{"course_code":{"type":"string","description":"Course code, e.g. TIES454"}},"required":["course_code"]}`,
+			Run:         s.toolCheckPrerequisites,
+		},
+		chatToolRemoveCourse: {
+			Description: "Remove a course from one of the student's recommendations. Only call this when the student asks for it.",
+			Parameters:  `{"type":"object","properties":{"recommendation_id":{"type":"integer","description":"Defaults to the recommendation this chat is about"},"course_code":{"type":"string"}},"required":["course_code"]}`,
+			Run:         s.toolRemoveCourse,
+		},
+		chatToolFindScholarships: {
+			Description: "Start a new web search for scholarships matching the student's transcript and profile. Results are saved to their scholarship list.",
+			Parameters:  `{"type":"object","properties":{}}`,
+			Run:         s.toolFindScholarships,
+		},
+		chatToolCreateSummaryPDF: {
+			Description: "Generate a PDF summary of a recommendation with transcript analytics, study plan and scholarships. Returns its download link.",
+			Parameters:  `{"type":"object","properties":{"recommendation_id":{"type":"integer","description":"Defaults to the recommendation this chat is about"},"include_scholarships":{"type":"boolean"}}}`,
+			Run:         s.toolCreateSummaryPDF,
+		},
+	}
+}
+
+// llmTools lists the tools in the model's format, sorted by name.
+func llmTools(tools map[string]chatTool) []llmTool {
+	out := make([]llmTool, 0, len(tools))
+	for name, tool := range tools {
+		out = append(out, llmTool{
+			Type: "function",
+			Function: llmToolFunction{
+				Name:        name,
+				Description: tool.Description,
+				Parameters:  json.RawMessage(tool.Parameters),
+			},
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Function.Name < out[j].Function.Name })
+	return out
+}
+
+// decodeToolArgs parses and validates the model's arguments into dst.
+func (s *Server) decodeToolArgs(args json.RawMessage, dst any) error {
+	if err := json.Unmarshal(args, dst); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid arguments: %v", err))
+	}
+	if err := s.validate.Struct(dst); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+// toolRecommendation returns the recommendation a tool acts on: recoID, or
+// the chat's pinned one. It must belong to the user.
+func (s *Server) toolRecommendation(ctx context.Context, scope chatToolScope, recoID int64) (db.Recommendation, error) {
+	if recoID == 0 {
+		recoID = scope.RecommendationID
+	}
+	if recoID == 0 {
+		return db.Recommendation{}, fiber.NewError(fiber.StatusBadRequest, "recommendation_id is required: no recommendation is pinned to this chat")
+	}
+	reco, err := s.store.GetRecommendation(ctx, recoID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && reco.UserUsername != scope.Username) {
+		return db.Recommendation{}, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("recommendation %d not found", recoID))
+	}
+	return reco, err
+}
+
+func (s *Server) toolSearchCourses(ctx context.Context, scope chatToolScope, args json.RawMessage) (any, error) {
+	var req searchCoursesArgs
+	if err := s.decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultToolCourseLimit
+	}
+
+	courses, err := s.store.ListActiveCourses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ranked := s.retrieveCourses(ctx, courses, req.Query, req.Limit)
+	if len(ranked) > req.Limit {
+		ranked = ranked[:req.Limit]
+	}
+
+	out := make([]fiber.Map, 0, len(ranked))
+	for _, c := range ranked {
+		out = append(out, fiber.Map{
+			"code":              c.Code,
+			"name":              c.Name,
+			"learning_outcomes": truncate(c.LearningOutcomes.String, 300),
+			"link":              c.CourseLink.String,
+		})
+	}
+	return fiber.Map{"courses": out}, nil
+}
+
+func (s *Server) toolCheckPrerequisites(ctx context.Context, scope chatToolScope, args json.RawMessage) (any, error) {
+	var req checkPrerequisitesArgs
+	if err := s.decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	code := strings.ToUpper(strings.TrimSpace(req.CourseCode))
+
+	course, err := s.store.GetCourseByCode(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("course %s not found", code))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Completed courses from the latest transcript, if any
+	var completed []string
+	transcripts, err := s.store.ListTranscripts(ctx, scope.Username)
+	if err != nil {
+		return nil, err
+	}
+	if len(transcripts) > 0 {
+		tr, err := s.store.GetTranscript(ctx, transcripts[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		if completed, err = s.completedCourseCodes(ctx, tr); err != nil {
+			return nil, err
+		}
+	}
+
+	eligibility, err := s.checkEligibility(ctx, []db.Course{course}, completed)
+	if err != nil {
+		return nil, err
+	}
+	e := eligibility[course.ID]
+	return fiber.Map{
+		"course_code":      course.Code,
+		"name":             course.Name,
+		"prerequisites":    course.Prerequisites.String,
+		"status":           e.Status,
+		"missing":          e.Missing,
+		"transcript_found": len(transcripts) > 0,
+	}, nil
+}
+
+func (s *Server) toolRemoveCourse(ctx context.Context, scope chatToolScope, args json.RawMessage) (any, error) {
+	var req removeCourseArgs
+	if err := s.decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	reco, err := s.toolRecommendation(ctx, scope, req.RecommendationID)
+	if err != nil {
+		return nil, err
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.CourseCode))
+	remaining, err := s.removeRecommendedCourse(ctx, reco, scope.Username, func(course Recommendation) bool {
+		return strings.EqualFold(strings.TrimSpace(course.Code), code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(remaining))
+	for _, course := range remaining {
+		codes = append(codes, course.Code)
+	}
+	return fiber.Map{"recommendation_id": reco.ID, "removed": code, "remaining": codes}, nil
+}
+
+// toolFindScholarships queues a scholarships job, or runs it right away when
+// there are no job workers (like POST /api/scholarships/generate).
+func (s *Server) toolFindScholarships(ctx context.Context, scope chatToolScope, _ json.RawMessage) (any, error) {
+	payload := []byte("{}")
+	if s.config.JobWorkers <= 0 {
+		return runJobFunc(ctx, s.jobs.handlers[jobKindScholarships], db.Job{UserUsername: scope.Username, Kind: jobKindScholarships, Payload: payload})
+	}
+
+	job, err := s.enqueueJob(ctx, scope.Username, jobKindScholarships, payload)
+	if err != nil {
+		return nil, err
+	}
+	return fiber.Map{
+		"job_id":  job.ID,
+		"status":  job.Status,
+		"message": "The scholarship search has started; matches are saved to the student's scholarship list when it finishes.",
+	}, nil
+}
+
+func (s *Server) toolCreateSummaryPDF(ctx context.Context, scope chatToolScope, args json.RawMessage) (any, error) {
+	var req createSummaryPDFArgs
+	if err := s.decodeToolArgs(args, &req); err != nil {
+		return nil, err
+	}
+	reco, err := s.toolRecommendation(ctx, scope, req.RecommendationID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.writeSummaryPDF(ctx, scope.Username, createSummaryReq{
+		RecommendationID:    reco.ID,
+		IncludeScholarships: req.IncludeScholarships,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fiber.Map{
+		"summary_id":   row.ID,
+		"download_url": fmt.Sprintf("/api/summaries/%d/download", row.ID),
+	}, nil
+}
+
+// ---------------------------
+// Agent loop
+// ---------------------------
+
+// runChatAgent streams the model's reply while letting it call tools when
+// they are enabled: each round of calls is run and answered before the model
+// continues. After maxChatToolRounds rounds no tools are offered, so the
+// model must answer. The usage of every round is added to usage. Each round
+// after the first checks the user's daily token budget again and stops with
+// errLLMBudgetExceeded once it is used up.
+func (s *Server) runChatAgent(ctx context.Context, stream *replyStream, scope chatToolScope, caller ToolCaller, messages []aiMessage, onDelta func(delta string) error, usage *chatUsage) error {
+	var tools map[string]chatTool
+	var offered []llmTool
+	if s.config.ChatToolsEnabled {
+		tools = s.chatTools()
+		offered = llmTools(tools)
+	}
+	for round := 0; ; round++ {
+		if round == maxChatToolRounds {
+			offered = nil
+		}
+		if round > 0 {
+			reset, err := s.llmBudgetReset(ctx, scope.Username)
+			if err != nil {
+				return err
+			}
+			if !reset.IsZero() {
+				return errLLMBudgetExceeded
+			}
+		}
+
+		var text strings.Builder
+		result, err := caller.ChatStreamTools(ctx, messages, offered, func(delta string) error {
+			text.WriteString(delta)
+			return onDelta(delta)
+		})
+		if err != nil {
+			return err
+		}
+		usage.add(result.Usage, messages, text.String())
+		calls := result.ToolCalls
+		if len(calls) == 0 {
+			return nil
+		}
+
+		for i := range calls {
+			if calls[i].ID == "" {
+				calls[i].ID = fmt.Sprintf("call_%d_%d", round, i)
+			}
+		}
+		messages = append(messages, aiMessage{Role: "assistant", Content: text.String(), ToolCalls: calls})
+		for _, call := range calls {
+			messages = append(messages, aiMessage{
+				Role:       "tool",
+				ToolCallID: call.ID,
+				Content:    s.runChatTool(ctx, stream, scope, tools, call),
+			})
+		}
+	}
+}
+
+// runChatTool runs one tool call, streams it as "tool_call" and
+// "tool_result" events, records it in chat_tool_calls and returns the
+// result (or error) for the model.
+func (s *Server) runChatTool(ctx context.Context, stream *replyStream, scope chatToolScope, tools map[string]chatTool, call llmToolCall) string {
+	name := call.Function.Name
+	args := json.RawMessage(strings.TrimSpace(call.Function.Arguments))
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	var err error
+	if !json.Valid(args) {
+		// Keep the malformed text, as a JSON string
+		args, _ = json.Marshal(call.Function.Arguments)
+		err = fiber.NewError(fiber.StatusBadRequest, "arguments must be a JSON object")
+	}
+	stream.send(replyEventToolCall, fiber.Map{"id": call.ID, "name": name, "arguments": args})
+
+	start := time.Now()
+	var result any
+	tool, ok := tools[name]
+	switch {
+	case err != nil:
+	case !ok:
+		err = fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("unknown tool %q", name))
+	default:
+		result, err = tool.Run(ctx, scope, args)
+	}
+
+	arg := db.CreateChatToolCallParams{
+		UserUsername:   scope.Username,
+		ConversationID: sql.NullInt64{Int64: scope.ConversationID, Valid: scope.ConversationID > 0},
+		ToolCallID:     call.ID,
+		ToolName:       name,
+		Arguments:      args,
+		Status:         chatToolStatusOK,
+		DurationMs:     int32(time.Since(start).Milliseconds()),
+	}
+	event := fiber.Map{"id": call.ID, "name": name}
+	var content string
+	if err == nil {
+		arg.Result, err = json.Marshal(result)
+	}
+	if err != nil {
+		log.Printf("[CHAT-TOOL] %s for %s failed: %v", name, scope.Username, err)
+		message := chatToolErrorMessage(err)
+		arg.Status = chatToolStatusError
+		arg.Error = sql.NullString{String: err.Error(), Valid: true}
+		arg.Result = nil
+		event["error"] = message
+		data, _ := json.Marshal(fiber.Map{"error": message})
+		content = string(data)
+	} else {
+		event["result"] = json.RawMessage(arg.Result)
+		content = truncate(string(arg.Result), chatToolResultChars)
+	}
+	event["status"] = arg.Status
+
+	if _, err := s.store.CreateChatToolCall(ctx, arg); err != nil {
+		log.Printf("[DB] Save tool call %s for %s failed: %v", name, scope.Username, err)
+	}
+	stream.send(replyEventToolResult, event)
+	return content
+}
+
+// chatToolErrorMessage is what the model and the client see of a tool error:
+// the message of a fiber.Error, which describes a problem with the call, and
+// nothing about internal failures.
+func chatToolErrorMessage(err error) string {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return fe.Message
+	}
+	return "the tool failed, try again later"
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// GET /api/admin/chat/tool-calls?username=&limit=50
+// The audit log of tools run by the chat, newest first.
+func (s *Server) listChatToolCalls(c *fiber.Ctx) error {
+	var req listChatToolCallsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultToolCallLimit
+	}
+
+	rows, err := s.store.ListChatToolCalls(c.Context(), db.ListChatToolCallsParams{
+		Username: strings.TrimSpace(req.Username),
+		MaxCalls: int64(req.Limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	resp := make([]chatToolCallResponse, 0, len(rows))
+	for _, row := range rows {
+		resp = append(resp, newChatToolCallResponse(row))
+	}
+	return c.JSON(resp)
+}
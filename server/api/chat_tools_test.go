@@ -0,0 +1,304 @@
+// server/api/chat_tools_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatStreamToolCalls(t *testing.T) {
+	username := util.RandomOwner()
+	reco := db.Recommendation{
+		ID:           7,
+		UserUsername: username,
+		Payload:      []byte(`{"courses":[{"type":"course","title":"Machine Learning","code":"TIES454"},{"type":"course","title":"Deep Learning","code":"TIES455"}],"engine":"llm"}`),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListActiveCourses(gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(reco.ID)).Times(1).Return(reco, nil)
+	store.EXPECT().UpdateRecommendationPayload(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.UpdateRecommendationPayloadParams) (db.Recommendation, error) {
+			require.Equal(t, username, arg.UserUsername)
+			require.NotContains(t, string(arg.Payload), "TIES454")
+			require.Contains(t, string(arg.Payload), `"engine":"llm"`)
+			return db.Recommendation{}, nil
+		})
+
+	var audit []db.CreateChatToolCallParams
+	store.EXPECT().CreateChatToolCall(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CreateChatToolCallParams) (db.ChatToolCall, error) {
+			audit = append(audit, arg)
+			return db.ChatToolCall{}, nil
+		})
+
+	server := newFiberTestServer(t, store)
+	server.config.ChatToolsEnabled = true
+	fake := newFakeLLMProvider("Removed TIES454.")
+	fake.EnqueueToolCalls(
+		llmToolCall{ID: "call_1", Type: "function", Function: llmFunctionCall{Name: chatToolRemoveCourse, Arguments: `{"recommendation_id":7,"course_code":"ties454"}`}},
+		llmToolCall{ID: "call_2", Type: "function", Function: llmFunctionCall{Name: "drop_database", Arguments: `{}`}},
+	)
+	server.llm = fake
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", strings.NewReader(`{"messages":[{"role":"user","content":"Please drop TIES454 from my plan"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `event: tool_call
+data: {"arguments":{"recommendation_id":7,"course_code":"ties454"},"id":"call_1","name":"remove_course_from_recommendation"}`)
+	require.Contains(t, string(body), `event: tool_result
+data: {"id":"call_1","name":"remove_course_from_recommendation","result":{"recommendation_id":7,"remaining":["TIES455"],"removed":"TIES454"},"status":"ok"}`)
+	require.Contains(t, string(body), `event: tool_result
+data: {"error":"unknown tool \"drop_database\"","id":"call_2","name":"drop_database","status":"error"}`)
+	require.Contains(t, string(body), `data: {"text":"Removed "}`)
+	require.True(t, strings.HasSuffix(string(body), "event: done\ndata: {}\n\n"))
+
+	// Both calls are audited
+	require.Len(t, audit, 2)
+	require.Equal(t, username, audit[0].UserUsername)
+	require.False(t, audit[0].ConversationID.Valid)
+	require.Equal(t, chatToolStatusOK, audit[0].Status)
+	require.JSONEq(t, `{"recommendation_id":7,"remaining":["TIES455"],"removed":"TIES454"}`, string(audit[0].Result))
+	require.Equal(t, chatToolStatusError, audit[1].Status)
+	require.Equal(t, `unknown tool "drop_database"`, audit[1].Error.String)
+
+	// The model gets the results before it answers
+	calls := fake.Calls()
+	require.Len(t, calls, 2)
+	answer := calls[1]
+	require.Equal(t, "assistant", answer[len(answer)-3].Role)
+	require.Len(t, answer[len(answer)-3].ToolCalls, 2)
+	require.Equal(t, aiMessage{Role: "tool", ToolCallID: "call_2", Content: `{"error":"unknown tool \"drop_database\""}`}, answer[len(answer)-1])
+}
+
+func TestChatStreamToolsDisabled(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListActiveCourses(gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().CreateChatToolCall(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	fake := newFakeLLMProvider("No tools here.")
+	fake.EnqueueToolCalls(llmToolCall{ID: "call_1", Function: llmFunctionCall{Name: chatToolFindScholarships}})
+	server.llm = fake
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", strings.NewReader(`{"messages":[{"role":"user","content":"Find me scholarships"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NotContains(t, string(body), "event: tool_call")
+	require.Contains(t, string(body), `data: {"text":"No "}`)
+	require.Regexp(t, `event: usage\ndata: \{"provider":"fake","prompt_tokens":\d+,"completion_tokens":\d+,"total_tokens":\d+,"estimated":true\}`, string(body))
+}
+
+func TestChatStreamToolsBudget(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListActiveCourses(gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().CreateChatToolCall(gomock.Any(), gomock.Any()).Times(1).Return(db.ChatToolCall{}, nil)
+	// The request starts within the budget, the first round uses it up
+	gomock.InOrder(
+		store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(900), nil),
+		store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(1200), nil),
+	)
+
+	server := newFiberTestServer(t, store)
+	server.config.ChatToolsEnabled = true
+	server.config.LLMDailyTokenBudget = 1000
+	fake := newFakeLLMProvider("Never sent.")
+	fake.EnqueueToolCalls(llmToolCall{ID: "call_1", Function: llmFunctionCall{Name: "drop_database", Arguments: `{}`}})
+	server.llm = fake
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", strings.NewReader(`{"messages":[{"role":"user","content":"Drop the database"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "event: tool_result")
+	require.Contains(t, string(body), "event: error\ndata: {\"message\":\"daily AI token budget used up\"}")
+	require.NotContains(t, string(body), "event: done")
+	require.Len(t, fake.Calls(), 1)
+}
+
+func TestChatToolErrors(t *testing.T) {
+	username := util.RandomOwner()
+	otherReco := db.Recommendation{ID: 8, UserUsername: "someone_else", Payload: []byte(`{"courses":[]}`)}
+
+	testCases := []struct {
+		name      string
+		scope     chatToolScope
+		call      llmToolCall
+		buildStub func(store *mockdb.MockStore)
+		wantError string
+	}{
+		{
+			name:      "InvalidJSON",
+			scope:     chatToolScope{Username: username},
+			call:      llmToolCall{ID: "c1", Function: llmFunctionCall{Name: chatToolCheckPrerequisites, Arguments: `{"course_code":`}},
+			buildStub: func(store *mockdb.MockStore) {},
+			wantError: "arguments must be a JSON object",
+		},
+		{
+			name:      "MissingArgument",
+			scope:     chatToolScope{Username: username},
+			call:      llmToolCall{ID: "c2", Function: llmFunctionCall{Name: chatToolSearchCourses, Arguments: `{"limit":3}`}},
+			buildStub: func(store *mockdb.MockStore) {},
+			wantError: "Field validation for 'Query' failed on the 'required' tag",
+		},
+		{
+			name:  "UnknownCourse",
+			scope: chatToolScope{Username: username},
+			call:  llmToolCall{ID: "c3", Function: llmFunctionCall{Name: chatToolCheckPrerequisites, Arguments: `{"course_code":"nope1"}`}},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("NOPE1")).Times(1).Return(db.Course{}, sql.ErrNoRows)
+			},
+			wantError: "course NOPE1 not found",
+		},
+		{
+			name:      "NoPinnedRecommendation",
+			scope:     chatToolScope{Username: username},
+			call:      llmToolCall{ID: "c4", Function: llmFunctionCall{Name: chatToolCreateSummaryPDF, Arguments: `{}`}},
+			buildStub: func(store *mockdb.MockStore) {},
+			wantError: "recommendation_id is required: no recommendation is pinned to this chat",
+		},
+		{
+			name:  "OtherUsersRecommendation",
+			scope: chatToolScope{Username: username, ConversationID: 3, RecommendationID: otherReco.ID},
+			call:  llmToolCall{ID: "c5", Function: llmFunctionCall{Name: chatToolRemoveCourse, Arguments: `{"course_code":"TIES454"}`}},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(otherReco.ID)).Times(1).Return(otherReco, nil)
+				store.EXPECT().UpdateRecommendationPayload(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantError: "recommendation 8 not found",
+		},
+		{
+			name:  "InternalError",
+			scope: chatToolScope{Username: username},
+			call:  llmToolCall{ID: "c6", Function: llmFunctionCall{Name: chatToolSearchCourses, Arguments: `{"query":"ml"}`}},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			wantError: "the tool failed, try again later",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+			store.EXPECT().CreateChatToolCall(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreateChatToolCallParams) (db.ChatToolCall, error) {
+					require.Equal(t, chatToolStatusError, arg.Status)
+					require.True(t, arg.Error.Valid)
+					require.True(t, json.Valid(arg.Arguments))
+					require.Equal(t, tc.scope.ConversationID > 0, arg.ConversationID.Valid)
+					return db.ChatToolCall{}, nil
+				})
+
+			server := newFiberTestServer(t, store)
+			stream := newReplyStream(username)
+
+			content := server.runChatTool(context.Background(), stream, tc.scope, server.chatTools(), tc.call)
+
+			var out struct {
+				Error string `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(content), &out))
+			require.Contains(t, out.Error, tc.wantError)
+			require.Len(t, stream.events, 2)
+			require.Equal(t, replyEventToolCall, stream.events[0].Name)
+			require.Equal(t, replyEventToolResult, stream.events[1].Name)
+			require.Contains(t, string(stream.events[1].Data), `"status":"error"`)
+		})
+	}
+}
+
+func TestToolCheckPrerequisites(t *testing.T) {
+	username := util.RandomOwner()
+	course := db.Course{
+		ID: 9, Code: "TIES455", Name: "Deep Learning",
+		Prerequisites: sql.NullString{String: "TIES454", Valid: true},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TIES455")).Times(1).Return(course, nil)
+	store.EXPECT().ListTranscripts(gomock.Any(), gomock.Eq(username)).Times(1).Return(nil, nil)
+	store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).Return(nil, nil)
+	store.EXPECT().UpsertCoursePrerequisite(gomock.Any(), gomock.Any()).Times(1).Return(db.CoursePrerequisite{}, nil)
+
+	server := newFiberTestServer(t, store)
+	result, err := server.toolCheckPrerequisites(context.Background(), chatToolScope{Username: username}, json.RawMessage(`{"course_code":" ties455 "}`))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"course_code": "TIES455",
+		"name": "Deep Learning",
+		"prerequisites": "TIES454",
+		"status": "blocked",
+		"missing": ["TIES454"],
+		"transcript_found": false
+	}`, string(data))
+}
+
+func TestLLMToolsSorted(t *testing.T) {
+	server := &Server{}
+	tools := llmTools(server.chatTools())
+	require.Len(t, tools, 5)
+	for i, tool := range tools {
+		require.Equal(t, "function", tool.Type)
+		require.True(t, json.Valid(tool.Function.Parameters), tool.Function.Name)
+		if i > 0 {
+			require.Less(t, tools[i-1].Function.Name, tool.Function.Name)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+// server/api/conversations.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+const (
+	defaultChatHistoryTokens = 6000
+	defaultConversationLimit = 20
+	defaultMessageLimit      = 100
+	// Only this many of the newest unsummarized messages are considered for
+	// the prompt.
+	chatHistoryFetchLimit  = 200
+	conversationTitleChars = 60
+	chatSummaryInputChars  = 2000
+	chatRoleUser           = "user"
+	chatRoleAssistant      = "assistant"
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+type createConversationRequest struct {
+	RecommendationID int64  `json:"recommendation_id" validate:"omitempty,min=1"`
+	Title            string `json:"title" validate:"max=200"`
+}
+
+type listConversationsRequest struct {
+	Limit int32 `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type listMessagesRequest struct {
+	After int64 `query:"after" validate:"omitempty,min=0"`
+	Limit int32 `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+type sendMessageRequest struct {
+	Content string `json:"content" validate:"required,max=8000"`
+}
+
+type conversationResponse struct {
+	ID               int64     `json:"id"`
+	Title            string    `json:"title"`
+	RecommendationID *int64    `json:"recommendation_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func newConversationResponse(conv db.Conversation) conversationResponse {
+	resp := conversationResponse{
+		ID:        conv.ID,
+		Title:     conv.Title,
+		CreatedAt: conv.CreatedAt,
+		UpdatedAt: conv.UpdatedAt,
+	}
+	if conv.RecommendationID.Valid {
+		resp.RecommendationID = &conv.RecommendationID.Int64
+	}
+	return resp
+}
+
+type chatMessageResponse struct {
+	ID        int64     `json:"id"`
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newChatMessageResponse(m db.ChatMessage) chatMessageResponse {
+	return chatMessageResponse{ID: m.ID, Role: m.Role, Content: m.Content, CreatedAt: m.CreatedAt}
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// estimateTokens approximates the prompt tokens of a message: about four
+// characters per token plus the per-message overhead.
+func estimateTokens(s string) int32 {
+	return int32(utf8.RuneCountInString(s)/4 + 4)
+}
+
+// conversationTitle is the first line of the first message, shortened.
+func conversationTitle(content string) string {
+	title, _, _ := strings.Cut(strings.TrimSpace(content), "\n")
+	if utf8.RuneCountInString(title) > conversationTitleChars {
+		title = string([]rune(title)[:conversationTitleChars]) + "…"
+	}
+	return title
+}
+
+func (s *Server) fetchOwnedConversation(c *fiber.Ctx, username string) (db.Conversation, error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return db.Conversation{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	conv, err := s.store.GetConversation(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Conversation{}, fiber.NewError(fiber.StatusNotFound, "conversation not found")
+		}
+		return db.Conversation{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if conv.UserUsername != username {
+		return db.Conversation{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
+	}
+	return conv, nil
+}
+
+// splitChatHistory keeps the newest messages (oldest first) that fit in
+// budget tokens, always at least the last one, and returns the older ones
+// separately.
+func splitChatHistory(msgs []db.ChatMessage, budget int) (older, kept []db.ChatMessage) {
+	used, i := 0, len(msgs)
+	for i > 0 {
+		tokens := int(msgs[i-1].Tokens)
+		if used+tokens > budget && i < len(msgs) {
+			break
+		}
+		used += tokens
+		i--
+	}
+	return msgs[:i], msgs[i:]
+}
+
+// summarizeChatHistory folds messages that no longer fit the prompt into the
+// conversation's running summary and stores it, so they are not sent again.
+// Without CHAT_SUMMARIZE_HISTORY, or when the model fails, they are just
+// left out and the previous summary is returned.
+func (s *Server) summarizeChatHistory(ctx context.Context, conv db.Conversation, older []db.ChatMessage) string {
+	summary := conv.Summary.String
+	if len(older) == 0 || !s.config.ChatSummarizeHistory {
+		return summary
+	}
+
+	var sb strings.Builder
+	if summary != "" {
+		sb.WriteString("Summary so far:\n" + summary + "\n\n")
+	}
+	sb.WriteString("Messages to add:\n")
+	for _, m := range older {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", m.Role, truncate(m.Content, chatSummaryInputChars)))
+	}
+
+	updated, err := s.llm.Chat(ctx, []aiMessage{
+		{
+			Role: "system",
+			Content: "Summarize this conversation between a student and their academic advisor in at most 150 words. " +
+				"Keep facts about the student, advice given, decisions and open questions. Reply with the summary only.",
+		},
+		{Role: "user", Content: sb.String()},
+	})
+	updated = strings.TrimSpace(updated)
+	if err != nil || updated == "" {
+		log.Printf("[CHAT] Summarizing conversation %d failed: %v", conv.ID, err)
+		return summary
+	}
+
+	if err := s.store.UpdateConversationSummary(ctx, db.UpdateConversationSummaryParams{
+		ID:              conv.ID,
+		Summary:         sql.NullString{String: updated, Valid: true},
+		SummarizedUntil: older[len(older)-1].ID,
+	}); err != nil {
+		log.Printf("[CHAT] Save summary of conversation %d failed: %v", conv.ID, err)
+	}
+	return updated
+}
+
+// conversationPrompt builds the model input for a conversation: the system
+// context of its pinned recommendation with the sources retrieved for
+// question, the summary of older messages and the newest messages within
+// CHAT_HISTORY_TOKENS. It also returns the sources in citation marker order.
+func (s *Server) conversationPrompt(ctx context.Context, conv db.Conversation, question string) ([]aiMessage, []chatChunk, error) {
+	latest, err := s.store.ListLatestChatMessages(ctx, db.ListLatestChatMessagesParams{
+		ConversationID: conv.ID,
+		AfterID:        conv.SummarizedUntil,
+		MaxMessages:    chatHistoryFetchLimit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	history := make([]db.ChatMessage, len(latest))
+	for i, m := range latest {
+		history[len(latest)-1-i] = m
+	}
+
+	budget := s.config.ChatHistoryTokens
+	if budget <= 0 {
+		budget = defaultChatHistoryTokens
+	}
+	older, kept := splitChatHistory(history, budget)
+	summary := s.summarizeChatHistory(ctx, conv, older)
+
+	systemContext, sources := s.chatSystemContext(ctx, conv.UserUsername, conv.RecommendationID.Int64, question)
+	messages := []aiMessage{{Role: "system", Content: systemContext}}
+	if summary != "" {
+		messages = append(messages, aiMessage{Role: "system", Content: "Summary of the earlier conversation:\n" + summary})
+	}
+	for _, m := range kept {
+		messages = append(messages, aiMessage{Role: m.Role, Content: m.Content})
+	}
+	return messages, sources, nil
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// POST /api/conversations
+// Starts a conversation, optionally pinned to one of the caller's
+// recommendations whose transcript and courses then ground every reply.
+func (s *Server) createConversation(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req createConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	arg := db.CreateConversationParams{
+		UserUsername: payload.Username,
+		Title:        strings.TrimSpace(req.Title),
+	}
+	if req.RecommendationID > 0 {
+		reco, err := s.store.GetRecommendation(c.Context(), req.RecommendationID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && reco.UserUsername != payload.Username) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		arg.RecommendationID = sql.NullInt64{Int64: reco.ID, Valid: true}
+	}
+
+	conv, err := s.store.CreateConversation(c.Context(), arg)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.Status(fiber.StatusCreated).JSON(newConversationResponse(conv))
+}
+
+// GET /api/conversations?limit=20
+// The caller's conversations, most recently active first.
+func (s *Server) listConversations(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req listConversationsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultConversationLimit
+	}
+
+	convs, err := s.store.ListConversations(c.Context(), db.ListConversationsParams{
+		UserUsername: payload.Username,
+		Limit:        int64(req.Limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	resp := make([]conversationResponse, 0, len(convs))
+	for _, conv := range convs {
+		resp = append(resp, newConversationResponse(conv))
+	}
+	return c.JSON(resp)
+}
+
+// GET /api/conversations/:id/messages?after=0&limit=100
+// Messages oldest first; pass the last ID seen as after for the next page.
+func (s *Server) listConversationMessages(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	conv, err := s.fetchOwnedConversation(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	var req listMessagesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultMessageLimit
+	}
+
+	msgs, err := s.store.ListChatMessages(c.Context(), db.ListChatMessagesParams{
+		ConversationID: conv.ID,
+		AfterID:        req.After,
+		MaxMessages:    int64(req.Limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	resp := make([]chatMessageResponse, 0, len(msgs))
+	for _, m := range msgs {
+		resp = append(resp, newChatMessageResponse(m))
+	}
+	return c.JSON(resp)
+}
+
+// POST /api/conversations/:id/messages
+// Saves the user's message and streams the reply as Server-Sent Events
+// (like /api/chat/stream). Once complete a "citation" event follows for each
+// source the reply cites, the reply is saved and a "saved" event carries
+// both message IDs before "done". With a Last-Event-ID header it resumes
+// that reply instead.
+func (s *Server) sendConversationMessage(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+	if lastEventID := c.Get("Last-Event-ID"); lastEventID != "" {
+		return s.resumeReplyStream(c, payload.Username, lastEventID)
+	}
+
+	conv, err := s.fetchOwnedConversation(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	var req sendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	ctx := withLLMCaller(c.Context(), payload.Username, llmFeatureChat)
+	userMsg, err := s.store.CreateChatMessage(ctx, db.CreateChatMessageParams{
+		ConversationID: conv.ID,
+		Role:           chatRoleUser,
+		Content:        req.Content,
+		Tokens:         estimateTokens(req.Content),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.store.TouchConversation(ctx, db.TouchConversationParams{ID: conv.ID, Title: conversationTitle(req.Content)}); err != nil {
+		log.Printf("[CHAT] Touch conversation %d failed: %v", conv.ID, err)
+	}
+
+	messages, sources, err := s.conversationPrompt(ctx, conv, req.Content)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	scope := chatToolScope{
+		Username:         payload.Username,
+		ConversationID:   conv.ID,
+		RecommendationID: conv.RecommendationID.Int64,
+	}
+	stream := s.replies.start(payload.Username, func(ctx context.Context, stream *replyStream) {
+		reply, err := s.streamChatReply(ctx, stream, scope, messages)
+		if err != nil {
+			return
+		}
+		if strings.TrimSpace(reply) == "" {
+			stream.send(replyEventDone, fiber.Map{})
+			return
+		}
+
+		writeCitations(stream, reply, sources)
+
+		saved := fiber.Map{"user_message_id": userMsg.ID}
+		assistantMsg, err := s.store.CreateChatMessage(ctx, db.CreateChatMessageParams{
+			ConversationID: conv.ID,
+			Role:           chatRoleAssistant,
+			Content:        reply,
+			Tokens:         estimateTokens(reply),
+		})
+		if err != nil {
+			log.Printf("[CHAT] Save reply in conversation %d failed: %v", conv.ID, err)
+		} else {
+			saved["message_id"] = assistantMsg.ID
+		}
+		stream.send(replyEventSaved, saved)
+		stream.send(replyEventDone, fiber.Map{})
+	})
+	return serveReplyStream(c, stream, 0)
+}
+
+// DELETE /api/conversations/:id
+func (s *Server) deleteConversation(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	n, err := s.store.DeleteConversation(c.Context(), db.DeleteConversationParams{ID: id, UserUsername: payload.Username})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if n == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("conversation not found")))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,296 @@
+// server/api/conversations_test.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateConversationAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		body       fiber.Map
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name: "Unpinned",
+			body: fiber.Map{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateConversation(gomock.Any(), gomock.Eq(db.CreateConversationParams{UserUsername: username})).
+					Times(1).Return(db.Conversation{ID: 1, UserUsername: username}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "PinnedToRecommendation",
+			body: fiber.Map{"recommendation_id": 7, "title": " Next semester "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(7))).Times(1).
+					Return(db.Recommendation{ID: 7, UserUsername: username}, nil)
+				store.EXPECT().CreateConversation(gomock.Any(), gomock.Eq(db.CreateConversationParams{
+					UserUsername:     username,
+					RecommendationID: sql.NullInt64{Int64: 7, Valid: true},
+					Title:            "Next semester",
+				})).Times(1).Return(db.Conversation{ID: 2, UserUsername: username, RecommendationID: sql.NullInt64{Int64: 7, Valid: true}}, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "OtherUsersRecommendation",
+			body: fiber.Map{"recommendation_id": 7},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(7))).Times(1).
+					Return(db.Recommendation{ID: 7, UserUsername: "someone_else"}, nil)
+				store.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "InvalidRecommendationID",
+			body: fiber.Map{"recommendation_id": -1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateConversation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/conversations", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestListConversationMessagesAPI(t *testing.T) {
+	username := util.RandomOwner()
+	conv := db.Conversation{ID: 3, UserUsername: username}
+
+	testCases := []struct {
+		name          string
+		path          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OK",
+			path: "/api/conversations/3/messages?after=10&limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetConversation(gomock.Any(), gomock.Eq(conv.ID)).Times(1).Return(conv, nil)
+				store.EXPECT().ListChatMessages(gomock.Any(), gomock.Eq(db.ListChatMessagesParams{ConversationID: conv.ID, AfterID: 10, MaxMessages: 2})).
+					Times(1).Return([]db.ChatMessage{
+					{ID: 11, ConversationID: conv.ID, Role: chatRoleUser, Content: "Hi"},
+					{ID: 12, ConversationID: conv.ID, Role: chatRoleAssistant, Content: "Hello"},
+				}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got []chatMessageResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got, 2)
+				require.Equal(t, chatRoleAssistant, got[1].Role)
+			},
+		},
+		{
+			name: "NotOwner",
+			path: "/api/conversations/3/messages",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetConversation(gomock.Any(), gomock.Eq(conv.ID)).Times(1).
+					Return(db.Conversation{ID: 3, UserUsername: "someone_else"}, nil)
+				store.EXPECT().ListChatMessages(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/api/conversations/4/messages",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetConversation(gomock.Any(), gomock.Eq(int64(4))).Times(1).Return(db.Conversation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestSendConversationMessageAPI(t *testing.T) {
+	username := util.RandomOwner()
+	conv := db.Conversation{ID: 5, UserUsername: username, SummarizedUntil: 2}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetConversation(gomock.Any(), gomock.Eq(conv.ID)).Times(1).Return(conv, nil)
+	store.EXPECT().CreateChatMessage(gomock.Any(), gomock.Eq(db.CreateChatMessageParams{
+		ConversationID: conv.ID, Role: chatRoleUser, Content: "Which machine learning course next?", Tokens: estimateTokens("Which machine learning course next?"),
+	})).Times(1).Return(db.ChatMessage{ID: 10, ConversationID: conv.ID, Role: chatRoleUser, Content: "Which machine learning course next?"}, nil)
+	store.EXPECT().TouchConversation(gomock.Any(), gomock.Eq(db.TouchConversationParams{ID: conv.ID, Title: "Which machine learning course next?"})).Times(1).Return(nil)
+	store.EXPECT().ListLatestChatMessages(gomock.Any(), gomock.Eq(db.ListLatestChatMessagesParams{
+		ConversationID: conv.ID, AfterID: 2, MaxMessages: chatHistoryFetchLimit,
+	})).Times(1).Return([]db.ChatMessage{
+		{ID: 10, Role: chatRoleUser, Content: "Which machine learning course next?", Tokens: 8},
+		{ID: 4, Role: chatRoleAssistant, Content: "Hello", Tokens: 5},
+	}, nil)
+	store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).Return([]db.Course{{
+		ID: 9, Code: "TIES454", Name: "Machine Learning",
+		LearningOutcomes: sql.NullString{String: "Students can train and evaluate machine learning models.", Valid: true},
+		CourseLink:       sql.NullString{String: "https://example.edu/ties454", Valid: true},
+	}}, nil)
+	store.EXPECT().ListCourseEmbeddings(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+	store.EXPECT().UpsertCourseEmbedding(gomock.Any(), gomock.Any()).Times(1).Return(db.CourseEmbedding{}, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Any()).Times(1).Return(nil, nil)
+	store.EXPECT().CreateChatMessage(gomock.Any(), gomock.Eq(db.CreateChatMessageParams{
+		ConversationID: conv.ID, Role: chatRoleAssistant, Content: "Take TIES454 next [1].", Tokens: estimateTokens("Take TIES454 next [1]."),
+	})).Times(1).Return(db.ChatMessage{ID: 11}, nil)
+
+	server := newFiberTestServer(t, store)
+	fake := newFakeLLMProvider("Take TIES454 next [1].")
+	server.llm = fake
+
+	req := httptest.NewRequest(http.MethodPost, "/api/conversations/5/messages", strings.NewReader(`{"content": " Which machine learning course next? "}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), `event: delta
+data: {"text":"TIES454 "}`)
+	require.Contains(t, string(body), `event: citation
+data: {"marker":1,"source":"course","ref_id":9,"title":"TIES454 Machine Learning","url":"https://example.edu/ties454"`)
+	require.Contains(t, string(body), "event: saved\ndata: {\"message_id\":11,\"user_message_id\":10}\n\n")
+	require.True(t, strings.HasSuffix(string(body), "event: done\ndata: {}\n\n"))
+
+	// System context with the retrieved course, then the history oldest first
+	calls := fake.Calls()
+	require.Len(t, calls, 1)
+	require.Equal(t, "system", calls[0][0].Role)
+	require.Contains(t, calls[0][0].Content, "[1] (course) TIES454 Machine Learning")
+	require.Equal(t, []aiMessage{{Role: chatRoleAssistant, Content: "Hello"}, {Role: chatRoleUser, Content: "Which machine learning course next?"}}, calls[0][1:])
+}
+
+func TestSplitChatHistory(t *testing.T) {
+	msgs := []db.ChatMessage{{ID: 1, Tokens: 50}, {ID: 2, Tokens: 30}, {ID: 3, Tokens: 20}, {ID: 4, Tokens: 40}}
+
+	older, kept := splitChatHistory(msgs, 100)
+	require.Len(t, older, 1)
+	require.Equal(t, int64(2), kept[0].ID)
+
+	// The newest message is kept even when it alone exceeds the budget
+	older, kept = splitChatHistory(msgs, 10)
+	require.Len(t, older, 3)
+	require.Equal(t, int64(4), kept[0].ID)
+
+	older, kept = splitChatHistory(nil, 10)
+	require.Empty(t, older)
+	require.Empty(t, kept)
+}
+
+func TestSummarizeChatHistory(t *testing.T) {
+	conv := db.Conversation{ID: 5, Summary: sql.NullString{String: "Student likes AI.", Valid: true}}
+	older := []db.ChatMessage{{ID: 3, Role: chatRoleUser, Content: "I finished TIES454."}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	fake := newFakeLLMProvider("Student likes AI and finished TIES454.")
+	server := &Server{store: store, llm: fake, config: util.Config{ChatSummarizeHistory: true}}
+
+	store.EXPECT().UpdateConversationSummary(gomock.Any(), gomock.Eq(db.UpdateConversationSummaryParams{
+		ID: 5, Summary: sql.NullString{String: "Student likes AI and finished TIES454.", Valid: true}, SummarizedUntil: 3,
+	})).Times(1).Return(nil)
+	require.Equal(t, "Student likes AI and finished TIES454.", server.summarizeChatHistory(context.Background(), conv, older))
+	require.Contains(t, fake.Calls()[0][1].Content, "Summary so far:\nStudent likes AI.")
+	require.Contains(t, fake.Calls()[0][1].Content, "user: I finished TIES454.")
+
+	// Summarizing off: the old summary stays and nothing is stored
+	server.config.ChatSummarizeHistory = false
+	require.Equal(t, "Student likes AI.", server.summarizeChatHistory(context.Background(), conv, older))
+
+	// Model failure: the old summary stays
+	server.config.ChatSummarizeHistory = true
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Equal(t, "Student likes AI.", server.summarizeChatHistory(ctx, conv, older))
+}
+
+func TestDeleteConversationAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	server := newFiberTestServer(t, store)
+
+	store.EXPECT().DeleteConversation(gomock.Any(), gomock.Eq(db.DeleteConversationParams{ID: 3, UserUsername: username})).Times(1).Return(int64(1), nil)
+	store.EXPECT().DeleteConversation(gomock.Any(), gomock.Eq(db.DeleteConversationParams{ID: 4, UserUsername: username})).Times(1).Return(int64(0), nil)
+
+	for path, want := range map[string]int{
+		"/api/conversations/3": http.StatusNoContent,
+		"/api/conversations/4": http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+		resp, err := server.app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, want, resp.StatusCode, path)
+	}
+}
@@ -0,0 +1,281 @@
+// server/api/course_browse.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+const (
+	defaultCoursePageSize = 20
+	defaultRelatedLimit   = 5
+
+	// Weights of the related-course score.
+	relatedSharedPrereqWeight = 1.0 // per prerequisite both courses require
+	relatedDirectWeight       = 1.5 // one course is a prerequisite of the other
+	relatedOutcomeWeight      = 3.0 // times the learning-outcome Jaccard overlap
+)
+
+// Relation of a related course to the requested one.
+const (
+	relationPrerequisite = "prerequisite" // the related course is required first
+	relationFollowUp     = "follow_up"    // the related course builds on this one
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+type listCoursesRequest struct {
+	Limit  int64  `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `query:"cursor"`
+}
+
+// coursePageResponse is one page of the active catalog
+// @Description Course page response payload
+type coursePageResponse struct {
+	Courses    []courseResponse `json:"courses"`
+	NextCursor string           `json:"next_cursor,omitempty"` // empty on the last page
+}
+
+type relatedCoursesRequest struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+// relatedCourse is a catalog course scored against the requested one.
+// @Description Related course response payload
+type relatedCourse struct {
+	Course              courseResponse `json:"course"`
+	Score               float64        `json:"score"`
+	SharedPrerequisites []string       `json:"shared_prerequisites"`
+	Relation            string         `json:"relation,omitempty"`
+	OutcomeOverlap      float64        `json:"outcome_overlap"`
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// Cursors are opaque to clients: the base64 of the last course ID on the page.
+func encodeCourseCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCourseCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return id, nil
+}
+
+// getCourseByCodeParam loads the course named by the :code path parameter.
+// Archived courses are returned too, so saved recommendations keep working.
+func (s *Server) getCourseByCodeParam(c *fiber.Ctx) (db.Course, error) {
+	code := strings.ToUpper(strings.TrimSpace(c.Params("code")))
+	course, err := s.store.GetCourseByCode(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return course, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("course %s not found", code))
+		}
+		return course, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return course, nil
+}
+
+// outcomeTokens is the set of learning-outcome terms of a course.
+func outcomeTokens(course db.Course) map[string]bool {
+	set := make(map[string]bool)
+	for _, tok := range embedTokens(course.LearningOutcomes.String) {
+		set[tok] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for tok := range a {
+		if b[tok] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func prerequisiteCodes(p coursePrerequisites) map[string]bool {
+	set := make(map[string]bool)
+	if p.Expr != nil {
+		for _, code := range p.Expr.codes() {
+			set[code] = true
+		}
+	}
+	return set
+}
+
+// relatedCourses scores every other catalog course against target by the
+// prerequisites they share, a direct prerequisite link in either direction
+// and the overlap of their learning outcomes. Courses with no signal are
+// left out; the rest are returned best first.
+func relatedCourses(target db.Course, catalog []db.Course, graph map[int64]coursePrerequisites, limit int) []relatedCourse {
+	targetPrereqs := prerequisiteCodes(graph[target.ID])
+	targetTokens := outcomeTokens(target)
+
+	out := []relatedCourse{}
+	for _, course := range catalog {
+		if course.ID == target.ID {
+			continue
+		}
+		prereqs := prerequisiteCodes(graph[course.ID])
+
+		shared := []string{}
+		for code := range prereqs {
+			if targetPrereqs[code] {
+				shared = append(shared, code)
+			}
+		}
+		sort.Strings(shared)
+
+		relation := ""
+		switch {
+		case targetPrereqs[course.Code]:
+			relation = relationPrerequisite
+		case prereqs[target.Code]:
+			relation = relationFollowUp
+		}
+
+		overlap := jaccard(targetTokens, outcomeTokens(course))
+
+		score := relatedSharedPrereqWeight*float64(len(shared)) + relatedOutcomeWeight*overlap
+		if relation != "" {
+			score += relatedDirectWeight
+		}
+		if score == 0 {
+			continue
+		}
+
+		out = append(out, relatedCourse{
+			Course:              newCourseResponse(course),
+			Score:               math.Round(score*1000) / 1000,
+			SharedPrerequisites: shared,
+			Relation:            relation,
+			OutcomeOverlap:      math.Round(overlap*1000) / 1000,
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Course.Code < out[j].Course.Code
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// GET /api/courses?limit=20&cursor=...
+// Lists active courses by ID. Pass next_cursor back as cursor for the next page.
+func (s *Server) listCatalogCourses(c *fiber.Ctx) error {
+	var req listCoursesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultCoursePageSize
+	}
+	afterID, err := decodeCourseCursor(req.Cursor)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// One extra row tells whether another page follows.
+	courses, err := s.store.ListCoursesPage(c.Context(), db.ListCoursesPageParams{
+		ID:    afterID,
+		Limit: req.Limit + 1,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	resp := coursePageResponse{Courses: make([]courseResponse, 0, len(courses))}
+	if int64(len(courses)) > req.Limit {
+		courses = courses[:req.Limit]
+		resp.NextCursor = encodeCourseCursor(courses[len(courses)-1].ID)
+	}
+	for _, course := range courses {
+		resp.Courses = append(resp.Courses, newCourseResponse(course))
+	}
+	return c.JSON(resp)
+}
+
+// GET /api/courses/:code
+func (s *Server) getCatalogCourse(c *fiber.Ctx) error {
+	course, err := s.getCourseByCodeParam(c)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// GET /api/courses/:code/related?limit=5
+// Active courses that share prerequisites or learning outcomes with the course.
+func (s *Server) listRelatedCourses(c *fiber.Ctx) error {
+	var req relatedCoursesRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultRelatedLimit
+	}
+
+	target, err := s.getCourseByCodeParam(c)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	catalog, err := s.store.ListActiveCourses(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	withTarget := catalog
+	if target.ArchivedAt.Valid {
+		withTarget = append(withTarget[:len(withTarget):len(withTarget)], target)
+	}
+	graph, err := s.loadPrerequisiteGraph(c.Context(), withTarget)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.JSON(relatedCourses(target, catalog, graph, req.Limit))
+}
@@ -0,0 +1,223 @@
+// server/api/course_browse_test.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func testBrowseCourse(id int64, code, outcomes, prerequisites string) db.Course {
+	course := testPlanCourse(id, code, "", prerequisites)
+	course.LearningOutcomes = sql.NullString{String: outcomes, Valid: outcomes != ""}
+	return course
+}
+
+func TestRelatedCourses(t *testing.T) {
+	target := testBrowseCourse(1, "TJTS5012", "research methods for information systems", "TJTS5010 and TJTS5011")
+	catalog := []db.Course{
+		target,
+		testBrowseCourse(2, "TJTS5010", "basics of information systems", ""),
+		testBrowseCourse(3, "TJTS5020", "qualitative research methods", "TJTS5010"),
+		testBrowseCourse(4, "TJTS6000", "thesis seminar", "TJTS5012"),
+		testBrowseCourse(5, "KOGS1000", "music history", ""),
+	}
+	graph := testPrerequisiteGraph(catalog)
+
+	related := relatedCourses(target, catalog, graph, 10)
+	codes := make([]string, 0, len(related))
+	for _, r := range related {
+		codes = append(codes, r.Course.Code)
+	}
+	// KOGS1000 shares nothing with the target and is left out.
+	require.Equal(t, []string{"TJTS5010", "TJTS5020", "TJTS6000"}, codes)
+
+	require.Equal(t, relationPrerequisite, related[0].Relation)
+	require.Greater(t, related[0].OutcomeOverlap, 0.0)
+	require.Equal(t, []string{"TJTS5010"}, related[1].SharedPrerequisites)
+	require.Empty(t, related[1].Relation)
+	require.Greater(t, related[1].OutcomeOverlap, 0.0)
+	require.Equal(t, relationFollowUp, related[2].Relation)
+	require.Zero(t, related[2].OutcomeOverlap)
+
+	require.Len(t, relatedCourses(target, catalog, graph, 1), 1)
+}
+
+func TestCourseCursor(t *testing.T) {
+	id, err := decodeCourseCursor(encodeCourseCursor(42))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+
+	id, err = decodeCourseCursor("")
+	require.NoError(t, err)
+	require.Zero(t, id)
+
+	_, err = decodeCourseCursor("not-a-cursor!")
+	require.Error(t, err)
+}
+
+func TestCourseBrowseAPI(t *testing.T) {
+	page := []db.Course{
+		testBrowseCourse(1, "TJTS5010", "", ""),
+		testBrowseCourse(2, "TJTS5011", "", ""),
+		testBrowseCourse(3, "TJTS5012", "", ""),
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		ifNoneMatch   bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "ListFirstPage",
+			path: "/api/courses?limit=2",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCoursesPage(gomock.Any(), gomock.Eq(db.ListCoursesPageParams{ID: 0, Limit: 3})).
+					Times(1).Return(page, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.NotEmpty(t, resp.Header.Get("ETag"))
+				var got coursePageResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got.Courses, 2)
+				require.Equal(t, encodeCourseCursor(2), got.NextCursor)
+			},
+		},
+		{
+			name: "ListLastPage",
+			path: "/api/courses?limit=2&cursor=" + encodeCourseCursor(2),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCoursesPage(gomock.Any(), gomock.Eq(db.ListCoursesPageParams{ID: 2, Limit: 3})).
+					Times(1).Return(page[2:], nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got coursePageResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got.Courses, 1)
+				require.Empty(t, got.NextCursor)
+			},
+		},
+		{
+			name:        "ListNotModified",
+			path:        "/api/courses",
+			ifNoneMatch: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCoursesPage(gomock.Any(), gomock.Eq(db.ListCoursesPageParams{ID: 0, Limit: 21})).
+					Times(2).Return(page, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotModified, resp.StatusCode)
+			},
+		},
+		{
+			name: "ListInvalidLimit",
+			path: "/api/courses?limit=500",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCoursesPage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "ListInvalidCursor",
+			path: "/api/courses?cursor=%21%21",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCoursesPage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "GetByCode",
+			path: "/api/courses/tjts5012",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TJTS5012")).Times(1).Return(page[2], nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got courseResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, "TJTS5012", got.Code)
+			},
+		},
+		{
+			name: "GetNotFound",
+			path: "/api/courses/TJTS9999",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TJTS9999")).Times(1).Return(db.Course{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name: "Related",
+			path: "/api/courses/TJTS5012/related",
+			buildStubs: func(store *mockdb.MockStore) {
+				target := testBrowseCourse(3, "TJTS5012", "", "TJTS5010")
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TJTS5012")).Times(1).Return(target, nil)
+				store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).
+					Return([]db.Course{page[0], page[1], target}, nil)
+				store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).Return([]db.CoursePrerequisite{}, nil)
+				store.EXPECT().UpsertCoursePrerequisite(gomock.Any(), gomock.Any()).AnyTimes()
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got []relatedCourse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got, 1)
+				require.Equal(t, "TJTS5010", got[0].Course.Code)
+				require.Equal(t, relationPrerequisite, got[0].Relation)
+			},
+		},
+		{
+			name: "RelatedNotFound",
+			path: "/api/courses/TJTS9999/related",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Any()).Times(1).Return(db.Course{}, sql.ErrNoRows)
+				store.EXPECT().ListActiveCourses(gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.ifNoneMatch {
+				first, err := server.app.Test(httptest.NewRequest(http.MethodGet, tc.path, nil), -1)
+				require.NoError(t, err)
+				require.Equal(t, http.StatusOK, first.StatusCode)
+				req.Header.Set("If-None-Match", first.Header.Get("ETag"))
+			}
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// server/api/courses.go
+
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/catalog"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+// courseRequest is the body of the admin course endpoints
+// @Description Course request payload
+type courseRequest struct {
+	Code             string `json:"code" validate:"required,coursecode"`
+	Name             string `json:"name" validate:"required,max=300"`
+	Language         string `json:"language" validate:"max=100"`
+	GradingScale     string `json:"grading_scale" validate:"max=100"`
+	Organiser        string `json:"organiser" validate:"max=300"`
+	LearningOutcomes string `json:"learning_outcomes"`
+	Prerequisites    string `json:"prerequisites"`
+	TeacherName      string `json:"teacher_name" validate:"max=200"`
+	TeacherEmail     string `json:"teacher_email" validate:"omitempty,email"`
+	CourseLink       string `json:"course_link" validate:"omitempty,url"`
+}
+
+// courseResponse is the API view of a catalog course
+// @Description Course response payload
+type courseResponse struct {
+	ID               int64      `json:"id"`
+	Code             string     `json:"code"`
+	Name             string     `json:"name"`
+	Language         string     `json:"language,omitempty"`
+	GradingScale     string     `json:"grading_scale,omitempty"`
+	Organiser        string     `json:"organiser,omitempty"`
+	LearningOutcomes string     `json:"learning_outcomes,omitempty"`
+	Prerequisites    string     `json:"prerequisites,omitempty"`
+	TeacherName      string     `json:"teacher_name,omitempty"`
+	TeacherEmail     string     `json:"teacher_email,omitempty"`
+	CourseLink       string     `json:"course_link,omitempty"`
+	ArchivedAt       *time.Time `json:"archived_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func newCourseResponse(course db.Course) courseResponse {
+	resp := courseResponse{
+		ID:               course.ID,
+		Code:             course.Code,
+		Name:             course.Name,
+		Language:         course.Language.String,
+		GradingScale:     course.GradingScale.String,
+		Organiser:        course.Organiser.String,
+		LearningOutcomes: course.LearningOutcomes.String,
+		Prerequisites:    course.Prerequisites.String,
+		TeacherName:      course.TeacherName.String,
+		TeacherEmail:     course.TeacherEmail.String,
+		CourseLink:       course.CourseLink.String,
+		CreatedAt:        course.CreatedAt,
+		UpdatedAt:        course.UpdatedAt,
+	}
+	if course.ArchivedAt.Valid {
+		archivedAt := course.ArchivedAt.Time
+		resp.ArchivedAt = &archivedAt
+	}
+	return resp
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// parseCourseRequest reads and validates a course body. Codes are
+// upper-cased before validation so "tjts5012" is accepted.
+func (s *Server) parseCourseRequest(c *fiber.Ctx) (courseRequest, error) {
+	var req courseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	req.Name = strings.TrimSpace(req.Name)
+
+	if err := s.validate.Struct(req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return req, nil
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// POST /api/admin/courses
+// Creates the course, or updates (and un-archives) the course with the same code.
+func (s *Server) upsertCourse(c *fiber.Ctx) error {
+	req, err := s.parseCourseRequest(c)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	course, err := s.store.UpsertCourse(c.Context(), db.UpsertCourseParams{
+		Code:             req.Code,
+		Name:             req.Name,
+		Language:         sqlStringOrNull(req.Language),
+		GradingScale:     sqlStringOrNull(req.GradingScale),
+		Organiser:        sqlStringOrNull(req.Organiser),
+		LearningOutcomes: sqlStringOrNull(req.LearningOutcomes),
+		Prerequisites:    sqlStringOrNull(req.Prerequisites),
+		TeacherName:      sqlStringOrNull(req.TeacherName),
+		TeacherEmail:     sqlStringOrNull(req.TeacherEmail),
+		CourseLink:       sqlStringOrNull(req.CourseLink),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// PUT /api/admin/courses/:id
+func (s *Server) updateCourse(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	req, err := s.parseCourseRequest(c)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	course, err := s.store.UpdateCourse(c.Context(), db.UpdateCourseParams{
+		ID:               id,
+		Code:             req.Code,
+		Name:             req.Name,
+		Language:         sqlStringOrNull(req.Language),
+		GradingScale:     sqlStringOrNull(req.GradingScale),
+		Organiser:        sqlStringOrNull(req.Organiser),
+		LearningOutcomes: sqlStringOrNull(req.LearningOutcomes),
+		Prerequisites:    sqlStringOrNull(req.Prerequisites),
+		TeacherName:      sqlStringOrNull(req.TeacherName),
+		TeacherEmail:     sqlStringOrNull(req.TeacherEmail),
+		CourseLink:       sqlStringOrNull(req.CourseLink),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("course code %s is already in use", req.Code)))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// DELETE /api/admin/courses/:id
+// Archives the course: it stays readable for saved recommendations, plans
+// and transcripts but is no longer recommended.
+func (s *Server) archiveCourse(c *fiber.Ctx) error {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	course, err := s.store.ArchiveCourse(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// POST /api/admin/courses/import  (multipart/form-data: file=<csv|json|ndjson>)
+// Query: format (else from the file extension), dry_run=true to only report
+// the diff, archive_missing=true to archive active courses not in the file.
+func (s *Server) importCourses(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing file: %w", err)))
+	}
+
+	format, err := catalog.DetectFormat(c.Query("format"), fileHeader.Filename)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	defer file.Close()
+
+	courses, err := catalog.Parse(file, format)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	result, err := s.store.ImportCoursesTx(c.Context(), db.ImportCoursesTxParams{
+		Courses:        courses,
+		DryRun:         c.QueryBool("dry_run"),
+		ArchiveMissing: c.QueryBool("archive_missing"),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(result)
+}
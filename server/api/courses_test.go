@@ -0,0 +1,332 @@
+// server/api/courses_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminCourseAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	course := db.Course{
+		ID:            11,
+		Code:          "TJTS5012",
+		Name:          "Research Methods",
+		Prerequisites: sql.NullString{String: "TJTS5010", Valid: true},
+	}
+	validBody := courseRequest{
+		Code:          "tjts5012",
+		Name:          "Research Methods",
+		Prerequisites: "TJTS5010",
+		TeacherEmail:  "teacher@example.com",
+	}
+
+	testCases := []struct {
+		name          string
+		role          string
+		method        string
+		path          string
+		body          any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "Upsert",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   "/api/admin/courses",
+			body:   validBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertCourse(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.UpsertCourseParams) (db.Course, error) {
+						require.Equal(t, "TJTS5012", arg.Code)
+						require.Equal(t, "TJTS5010", arg.Prerequisites.String)
+						require.False(t, arg.Language.Valid)
+						return course, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got courseResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, course.Code, got.Code)
+				require.Nil(t, got.ArchivedAt)
+			},
+		},
+		{
+			name:   "UpsertInvalidCode",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   "/api/admin/courses",
+			body:   courseRequest{Code: "not a code", Name: "Broken"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertCourse(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpsertInvalidEmail",
+			role:   util.AdminRole,
+			method: http.MethodPost,
+			path:   "/api/admin/courses",
+			body:   courseRequest{Code: "TJTS5012", Name: "Research Methods", TeacherEmail: "nope"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertCourse(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpsertNotAdmin",
+			role:   util.AdvisorRole,
+			method: http.MethodPost,
+			path:   "/api/admin/courses",
+			body:   validBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertCourse(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "Update",
+			role:   util.AdminRole,
+			method: http.MethodPut,
+			path:   "/api/admin/courses/11",
+			body:   validBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCourse(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.UpdateCourseParams) (db.Course, error) {
+						require.Equal(t, int64(11), arg.ID)
+						require.Equal(t, "TJTS5012", arg.Code)
+						return course, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpdateNotFound",
+			role:   util.AdminRole,
+			method: http.MethodPut,
+			path:   "/api/admin/courses/12",
+			body:   validBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCourse(gomock.Any(), gomock.Any()).Times(1).Return(db.Course{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpdateDuplicateCode",
+			role:   util.AdminRole,
+			method: http.MethodPut,
+			path:   "/api/admin/courses/11",
+			body:   validBody,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateCourse(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.Course{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "Archive",
+			role:   util.AdminRole,
+			method: http.MethodDelete,
+			path:   "/api/admin/courses/11",
+			buildStubs: func(store *mockdb.MockStore) {
+				archived := course
+				archived.ArchivedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().ArchiveCourse(gomock.Any(), gomock.Eq(course.ID)).Times(1).Return(archived, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got courseResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.NotNil(t, got.ArchivedAt)
+			},
+		},
+		{
+			name:   "ArchiveNotFound",
+			role:   util.AdminRole,
+			method: http.MethodDelete,
+			path:   "/api/admin/courses/99",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ArchiveCourse(gomock.Any(), gomock.Eq(int64(99))).Times(1).Return(db.Course{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			req := httptest.NewRequest(tc.method, tc.path, &body)
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, admin, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestImportCoursesAPI(t *testing.T) {
+	admin := util.RandomOwner()
+	csvFile := "code,name,teacher_email\nKOGS1000,Intro,aino@example.com\nKOGS1001,Cognition,\n"
+
+	testCases := []struct {
+		name          string
+		filename      string
+		content       string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:     "DryRun",
+			filename: "courses.csv",
+			content:  csvFile,
+			query:    "?dry_run=true",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportCoursesTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.ImportCoursesTxParams) (db.ImportCoursesTxResult, error) {
+						require.True(t, arg.DryRun)
+						require.False(t, arg.ArchiveMissing)
+						require.Len(t, arg.Courses, 2)
+						require.Equal(t, "aino@example.com", arg.Courses[0].TeacherEmail.String)
+						return db.ImportCoursesTxResult{
+							Added:     []string{"KOGS1001"},
+							Changed:   []db.CourseChange{},
+							Unchanged: []string{"KOGS1000"},
+							Removed:   []string{"TJTS5012"},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got db.ImportCoursesTxResult
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, []string{"KOGS1001"}, got.Added)
+				require.Equal(t, []string{"TJTS5012"}, got.Removed)
+				require.False(t, got.Applied)
+			},
+		},
+		{
+			name:     "ApplyNDJSON",
+			filename: "export.txt",
+			content:  `{"code": "KOGS1000", "name": {"en": "Intro"}}`,
+			query:    "?format=ndjson&archive_missing=true",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportCoursesTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.ImportCoursesTxParams) (db.ImportCoursesTxResult, error) {
+						require.False(t, arg.DryRun)
+						require.True(t, arg.ArchiveMissing)
+						require.Equal(t, "Intro", arg.Courses[0].Name)
+						return db.ImportCoursesTxResult{Applied: true}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:     "InvalidRow",
+			filename: "courses.csv",
+			content:  "code,name\nnot-a-code,Broken\n",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportCoursesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:     "UnknownFormat",
+			filename: "courses.xlsx",
+			content:  "binary",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportCoursesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:     "TxError",
+			filename: "courses.csv",
+			content:  csvFile,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportCoursesTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.ImportCoursesTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, err := writer.CreateFormFile("file", tc.filename)
+			require.NoError(t, err)
+			_, err = part.Write([]byte(tc.content))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/courses/import"+tc.query, &body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, admin, util.AdminRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// server/api/embed_hashing.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+const hashingEmbedderDims = 512
+
+// stopWords are dropped before hashing (English and common Finnish).
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "for": true, "from": true, "has": true, "have": true, "in": true, "is": true, "it": true,
+	"its": true, "of": true, "on": true, "or": true, "that": true, "the": true, "their": true, "this": true,
+	"to": true, "will": true, "with": true, "student": true, "students": true, "course": true, "able": true,
+	"ja": true, "tai": true, "sekä": true, "opiskelija": true, "osaa": true,
+}
+
+// hashingEmbedder is a pure-Go, offline embedder: unigrams and bigrams are
+// hashed into a fixed number of signed buckets with sublinear term
+// frequency, then L2-normalized. It needs no model or network access, so
+// it is the default and the one used in tests.
+type hashingEmbedder struct {
+	dims int
+}
+
+func newHashingEmbedder(dims int) Embedder {
+	return &hashingEmbedder{dims: dims}
+}
+
+func (e *hashingEmbedder) Name() string {
+	return fmt.Sprintf("%s-%d", embedderHashing, e.dims)
+}
+
+func (e *hashingEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = e.embed(text)
+	}
+	return out, nil
+}
+
+func (e *hashingEmbedder) embed(text string) []float32 {
+	tokens := embedTokens(text)
+
+	tf := make(map[string]float64)
+	for i, tok := range tokens {
+		tf[tok]++
+		if i > 0 {
+			tf[tokens[i-1]+" "+tok] += 0.5
+		}
+	}
+
+	v := make([]float64, e.dims)
+	for feature, count := range tf {
+		h := fnv.New32a()
+		h.Write([]byte(feature))
+		sum := h.Sum32()
+
+		weight := 1 + math.Log(count)
+		if count < 1 {
+			weight = count
+		}
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		v[int(sum%uint32(e.dims))] += weight
+	}
+
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	norm = math.Sqrt(norm)
+
+	out := make([]float32, e.dims)
+	if norm == 0 {
+		return out
+	}
+	for i, x := range v {
+		out[i] = float32(x / norm)
+	}
+	return out
+}
+
+// embedTokens lowercases text, splits it on anything that is not a letter
+// or digit and drops stop words and one-letter tokens. A trailing plural
+// "s" is trimmed so "systems" and "system" share a feature.
+func embedTokens(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || stopWords[f] {
+			continue
+		}
+		if len(f) > 3 && strings.HasSuffix(f, "s") && !strings.HasSuffix(f, "ss") {
+			f = strings.TrimSuffix(f, "s")
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
@@ -0,0 +1,117 @@
+// server/api/embed_openai.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultEmbeddingModel = "text-embedding-3-small"
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *llmUsage `json:"usage"`
+}
+
+// openAICompatibleEmbedder calls an OpenAI-style /embeddings endpoint
+// (OpenAI, Ollama, vLLM, ...).
+type openAICompatibleEmbedder struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	model      string
+	requireKey bool
+	httpClient *http.Client
+}
+
+// newOpenAIEmbedder returns an embedder for the hosted OpenAI API.
+func newOpenAIEmbedder(apiKey, model string) Embedder {
+	e := newOpenAICompatibleEmbedder(openAIBaseURL, apiKey, model).(*openAICompatibleEmbedder)
+	e.name = embedderOpenAI
+	e.requireKey = true
+	return e
+}
+
+// newOpenAICompatibleEmbedder returns an embedder for a self-hosted server.
+// The API key is optional.
+func newOpenAICompatibleEmbedder(baseURL, apiKey, model string) Embedder {
+	if model == "" {
+		model = defaultEmbeddingModel
+	}
+	return &openAICompatibleEmbedder{
+		name:    embedderOpenAICompatible,
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:  apiKey,
+		model:   model,
+		httpClient: &http.Client{
+			Timeout: 120 * time.Second,
+		},
+	}
+}
+
+func (e *openAICompatibleEmbedder) Name() string {
+	return e.name + ":" + e.model
+}
+
+func (e *openAICompatibleEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.requireKey && e.apiKey == "" {
+		return nil, fmt.Errorf("missing OpenAI API key")
+	}
+
+	b, _ := json.Marshal(openAIEmbeddingRequest{Model: e.model, Input: texts})
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embeddings", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", e.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", e.name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return nil, fmt.Errorf("%s returned %d: %s", e.name, resp.StatusCode, string(bodyBytes))
+	}
+
+	var out openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid %s embedding response: %w", e.name, err)
+	}
+	reportLLMUsage(ctx, e.model, out.Usage)
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("%s returned embedding index %d for %d inputs", e.name, d.Index, len(texts))
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("%s returned no embedding for input %d", e.name, i)
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,224 @@
+// server/api/embeddings.go
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Embedder names accepted in EMBEDDING_PROVIDER.
+const (
+	embedderHashing          = "hashing"
+	embedderOpenAI           = "openai"
+	embedderOpenAICompatible = "openai_compatible"
+)
+
+// embedBatchSize bounds how many course texts are sent per Embed call.
+const embedBatchSize = 64
+
+// Embedder turns texts into dense vectors for semantic search.
+// Implementations must be safe for concurrent use.
+type Embedder interface {
+	// Name identifies the embedder and its model. It is stored with every
+	// vector, so changing it re-embeds the catalog.
+	Name() string
+
+	// Embed returns one vector per input text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// newEmbedder builds the embedder selected by EMBEDDING_PROVIDER.
+func newEmbedder(config util.Config) (Embedder, error) {
+	switch strings.ToLower(strings.TrimSpace(config.EmbeddingProvider)) {
+	case "", embedderHashing:
+		return newHashingEmbedder(hashingEmbedderDims), nil
+	case embedderOpenAI:
+		return newOpenAIEmbedder(config.OpenAIAPIKey, config.EmbeddingModel), nil
+	case embedderOpenAICompatible:
+		if strings.TrimSpace(config.LLMBaseURL) == "" {
+			return nil, fmt.Errorf("LLM_BASE_URL is required for the %s embedder", embedderOpenAICompatible)
+		}
+		return newOpenAICompatibleEmbedder(config.LLMBaseURL, config.OpenAIAPIKey, config.EmbeddingModel), nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider %q", config.EmbeddingProvider)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// VECTORS
+// -----------------------------------------------------------------------------
+
+// encodeVector packs a vector as little-endian float32 bytes (course_embeddings.vector).
+func encodeVector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return b
+}
+
+func decodeVector(b []byte) ([]float32, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector length %d", len(b))
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v, nil
+}
+
+// cosineSimilarity returns 0 for empty or mismatched vectors.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// courseEmbeddingText is the text a course is embedded from. The name is
+// repeated so that it outweighs long learning outcomes.
+func courseEmbeddingText(c db.Course) string {
+	parts := []string{c.Name, c.Name}
+	if c.LearningOutcomes.Valid {
+		parts = append(parts, c.LearningOutcomes.String)
+	}
+	if c.Prerequisites.Valid {
+		parts = append(parts, c.Prerequisites.String)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// -----------------------------------------------------------------------------
+// RETRIEVAL
+// -----------------------------------------------------------------------------
+
+// courseVectors returns a vector per course, embedding (and storing) the
+// courses whose stored vector is missing, from another embedder, or computed
+// from different text.
+func (s *Server) courseVectors(ctx context.Context, courses []db.Course) (map[int64][]float32, error) {
+	name := s.embedder.Name()
+	stored, err := s.store.ListCourseEmbeddings(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	byCourse := make(map[int64]db.CourseEmbedding, len(stored))
+	for _, row := range stored {
+		byCourse[row.CourseID] = row
+	}
+
+	vectors := make(map[int64][]float32, len(courses))
+	var stale []db.Course
+	for _, c := range courses {
+		row, ok := byCourse[c.ID]
+		if ok && row.ContentHash == contentHash(courseEmbeddingText(c)) {
+			if v, err := decodeVector(row.Vector); err == nil && len(v) > 0 {
+				vectors[c.ID] = v
+				continue
+			}
+		}
+		stale = append(stale, c)
+	}
+
+	for start := 0; start < len(stale); start += embedBatchSize {
+		batch := stale[start:min(start+embedBatchSize, len(stale))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = courseEmbeddingText(c)
+		}
+
+		embedded, err := s.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("%s embedding failed: %w", name, err)
+		}
+		if len(embedded) != len(batch) {
+			return nil, fmt.Errorf("%s returned %d vectors for %d texts", name, len(embedded), len(batch))
+		}
+
+		for i, c := range batch {
+			vectors[c.ID] = embedded[i]
+			if _, err := s.store.UpsertCourseEmbedding(ctx, db.UpsertCourseEmbeddingParams{
+				CourseID:    c.ID,
+				Embedder:    name,
+				ContentHash: contentHash(texts[i]),
+				Vector:      encodeVector(embedded[i]),
+			}); err != nil {
+				log.Printf("[DB] Save embedding for %s failed: %v", c.Code, err)
+			}
+		}
+	}
+	if len(stale) > 0 {
+		log.Printf("[EMBED] %s embedded %d courses", name, len(stale))
+	}
+	return vectors, nil
+}
+
+// retrieveCourses keeps the topK candidates closest to query, in order of
+// similarity. Candidates are returned unchanged when they already fit, or
+// when embedding fails (the LLM then sees the full list, as before).
+func (s *Server) retrieveCourses(ctx context.Context, candidates []db.Course, query string, topK int) []db.Course {
+	if topK <= 0 || len(candidates) <= topK || strings.TrimSpace(query) == "" {
+		return candidates
+	}
+
+	vectors, err := s.courseVectors(ctx, candidates)
+	if err != nil {
+		log.Printf("[WARN] Semantic retrieval skipped: %v", err)
+		return candidates
+	}
+	qv, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil || len(qv) != 1 {
+		log.Printf("[WARN] Semantic retrieval skipped: query embedding failed: %v", err)
+		return candidates
+	}
+
+	scores := make(map[int64]float64, len(candidates))
+	for _, c := range candidates {
+		scores[c.ID] = cosineSimilarity(qv[0], vectors[c.ID])
+	}
+
+	ranked := append([]db.Course(nil), candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i].ID] > scores[ranked[j].ID] })
+	return ranked[:topK]
+}
+
+// retrievalQuery combines the user's preference with the names of the
+// courses they have completed.
+func retrievalQuery(preference string, allCourses []db.Course, completedCodes []string) string {
+	done := make(map[string]bool, len(completedCodes))
+	for _, code := range completedCodes {
+		done[code] = true
+	}
+	parts := []string{preference}
+	for _, c := range allCourses {
+		if done[strings.ToUpper(strings.TrimSpace(c.Code))] {
+			parts = append(parts, c.Name)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
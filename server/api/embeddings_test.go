@@ -0,0 +1,140 @@
+// server/api/embeddings_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testEmbedCourse(id int64, name, outcomes string) db.Course {
+	return db.Course{
+		ID:               id,
+		Code:             fmt.Sprintf("TEST%d", id),
+		Name:             name,
+		LearningOutcomes: sql.NullString{String: outcomes, Valid: outcomes != ""},
+	}
+}
+
+func TestHashingEmbedder(t *testing.T) {
+	e := newHashingEmbedder(hashingEmbedderDims)
+	require.Equal(t, "hashing-512", e.Name())
+
+	vectors, err := e.Embed(context.Background(), []string{
+		"Machine learning and neural networks",
+		"Deep neural networks for machine learning systems",
+		"History of Finnish literature",
+		"",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 4)
+	require.Len(t, vectors[0], hashingEmbedderDims)
+
+	require.InDelta(t, 1.0, cosineSimilarity(vectors[0], vectors[0]), 1e-6)
+	require.Greater(t, cosineSimilarity(vectors[0], vectors[1]), cosineSimilarity(vectors[0], vectors[2]))
+	require.Zero(t, cosineSimilarity(vectors[0], vectors[3]))
+
+	again, _ := e.Embed(context.Background(), []string{"Machine learning and neural networks"})
+	require.Equal(t, vectors[0], again[0])
+}
+
+func TestEmbedTokens(t *testing.T) {
+	require.Equal(t, []string{"information", "system", "security"}, embedTokens("The Information-Systems security, a course."))
+}
+
+func TestVectorEncoding(t *testing.T) {
+	v := []float32{0, 1.5, -2.25, 3e-7}
+	decoded, err := decodeVector(encodeVector(v))
+	require.NoError(t, err)
+	require.Equal(t, v, decoded)
+
+	_, err = decodeVector([]byte{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestRetrieveCourses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	candidates := []db.Course{
+		testEmbedCourse(1, "Finnish Literature", "Poetry and novels of the 1800s"),
+		testEmbedCourse(2, "Machine Learning", "Neural networks, supervised learning and model evaluation"),
+		testEmbedCourse(3, "Accounting Basics", "Bookkeeping and financial statements"),
+		testEmbedCourse(4, "Deep Learning", "Convolutional neural networks and training deep models"),
+	}
+	embedder := newHashingEmbedder(hashingEmbedderDims)
+
+	// Course 1 has a current vector; course 3 was embedded from old text.
+	current, _ := embedder.Embed(context.Background(), []string{courseEmbeddingText(candidates[0])})
+	store.EXPECT().ListCourseEmbeddings(gomock.Any(), gomock.Eq(embedder.Name())).Times(1).Return([]db.CourseEmbedding{
+		{CourseID: 1, Embedder: embedder.Name(), ContentHash: contentHash(courseEmbeddingText(candidates[0])), Vector: encodeVector(current[0])},
+		{CourseID: 3, Embedder: embedder.Name(), ContentHash: "old", Vector: encodeVector(current[0])},
+	}, nil)
+	store.EXPECT().UpsertCourseEmbedding(gomock.Any(), gomock.Any()).Times(3).Return(db.CourseEmbedding{}, nil)
+
+	server := &Server{store: store, embedder: embedder}
+	got := server.retrieveCourses(context.Background(), candidates, "I want to learn neural networks and machine learning", 2)
+
+	require.Len(t, got, 2)
+	require.ElementsMatch(t, []int64{2, 4}, []int64{got[0].ID, got[1].ID})
+
+	// Small candidate lists skip retrieval entirely
+	require.Equal(t, candidates, server.retrieveCourses(context.Background(), candidates, "anything", 10))
+}
+
+func TestOpenAICompatibleEmbedder(t *testing.T) {
+	var got openAIEmbeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/embeddings", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got.Model == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// Out of order on purpose: index decides the position
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`)
+	}))
+	defer srv.Close()
+
+	e := newOpenAICompatibleEmbedder(srv.URL+"/v1", "", "nomic-embed-text")
+	require.Equal(t, "openai_compatible:nomic-embed-text", e.Name())
+
+	vectors, err := e.Embed(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	require.Equal(t, []string{"a", "b"}, got.Input)
+
+	_, err = newOpenAICompatibleEmbedder(srv.URL+"/v1", "", "broken").Embed(context.Background(), []string{"a"})
+	require.Error(t, err)
+
+	_, err = newOpenAIEmbedder("", "").Embed(context.Background(), []string{"a"})
+	require.EqualError(t, err, "missing OpenAI API key")
+}
+
+func TestNewEmbedder(t *testing.T) {
+	e, err := newEmbedder(util.Config{})
+	require.NoError(t, err)
+	require.Equal(t, "hashing-512", e.Name())
+
+	e, err = newEmbedder(util.Config{EmbeddingProvider: "openai"})
+	require.NoError(t, err)
+	require.Equal(t, "openai:text-embedding-3-small", e.Name())
+
+	_, err = newEmbedder(util.Config{EmbeddingProvider: "openai_compatible"})
+	require.Error(t, err)
+
+	_, err = newEmbedder(util.Config{EmbeddingProvider: "word2vec"})
+	require.Error(t, err)
+}
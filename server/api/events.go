@@ -0,0 +1,173 @@
+// server/api/events.go
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Progress event types published on GET /api/events.
+const (
+	eventTranscriptTextExtracted = "transcript.text_extracted"
+	eventTranscriptOCRStarted    = "transcript.ocr.started"
+	eventTranscriptOCRPageDone   = "transcript.ocr.page_done"
+	eventTranscriptParsed        = "transcript.parsed"
+	eventTranscriptSaved         = "transcript.saved"
+
+	eventRecoHistoryExtracted = "recommendation.history_extracted"
+	eventRecoCandidates       = "recommendation.candidates_filtered"
+	eventRecoRetrieved        = "recommendation.retrieved"
+	eventRecoRanked           = "recommendation.ranked"
+	eventRecoSaved            = "recommendation.saved"
+
+	eventScholarshipsSearched = "scholarships.searched"
+	eventScholarshipsFetched  = "scholarships.pages_fetched"
+	eventScholarshipsSaved    = "scholarships.saved"
+
+	eventSummaryGenerated  = "summary.generated"
+	eventSummaryPDFWritten = "summary.pdf_written"
+
+	eventJobStarted   = "job.started"
+	eventJobSucceeded = "job.succeeded"
+	eventJobRetrying  = "job.retrying"
+	eventJobFailed    = "job.failed"
+
+	eventNotificationCreated = "notification.created"
+)
+
+const (
+	// Events for a subscriber that does not keep up are dropped.
+	eventBufferSize     = 64
+	eventsKeepAliveTime = 15 * time.Second
+)
+
+// progressEvent is one message on the events stream.
+type progressEvent struct {
+	Type  string    `json:"type"`
+	JobID int64     `json:"job_id,omitempty"`
+	Data  any       `json:"data,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+// eventHub is an in-process pub/sub of progress events keyed by username.
+// Every open GET /api/events stream of a user receives that user's events.
+type eventHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan progressEvent]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[string]map[chan progressEvent]struct{})}
+}
+
+// subscribe registers a new stream for the user. Call the returned function
+// to unsubscribe; it closes the channel.
+func (h *eventHub) subscribe(username string) (<-chan progressEvent, func()) {
+	ch := make(chan progressEvent, eventBufferSize)
+
+	h.mu.Lock()
+	if h.subs[username] == nil {
+		h.subs[username] = make(map[chan progressEvent]struct{})
+	}
+	h.subs[username][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[username], ch)
+			if len(h.subs[username]) == 0 {
+				delete(h.subs, username)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish sends ev to every stream of the user without blocking.
+func (h *eventHub) publish(username string, ev progressEvent) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[username] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+type jobIDContextKey struct{}
+
+// withJobID tags ctx so events published while running the job carry its ID.
+func withJobID(ctx context.Context, jobID int64) context.Context {
+	return context.WithValue(ctx, jobIDContextKey{}, jobID)
+}
+
+// publishEvent sends a progress event to the user's open event streams.
+func (s *Server) publishEvent(ctx context.Context, username, eventType string, data any) {
+	jobID, _ := ctx.Value(jobIDContextKey{}).(int64)
+	s.events.publish(username, progressEvent{
+		Type:  eventType,
+		JobID: jobID,
+		Data:  data,
+		Time:  time.Now(),
+	})
+}
+
+// GET /api/events
+// Server-sent events: one event per progress step of the user's uploads,
+// recommendations, scholarship searches, summaries and background jobs.
+func (s *Server) streamEvents(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	events, unsubscribe := s.events.subscribe(payload.Username)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(eventsKeepAliveTime)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				data, _ := json.Marshal(ev)
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return // client went away
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,59 @@
+// server/api/events_test.go
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub(t *testing.T) {
+	hub := newEventHub()
+
+	alice1, unsubAlice1 := hub.subscribe("alice")
+	alice2, unsubAlice2 := hub.subscribe("alice")
+	bob, unsubBob := hub.subscribe("bob")
+	defer unsubAlice2()
+	defer unsubBob()
+
+	hub.publish("alice", progressEvent{Type: eventRecoRanked})
+	require.Equal(t, eventRecoRanked, (<-alice1).Type)
+	require.Equal(t, eventRecoRanked, (<-alice2).Type)
+	require.Empty(t, bob)
+
+	// Unsubscribing closes the stream and is safe to repeat
+	unsubAlice1()
+	unsubAlice1()
+	_, open := <-alice1
+	require.False(t, open)
+
+	hub.publish("alice", progressEvent{Type: eventRecoSaved})
+	require.Equal(t, eventRecoSaved, (<-alice2).Type)
+
+	// A slow subscriber drops events instead of blocking the publisher
+	for i := 0; i < eventBufferSize+10; i++ {
+		hub.publish("bob", progressEvent{Type: eventTranscriptOCRPageDone})
+	}
+	require.Len(t, bob, eventBufferSize)
+
+	// No subscribers, nil hub: no-op
+	hub.publish("carol", progressEvent{Type: eventJobStarted})
+	var nilHub *eventHub
+	nilHub.publish("alice", progressEvent{Type: eventJobStarted})
+}
+
+func TestPublishEventJobID(t *testing.T) {
+	server := &Server{events: newEventHub()}
+	events, unsubscribe := server.events.subscribe("alice")
+	defer unsubscribe()
+
+	server.publishEvent(context.Background(), "alice", eventSummaryGenerated, nil)
+	server.publishEvent(withJobID(context.Background(), 9), "alice", eventSummaryGenerated, nil)
+
+	require.Zero(t, (<-events).JobID)
+	ev := <-events
+	require.Equal(t, int64(9), ev.JobID)
+	require.False(t, ev.Time.IsZero())
+}
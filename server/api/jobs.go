@@ -0,0 +1,397 @@
+// server/api/jobs.go
+
+package api
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Job kinds handled by the background workers.
+const (
+	jobKindTranscript     = "transcript_upload"
+	jobKindRecommendation = "recommendation"
+	jobKindScholarships   = "scholarships"
+	jobKindSummary        = "summary"
+)
+
+// Job statuses (jobs.status)
+const (
+	jobStatusQueued    = "queued"
+	jobStatusRunning   = "running"
+	jobStatusSucceeded = "succeeded"
+	jobStatusFailed    = "failed"
+)
+
+const (
+	defaultJobMaxAttempts = 3
+	jobPollInterval       = time.Second
+	jobTimeout            = 10 * time.Minute
+	// Jobs still "running" this long after they started belong to a worker
+	// that died; they are re-queued when the pool starts.
+	jobStaleAfter = 2 * jobTimeout
+
+	jobBackoffBase = 5 * time.Second
+	jobBackoffMax  = 5 * time.Minute
+
+	jobEventsInterval = time.Second
+)
+
+// jobFunc runs one job and returns its JSON-serializable result. Returning a
+// *fiber.Error with a 4xx code marks the failure as permanent (no retry).
+type jobFunc func(ctx context.Context, job db.Job) (any, error)
+
+// jobQueue is a pool of workers that claim queued jobs from Postgres with
+// FOR UPDATE SKIP LOCKED, so several server instances can share one table.
+type jobQueue struct {
+	store    db.Store
+	handlers map[string]jobFunc
+	workers  int
+	events   *eventHub
+}
+
+func newJobQueue(store db.Store, workers int, handlers map[string]jobFunc, events *eventHub) *jobQueue {
+	return &jobQueue{store: store, handlers: handlers, workers: workers, events: events}
+}
+
+// publish sends a job lifecycle event to the job owner's event streams.
+func (q *jobQueue) publish(job db.Job, eventType string, data fiber.Map) {
+	data["kind"] = job.Kind
+	data["attempt"] = job.Attempts
+	q.events.publish(job.UserUsername, progressEvent{Type: eventType, JobID: job.ID, Data: data, Time: time.Now()})
+}
+
+// start re-queues stale jobs, or fails them when their attempts are used
+// up, and launches the workers. It returns at once;
+// workers stop when ctx is cancelled.
+func (q *jobQueue) start(ctx context.Context) {
+	stale := sql.NullTime{Time: time.Now().Add(-jobStaleAfter), Valid: true}
+	if n, err := q.store.FailStaleJobs(ctx, stale); err != nil {
+		log.Printf("[JOBS] Failed to fail stale jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("[JOBS] Failed %d stale jobs that used all their attempts", n)
+	}
+	if n, err := q.store.RequeueStaleJobs(ctx, stale); err != nil {
+		log.Printf("[JOBS] Failed to re-queue stale jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("[JOBS] Re-queued %d stale jobs", n)
+	}
+
+	log.Printf("[JOBS] Starting %d workers", q.workers)
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx)
+	}
+}
+
+func (q *jobQueue) work(ctx context.Context) {
+	for {
+		ran, err := q.runNext(ctx)
+		if err != nil {
+			log.Printf("[JOBS] %v", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(jobPollInterval):
+		}
+	}
+}
+
+// runNext claims and runs one due job. It reports false when none is queued.
+func (q *jobQueue) runNext(ctx context.Context) (bool, error) {
+	job, err := q.store.ClaimJob(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim job: %w", err)
+	}
+
+	fn, ok := q.handlers[job.Kind]
+	var result any
+	if !ok {
+		err = fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("unknown job kind %q", job.Kind))
+	} else {
+		q.publish(job, eventJobStarted, fiber.Map{})
+		jobCtx, cancel := context.WithTimeout(withJobID(ctx, job.ID), jobTimeout)
+		result, err = runJobFunc(jobCtx, fn, job)
+		cancel()
+	}
+	return true, q.finish(ctx, job, result, err)
+}
+
+// finish records the outcome of an attempt: success, a delayed retry, or
+// a final failure once the error is permanent or attempts are used up.
+func (q *jobQueue) finish(ctx context.Context, job db.Job, result any, runErr error) error {
+	if runErr == nil {
+		resultJSON, err := json.Marshal(result)
+		if err != nil {
+			runErr = fmt.Errorf("encode result: %w", err)
+		} else {
+			_, err = q.store.CompleteJob(ctx, db.CompleteJobParams{ID: job.ID, Result: resultJSON})
+			if err != nil {
+				return fmt.Errorf("complete job %d: %w", job.ID, err)
+			}
+			q.publish(job, eventJobSucceeded, fiber.Map{})
+			return nil
+		}
+	}
+
+	msg := sql.NullString{String: runErr.Error(), Valid: true}
+	if isPermanentJobError(runErr) || job.Attempts >= job.MaxAttempts {
+		log.Printf("[JOBS] %s job %d failed after %d attempts: %v", job.Kind, job.ID, job.Attempts, runErr)
+		if _, err := q.store.FailJob(ctx, db.FailJobParams{ID: job.ID, Error: msg}); err != nil {
+			return fmt.Errorf("fail job %d: %w", job.ID, err)
+		}
+		q.publish(job, eventJobFailed, fiber.Map{"error": runErr.Error()})
+		return nil
+	}
+
+	delay := jobBackoff(job.Attempts)
+	log.Printf("[JOBS] %s job %d attempt %d failed, retrying in %s: %v", job.Kind, job.ID, job.Attempts, delay, runErr)
+	_, err := q.store.RetryJob(ctx, db.RetryJobParams{ID: job.ID, Error: msg, RunAt: time.Now().Add(delay)})
+	if err != nil {
+		return fmt.Errorf("retry job %d: %w", job.ID, err)
+	}
+	q.publish(job, eventJobRetrying, fiber.Map{"error": runErr.Error(), "retry_in_seconds": delay.Seconds()})
+	return nil
+}
+
+// runJobFunc calls fn, turning a panic into an error so one bad job cannot
+// take a worker down. The job's LLM calls are metered for its user.
+func runJobFunc(ctx context.Context, fn jobFunc, job db.Job) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(withLLMCaller(ctx, job.UserUsername, jobLLMFeatures[job.Kind]), job)
+}
+
+// jobBackoff is the delay before the next attempt: 5s, 10s, 20s, ... capped at 5m.
+func jobBackoff(attempt int32) time.Duration {
+	delay := jobBackoffBase
+	for i := int32(1); i < attempt && delay < jobBackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, jobBackoffMax)
+}
+
+// isPermanentJobError reports whether retrying cannot help (bad input,
+// missing transcript, ...).
+func isPermanentJobError(err error) bool {
+	var fe *fiber.Error
+	return errors.As(err, &fe) && fe.Code < fiber.StatusInternalServerError
+}
+
+// jobHandlers maps each job kind to the code that runs it.
+func (s *Server) jobHandlers() map[string]jobFunc {
+	return map[string]jobFunc{
+		jobKindTranscript: func(ctx context.Context, job db.Job) (any, error) {
+			var p transcriptJobPayload
+			if err := json.Unmarshal(job.Payload, &p); err != nil {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid job payload: %v", err))
+			}
+			return s.processTranscript(ctx, job.UserUsername, p.FilePath)
+		},
+		jobKindRecommendation: func(ctx context.Context, job db.Job) (any, error) {
+			var req createRecommendationRequest
+			if err := json.Unmarshal(job.Payload, &req); err != nil {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid job payload: %v", err))
+			}
+			return s.recommendCourses(ctx, job.UserUsername, req)
+		},
+		jobKindScholarships: func(ctx context.Context, job db.Job) (any, error) {
+			return s.findScholarships(ctx, job.UserUsername)
+		},
+		jobKindSummary: func(ctx context.Context, job db.Job) (any, error) {
+			return s.summarizeTranscript(ctx, job.UserUsername)
+		},
+	}
+}
+
+// dispatchJob queues a job for the user and answers 202 with its ID. When
+// no workers are configured (JOB_WORKERS=0) or the client asks for
+// ?sync=true, the job runs inside the request and its result is returned
+// directly, as before the queue existed.
+func (s *Server) dispatchJob(c *fiber.Ctx, username, kind string, payload any) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	if s.config.JobWorkers <= 0 || c.QueryBool("sync") {
+		fn := s.jobs.handlers[kind]
+		result, err := runJobFunc(c.Context(), fn, db.Job{UserUsername: username, Kind: kind, Payload: payloadJSON})
+		if err != nil {
+			return statusErrorResponse(c, err)
+		}
+		return c.JSON(result)
+	}
+
+	job, err := s.enqueueJob(c.Context(), username, kind, payloadJSON)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"job_id": job.ID,
+		"kind":   job.Kind,
+		"status": job.Status,
+	})
+}
+
+// enqueueJob stores a pending job for the workers.
+func (s *Server) enqueueJob(ctx context.Context, username, kind string, payload []byte) (db.Job, error) {
+	maxAttempts := s.config.JobMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultJobMaxAttempts
+	}
+	return s.store.CreateJob(ctx, db.CreateJobParams{
+		UserUsername: username,
+		Kind:         kind,
+		Payload:      payload,
+		MaxAttempts:  int32(maxAttempts),
+	})
+}
+
+// jobResponse is the API view of a job. Result is only set once it succeeded.
+type jobResponse struct {
+	ID          int64           `json:"id"`
+	Kind        string          `json:"kind"`
+	Status      string          `json:"status"`
+	Attempts    int32           `json:"attempts"`
+	MaxAttempts int32           `json:"max_attempts"`
+	Error       string          `json:"error,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func newJobResponse(job db.Job) jobResponse {
+	resp := jobResponse{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		Error:       job.Error.String,
+		RunAt:       job.RunAt,
+		CreatedAt:   job.CreatedAt,
+	}
+	if job.Status == jobStatusSucceeded {
+		resp.Result = job.Result
+	}
+	if job.StartedAt.Valid {
+		resp.StartedAt = &job.StartedAt.Time
+	}
+	if job.FinishedAt.Valid {
+		resp.FinishedAt = &job.FinishedAt.Time
+	}
+	return resp
+}
+
+func jobDone(status string) bool {
+	return status == jobStatusSucceeded || status == jobStatusFailed
+}
+
+// fetchOwnedJob loads the :id job and checks that it belongs to the caller.
+func (s *Server) fetchOwnedJob(c *fiber.Ctx, username string) (db.Job, error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return db.Job{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	job, err := s.store.GetJob(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Job{}, fiber.NewError(fiber.StatusNotFound, "job not found")
+		}
+		return db.Job{}, err
+	}
+	if job.UserUsername != username {
+		return db.Job{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
+	}
+	return job, nil
+}
+
+// GET /api/jobs/:id
+func (s *Server) getJob(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	job, err := s.fetchOwnedJob(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+	return c.JSON(newJobResponse(job))
+}
+
+// GET /api/jobs/:id/events
+// Server-sent events: one "status" event whenever the job changes, ending
+// after it succeeds or fails.
+func (s *Server) streamJobEvents(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	job, err := s.fetchOwnedJob(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The request context is gone once the handler returns.
+		ctx := context.Background()
+		last := ""
+		for {
+			state := fmt.Sprintf("%s/%d", job.Status, job.Attempts)
+			if state != last {
+				last = state
+				data, _ := json.Marshal(newJobResponse(job))
+				fmt.Fprintf(w, "event: status\ndata: %s\n\n", data)
+			} else {
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return // client went away
+			}
+			if jobDone(job.Status) {
+				return
+			}
+
+			time.Sleep(jobEventsInterval)
+			next, err := s.store.GetJob(ctx, job.ID)
+			if err != nil {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+				w.Flush()
+				return
+			}
+			job = next
+		}
+	})
+	return nil
+}
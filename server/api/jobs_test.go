@@ -0,0 +1,265 @@
+// server/api/jobs_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobBackoff(t *testing.T) {
+	require.Equal(t, 5*time.Second, jobBackoff(1))
+	require.Equal(t, 10*time.Second, jobBackoff(2))
+	require.Equal(t, 20*time.Second, jobBackoff(3))
+	require.Equal(t, 5*time.Minute, jobBackoff(10))
+	require.Equal(t, 5*time.Minute, jobBackoff(100))
+}
+
+func TestJobQueueRunNext(t *testing.T) {
+	claimed := func(kind string, attempts int32) db.Job {
+		return db.Job{ID: 7, UserUsername: "alice", Kind: kind, Status: jobStatusRunning, Payload: json.RawMessage(`{}`), Attempts: attempts, MaxAttempts: 3}
+	}
+	handlers := map[string]jobFunc{
+		"ok": func(_ context.Context, job db.Job) (any, error) {
+			return fiber.Map{"user": job.UserUsername}, nil
+		},
+		"flaky": func(context.Context, db.Job) (any, error) {
+			return nil, errors.New("upstream timeout")
+		},
+		"bad_input": func(context.Context, db.Job) (any, error) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "no transcripts found")
+		},
+		"panics": func(context.Context, db.Job) (any, error) {
+			panic("boom")
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		ran        bool
+	}{
+		{
+			name: "Empty",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(db.Job{}, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "Succeeded",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("ok", 1), nil)
+				store.EXPECT().CompleteJob(gomock.Any(), gomock.Eq(db.CompleteJobParams{ID: 7, Result: json.RawMessage(`{"user":"alice"}`)})).
+					Times(1).Return(db.Job{}, nil)
+			},
+			ran: true,
+		},
+		{
+			name: "RetriedWithBackoff",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("flaky", 2), nil)
+				store.EXPECT().RetryJob(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.RetryJobParams) (db.Job, error) {
+						require.Equal(t, "upstream timeout", arg.Error.String)
+						require.WithinDuration(t, time.Now().Add(10*time.Second), arg.RunAt, time.Second)
+						return db.Job{}, nil
+					})
+				store.EXPECT().FailJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			ran: true,
+		},
+		{
+			name: "AttemptsExhausted",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("flaky", 3), nil)
+				store.EXPECT().FailJob(gomock.Any(), gomock.Eq(db.FailJobParams{ID: 7, Error: sql.NullString{String: "upstream timeout", Valid: true}})).
+					Times(1).Return(db.Job{}, nil)
+				store.EXPECT().RetryJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			ran: true,
+		},
+		{
+			name: "PermanentError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("bad_input", 1), nil)
+				store.EXPECT().FailJob(gomock.Any(), gomock.Eq(db.FailJobParams{ID: 7, Error: sql.NullString{String: "no transcripts found", Valid: true}})).
+					Times(1).Return(db.Job{}, nil)
+			},
+			ran: true,
+		},
+		{
+			name: "UnknownKind",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("mystery", 1), nil)
+				store.EXPECT().FailJob(gomock.Any(), gomock.Any()).Times(1).Return(db.Job{}, nil)
+			},
+			ran: true,
+		},
+		{
+			name: "Panic",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ClaimJob(gomock.Any()).Times(1).Return(claimed("panics", 1), nil)
+				store.EXPECT().RetryJob(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.RetryJobParams) (db.Job, error) {
+						require.Equal(t, "job panicked: boom", arg.Error.String)
+						return db.Job{}, nil
+					})
+			},
+			ran: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			ran, err := newJobQueue(store, 1, handlers, nil).runNext(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tc.ran, ran)
+		})
+	}
+}
+
+func TestJobQueueStartStaleJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().FailStaleJobs(gomock.Any(), gomock.Any()).Times(1).Return(int64(1), nil),
+		store.EXPECT().RequeueStaleJobs(gomock.Any(), gomock.Any()).Times(1).Return(int64(2), nil),
+	)
+
+	// No workers, so only the stale jobs are handled
+	newJobQueue(store, 0, nil, nil).start(context.Background())
+}
+
+func TestDispatchJobQueued(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ any, arg db.CreateJobParams) (db.Job, error) {
+			require.Equal(t, username, arg.UserUsername)
+			require.Equal(t, jobKindSummary, arg.Kind)
+			require.Equal(t, int32(defaultJobMaxAttempts), arg.MaxAttempts)
+			return db.Job{ID: 42, UserUsername: username, Kind: arg.Kind, Status: jobStatusQueued}, nil
+		})
+	// Queued, not run inline
+	store.EXPECT().ListTranscripts(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	server.config.JobWorkers = 2
+
+	req := httptest.NewRequest(http.MethodPost, "/api/summaries/generate", nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var got struct {
+		JobID  int64  `json:"job_id"`
+		Status string `json:"status"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, int64(42), got.JobID)
+	require.Equal(t, jobStatusQueued, got.Status)
+}
+
+func TestGetJobAPI(t *testing.T) {
+	username := util.RandomOwner()
+	finished := db.Job{
+		ID:           3,
+		UserUsername: username,
+		Kind:         jobKindSummary,
+		Status:       jobStatusSucceeded,
+		Result:       json.RawMessage(`{"summary_text":"hello"}`),
+		Attempts:     1,
+		MaxAttempts:  3,
+		FinishedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		id            int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OK",
+			id:   finished.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(finished.ID)).Times(1).Return(finished, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got jobResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, jobStatusSucceeded, got.Status)
+				require.JSONEq(t, `{"summary_text":"hello"}`, string(got.Result))
+				require.NotNil(t, got.FinishedAt)
+			},
+		},
+		{
+			name: "Forbidden",
+			id:   4,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(int64(4))).Times(1).Return(db.Job{ID: 4, UserUsername: "someoneelse"}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   5,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(db.Job{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/jobs/%d", tc.id), nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
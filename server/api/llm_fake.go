@@ -0,0 +1,117 @@
+// server/api/llm_fake.go
+
+package api
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// fakeLLMProvider is a deterministic, offline LLMProvider for tests and CI.
+// Replies queued with Enqueue are returned in order; once the queue is empty
+// Chat echoes the last user message and ChatJSON returns "{}". Tool call
+// turns queued with EnqueueToolCalls are answered first by ChatStreamTools.
+type fakeLLMProvider struct {
+	mu        sync.Mutex
+	replies   []string
+	toolTurns [][]llmToolCall
+	calls     [][]aiMessage
+}
+
+func newFakeLLMProvider(replies ...string) *fakeLLMProvider {
+	return &fakeLLMProvider{replies: replies}
+}
+
+// Enqueue appends canned replies to be returned by subsequent calls.
+func (p *fakeLLMProvider) Enqueue(replies ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.replies = append(p.replies, replies...)
+}
+
+// EnqueueToolCalls queues one model turn that makes the given tool calls.
+func (p *fakeLLMProvider) EnqueueToolCalls(calls ...llmToolCall) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.toolTurns = append(p.toolTurns, calls)
+}
+
+// Calls returns every conversation the provider has been asked to complete.
+func (p *fakeLLMProvider) Calls() [][]aiMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([][]aiMessage(nil), p.calls...)
+}
+
+func (p *fakeLLMProvider) Name() string {
+	return llmProviderFake
+}
+
+func (p *fakeLLMProvider) Chat(ctx context.Context, messages []aiMessage) (string, error) {
+	return p.next(ctx, messages, "fake reply: "+lastUserMessage(messages))
+}
+
+func (p *fakeLLMProvider) ChatJSON(ctx context.Context, messages []aiMessage) (string, error) {
+	return p.next(ctx, messages, "{}")
+}
+
+func (p *fakeLLMProvider) ChatStream(ctx context.Context, messages []aiMessage, onDelta func(delta string) error) error {
+	reply, err := p.Chat(ctx, messages)
+	if err != nil {
+		return err
+	}
+	for i, word := range strings.SplitAfter(reply, " ") {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if word == "" && i > 0 {
+			continue
+		}
+		if err := onDelta(word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChatStreamTools reports no usage, so the chat estimates it.
+func (p *fakeLLMProvider) ChatStreamTools(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error) {
+	p.mu.Lock()
+	if len(p.toolTurns) > 0 && len(tools) > 0 {
+		turn := p.toolTurns[0]
+		p.toolTurns = p.toolTurns[1:]
+		p.calls = append(p.calls, append([]aiMessage(nil), messages...))
+		p.mu.Unlock()
+		return llmStreamResult{ToolCalls: turn}, ctx.Err()
+	}
+	p.mu.Unlock()
+	return llmStreamResult{}, p.ChatStream(ctx, messages, onDelta)
+}
+
+func (p *fakeLLMProvider) next(ctx context.Context, messages []aiMessage, fallback string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, append([]aiMessage(nil), messages...))
+
+	if len(p.replies) == 0 {
+		return fallback, nil
+	}
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return reply, nil
+}
+
+// lastUserMessage returns the content of the most recent user message.
+func lastUserMessage(messages []aiMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
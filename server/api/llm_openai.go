@@ -0,0 +1,274 @@
+// server/api/llm_openai.go
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	openAIBaseURL      = "https://api.openai.com/v1"
+	defaultOpenAIModel = "gpt-4o-mini"
+)
+
+// openAIChatRequest models the payload sent to OpenAI's chat completions endpoint.
+type openAIChatRequest struct {
+	Model          string               `json:"model"`
+	Messages       []aiMessage          `json:"messages"`
+	Stream         bool                 `json:"stream"`
+	ResponseFormat map[string]string    `json:"response_format,omitempty"`
+	Tools          []llmTool            `json:"tools,omitempty"`
+	StreamOptions  *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+// openAIStreamOptions asks for a final stream chunk with the token usage.
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// openAIChatResponse models the non-streaming response structure from OpenAI.
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage *llmUsage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// openAIStreamChunk models one `data:` line of a streamed completion. Tool
+// calls arrive in pieces: the first piece of each call (by Index) has its ID
+// and name, later ones append to its arguments. With include_usage the last
+// chunk has no choices, only the usage.
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int             `json:"index"`
+				ID       string          `json:"id"`
+				Type     string          `json:"type"`
+				Function llmFunctionCall `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *llmUsage `json:"usage"`
+}
+
+// openAICompatibleProvider talks to any server implementing OpenAI's
+// /chat/completions API: OpenAI itself, Ollama, vLLM, LM Studio, ...
+type openAICompatibleProvider struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	model      string
+	requireKey bool
+	httpClient *http.Client
+}
+
+// newOpenAIProvider returns a provider for the hosted OpenAI API.
+func newOpenAIProvider(apiKey, model string) LLMProvider {
+	p := newOpenAICompatibleProvider(openAIBaseURL, apiKey, model).(*openAICompatibleProvider)
+	p.name = llmProviderOpenAI
+	p.requireKey = true
+	return p
+}
+
+// newOpenAICompatibleProvider returns a provider for a self-hosted server such
+// as Ollama (http://localhost:11434/v1) or vLLM. The API key is optional.
+func newOpenAICompatibleProvider(baseURL, apiKey, model string) LLMProvider {
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	return &openAICompatibleProvider{
+		name:    llmProviderOpenAICompatible,
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		apiKey:  apiKey,
+		model:   model,
+		httpClient: &http.Client{
+			Timeout: 480 * time.Second,
+		},
+	}
+}
+
+func (p *openAICompatibleProvider) Name() string {
+	return p.name
+}
+
+func (p *openAICompatibleProvider) Chat(ctx context.Context, messages []aiMessage) (string, error) {
+	return p.complete(ctx, messages, false)
+}
+
+func (p *openAICompatibleProvider) ChatJSON(ctx context.Context, messages []aiMessage) (string, error) {
+	return p.complete(ctx, messages, true)
+}
+
+// complete sends a blocking request to /chat/completions.
+func (p *openAICompatibleProvider) complete(ctx context.Context, messages []aiMessage, expectJSON bool) (string, error) {
+	reqBody := openAIChatRequest{
+		Model:    p.model,
+		Messages: messages,
+		Stream:   false,
+	}
+	if expectJSON {
+		reqBody.ResponseFormat = map[string]string{"type": "json_object"}
+	}
+
+	log.Printf("[AI] Sending request to %s: model=%s, len(messages)=%d", p.name, p.model, len(messages))
+
+	resp, err := p.post(ctx, reqBody)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var out openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		log.Printf("[AI ERROR] failed to decode %s response: %v\nResponse body (truncated): %s", p.name, err, string(body))
+		return "", fmt.Errorf("invalid %s response: %w", p.name, err)
+	}
+
+	if out.Error != nil && out.Error.Message != "" {
+		return "", fmt.Errorf("%s error: %s (%s)", p.name, out.Error.Message, out.Error.Type)
+	}
+	if len(out.Choices) == 0 {
+		return "", fmt.Errorf("%s response had no choices", p.name)
+	}
+
+	reportLLMUsage(ctx, p.model, out.Usage)
+	content := out.Choices[0].Message.Content
+	log.Printf("[AI] %s response (first 200 chars): %s", p.name, truncate(content, 200))
+	return content, nil
+}
+
+func (p *openAICompatibleProvider) ChatStream(ctx context.Context, messages []aiMessage, onDelta func(delta string) error) error {
+	_, err := p.stream(ctx, messages, nil, onDelta)
+	return err
+}
+
+func (p *openAICompatibleProvider) ChatStreamTools(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error) {
+	return p.stream(ctx, messages, tools, onDelta)
+}
+
+// stream sends a streaming request to /chat/completions, passing content
+// fragments to onDelta and assembling the tool calls and usage.
+func (p *openAICompatibleProvider) stream(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error) {
+	resp, err := p.post(ctx, openAIChatRequest{
+		Model:         p.model,
+		Messages:      messages,
+		Stream:        true,
+		Tools:         tools,
+		StreamOptions: &openAIStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return llmStreamResult{}, err
+	}
+	defer resp.Body.Close()
+
+	var result llmStreamResult
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF {
+				reportLLMUsage(ctx, p.model, result.Usage)
+				return result, nil
+			}
+			return llmStreamResult{}, fmt.Errorf("%s stream read error: %w", p.name, err)
+		}
+
+		text := strings.TrimSpace(string(line))
+		if !strings.HasPrefix(text, "data: ") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(text, "data: "))
+		if data == "" {
+			continue
+		}
+		if data == "[DONE]" {
+			reportLLMUsage(ctx, p.model, result.Usage)
+			return result, nil
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if chunk.Usage != nil {
+			result.Usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta
+		for _, tc := range delta.ToolCalls {
+			if tc.Index < 0 {
+				continue
+			}
+			for len(result.ToolCalls) <= tc.Index {
+				result.ToolCalls = append(result.ToolCalls, llmToolCall{Type: "function"})
+			}
+			call := &result.ToolCalls[tc.Index]
+			if tc.ID != "" {
+				call.ID = tc.ID
+			}
+			call.Function.Name += tc.Function.Name
+			call.Function.Arguments += tc.Function.Arguments
+		}
+		if delta.Content == "" {
+			continue
+		}
+
+		if err := onDelta(delta.Content); err != nil {
+			return llmStreamResult{}, err
+		}
+	}
+}
+
+// post sends body to /chat/completions and returns the response once the
+// status code has been checked. The caller must close the body.
+func (p *openAICompatibleProvider) post(ctx context.Context, body openAIChatRequest) (*http.Response, error) {
+	if p.requireKey && p.apiKey == "" {
+		return nil, fmt.Errorf("missing OpenAI API key")
+	}
+
+	b, _ := json.Marshal(body)
+	reportLLMUsage(ctx, body.Model, nil)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", p.name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", p.name, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return nil, fmt.Errorf("%s returned %d: %s", p.name, resp.StatusCode, string(bodyBytes))
+	}
+	return resp, nil
+}
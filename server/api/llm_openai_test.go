@@ -0,0 +1,173 @@
+// server/api/llm_openai_test.go
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCompletionsServer fakes an OpenAI-compatible /chat/completions endpoint.
+// It records the last decoded request into *got.
+func newTestCompletionsServer(t *testing.T, got *openAIChatRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/chat/completions", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(got))
+
+		if got.Model == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "upstream down")
+			return
+		}
+
+		if got.Stream && len(got.Tools) > 0 {
+			// A tool call streamed in pieces, after some text
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Checking\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"id\":\"call_1\",\"type\":\"function\",\"function\":{\"name\":\"check_prerequisites\",\"arguments\":\"\"}}]}}]}\n\n")
+			for _, part := range []string{`{"course_`, `code":"TIES454"}`} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"tool_calls\":[{\"index\":0,\"function\":{\"arguments\":%q}}]}}]}\n\n", part)
+			}
+			fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":20,\"completion_tokens\":7,\"total_tokens\":27}}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		if got.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, part := range []string{"Hel", "lo"} {
+				fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":{\"content\":%q}}]}\n\n", part)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"{\"ok\":true}"}}],"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`)
+	}))
+}
+
+func TestOpenAICompatibleProvider(t *testing.T) {
+	var got openAIChatRequest
+	srv := newTestCompletionsServer(t, &got)
+	defer srv.Close()
+
+	messages := []aiMessage{{Role: "user", Content: "hi"}}
+
+	t.Run("Chat", func(t *testing.T) {
+		provider := newOpenAICompatibleProvider(srv.URL+"/v1/", "", "llama3")
+		reply, err := provider.Chat(context.Background(), messages)
+		require.NoError(t, err)
+		require.Equal(t, `{"ok":true}`, reply)
+		require.Equal(t, "llama3", got.Model)
+		require.Nil(t, got.ResponseFormat)
+	})
+
+	t.Run("ChatJSON", func(t *testing.T) {
+		provider := newOpenAICompatibleProvider(srv.URL+"/v1", "", "llama3")
+		_, err := provider.ChatJSON(context.Background(), messages)
+		require.NoError(t, err)
+		require.Equal(t, "json_object", got.ResponseFormat["type"])
+	})
+
+	t.Run("ChatStream", func(t *testing.T) {
+		provider := newOpenAICompatibleProvider(srv.URL+"/v1", "", "llama3")
+		var sb strings.Builder
+		err := provider.ChatStream(context.Background(), messages, func(delta string) error {
+			sb.WriteString(delta)
+			return nil
+		})
+		require.NoError(t, err)
+		require.True(t, got.Stream)
+		require.Equal(t, "Hello", sb.String())
+	})
+
+	t.Run("ChatStreamTools", func(t *testing.T) {
+		provider := newOpenAICompatibleProvider(srv.URL+"/v1", "", "llama3").(ToolCaller)
+		tools := []llmTool{{Type: "function", Function: llmToolFunction{Name: "check_prerequisites", Parameters: json.RawMessage(`{"type":"object"}`)}}}
+		var sb strings.Builder
+		result, err := provider.ChatStreamTools(context.Background(), messages, tools, func(delta string) error {
+			sb.WriteString(delta)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, "check_prerequisites", got.Tools[0].Function.Name)
+		require.True(t, got.StreamOptions.IncludeUsage)
+		require.Equal(t, "Checking", sb.String())
+		require.Equal(t, []llmToolCall{{
+			ID:       "call_1",
+			Type:     "function",
+			Function: llmFunctionCall{Name: "check_prerequisites", Arguments: `{"course_code":"TIES454"}`},
+		}}, result.ToolCalls)
+		require.Equal(t, &llmUsage{PromptTokens: 20, CompletionTokens: 7, TotalTokens: 27}, result.Usage)
+	})
+
+	t.Run("UpstreamError", func(t *testing.T) {
+		provider := newOpenAICompatibleProvider(srv.URL+"/v1", "", "broken")
+		_, err := provider.Chat(context.Background(), messages)
+		require.ErrorContains(t, err, "returned 502")
+	})
+
+	t.Run("OpenAIRequiresKey", func(t *testing.T) {
+		provider := newOpenAIProvider("", "")
+		_, err := provider.Chat(context.Background(), messages)
+		require.EqualError(t, err, "missing OpenAI API key")
+	})
+}
+
+func TestFakeLLMProvider(t *testing.T) {
+	provider := newFakeLLMProvider(`{"completed_codes":["TIES454"]}`)
+	messages := []aiMessage{{Role: "system", Content: "sys"}, {Role: "user", Content: "hello there"}}
+
+	reply, err := provider.ChatJSON(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, `{"completed_codes":["TIES454"]}`, reply)
+
+	reply, err = provider.ChatJSON(context.Background(), messages)
+	require.NoError(t, err)
+	require.Equal(t, "{}", reply)
+
+	var deltas []string
+	err = provider.ChatStream(context.Background(), messages, func(delta string) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "fake reply: hello there", strings.Join(deltas, ""))
+	require.Greater(t, len(deltas), 1)
+	require.Len(t, provider.Calls(), 3)
+}
+
+func TestNewLLMProvider(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   util.Config
+		wantName string
+		wantErr  bool
+	}{
+		{name: "DefaultOpenAI", config: util.Config{}, wantName: llmProviderOpenAI},
+		{name: "Compatible", config: util.Config{LLMProvider: "openai_compatible", LLMBaseURL: "http://localhost:11434/v1"}, wantName: llmProviderOpenAICompatible},
+		{name: "CompatibleWithoutURL", config: util.Config{LLMProvider: "openai_compatible"}, wantErr: true},
+		{name: "Fake", config: util.Config{LLMProvider: "FAKE"}, wantName: llmProviderFake},
+		{name: "Unknown", config: util.Config{LLMProvider: "gemini"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := newLLMProvider(tc.config)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.wantName, provider.Name())
+		})
+	}
+}
@@ -0,0 +1,409 @@
+// server/api/llm_usage.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Features an LLM call is metered under (llm_usage.feature).
+const (
+	llmFeatureChat           = "chat"
+	llmFeatureRecommendation = "recommendation"
+	llmFeatureScholarship    = "scholarship"
+	llmFeatureSummary        = "summary"
+	llmFeatureTranscript     = "transcript"
+	llmFeatureWarmup         = "warmup"
+	llmFeatureOther          = "other"
+)
+
+var errLLMBudgetExceeded = errors.New("daily AI token budget used up")
+
+const (
+	llmUsageStatusOK    = "ok"
+	llmUsageStatusError = "error"
+
+	usageReportDateLayout  = "2006-01-02"
+	defaultUsageReportDays = 30
+)
+
+// jobLLMFeatures is the feature of the LLM calls made by each job kind.
+var jobLLMFeatures = map[string]string{
+	jobKindTranscript:     llmFeatureTranscript,
+	jobKindRecommendation: llmFeatureRecommendation,
+	jobKindScholarships:   llmFeatureScholarship,
+	jobKindSummary:        llmFeatureSummary,
+}
+
+type llmCallerContextKey struct{}
+
+// llmCaller is who an LLM call is metered for.
+type llmCaller struct {
+	Username string
+	Feature  string
+}
+
+// withLLMCaller labels the LLM calls made with ctx with the user and the
+// feature they serve. An empty username meters the calls to nobody.
+func withLLMCaller(ctx context.Context, username, feature string) context.Context {
+	return context.WithValue(ctx, llmCallerContextKey{}, llmCaller{Username: username, Feature: feature})
+}
+
+type llmCallReportContextKey struct{}
+
+// llmCallReport is what a provider knows about one call beyond its reply.
+type llmCallReport struct {
+	Model string
+	Usage *llmUsage
+}
+
+// reportLLMUsage passes the model and the token usage of the current call
+// to the metering wrapper. Outside a metered call it does nothing.
+func reportLLMUsage(ctx context.Context, model string, usage *llmUsage) {
+	report, ok := ctx.Value(llmCallReportContextKey{}).(*llmCallReport)
+	if !ok {
+		return
+	}
+	report.Model = model
+	if usage != nil {
+		report.Usage = usage
+	}
+}
+
+// estimateLLMUsage approximates the usage of a call the provider reported
+// none for.
+func estimateLLMUsage(messages []aiMessage, reply string) *llmUsage {
+	usage := &llmUsage{CompletionTokens: int(estimateTokens(reply))}
+	for _, m := range messages {
+		usage.PromptTokens += int(estimateTokens(m.Content))
+	}
+	usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	return usage
+}
+
+// meteredLLM records every call to the wrapped provider in llm_usage: the
+// tokens (reported, or estimated from the text), the cost, the latency and
+// the user and feature the context is labelled with.
+type meteredLLM struct {
+	LLMProvider
+	store db.Store
+
+	// USD per million tokens.
+	promptPrice     float64
+	completionPrice float64
+}
+
+// newMeteredLLM wraps provider, keeping it a ToolCaller when it is one.
+func newMeteredLLM(provider LLMProvider, store db.Store, config util.Config) LLMProvider {
+	m := &meteredLLM{
+		LLMProvider:     provider,
+		store:           store,
+		promptPrice:     config.LLMPromptPricePerMTok,
+		completionPrice: config.LLMCompletionPricePerMTok,
+	}
+	if caller, ok := provider.(ToolCaller); ok {
+		return &meteredToolCaller{meteredLLM: m, caller: caller}
+	}
+	return m
+}
+
+func (m *meteredLLM) Chat(ctx context.Context, messages []aiMessage) (string, error) {
+	ctx, done := m.begin(ctx, messages)
+	reply, err := m.LLMProvider.Chat(ctx, messages)
+	done(reply, err)
+	return reply, err
+}
+
+func (m *meteredLLM) ChatJSON(ctx context.Context, messages []aiMessage) (string, error) {
+	ctx, done := m.begin(ctx, messages)
+	reply, err := m.LLMProvider.ChatJSON(ctx, messages)
+	done(reply, err)
+	return reply, err
+}
+
+func (m *meteredLLM) ChatStream(ctx context.Context, messages []aiMessage, onDelta func(delta string) error) error {
+	ctx, done := m.begin(ctx, messages)
+	var reply strings.Builder
+	err := m.LLMProvider.ChatStream(ctx, messages, func(delta string) error {
+		reply.WriteString(delta)
+		return onDelta(delta)
+	})
+	done(reply.String(), err)
+	return err
+}
+
+// meteredToolCaller is a meteredLLM around a provider that can call tools.
+type meteredToolCaller struct {
+	*meteredLLM
+	caller ToolCaller
+}
+
+func (m *meteredToolCaller) ChatStreamTools(ctx context.Context, messages []aiMessage, tools []llmTool, onDelta func(delta string) error) (llmStreamResult, error) {
+	ctx, done := m.begin(ctx, messages)
+	var reply strings.Builder
+	result, err := m.caller.ChatStreamTools(ctx, messages, tools, func(delta string) error {
+		reply.WriteString(delta)
+		return onDelta(delta)
+	})
+	// Tool call arguments are completion tokens too
+	for _, call := range result.ToolCalls {
+		reply.WriteString(call.Function.Arguments)
+	}
+	done(reply.String(), err)
+	return result, err
+}
+
+// begin starts metering a call. The returned context collects what the
+// provider reports; the returned function records the call.
+func (m *meteredLLM) begin(ctx context.Context, messages []aiMessage) (context.Context, func(reply string, err error)) {
+	ctx, finish := beginLLMCall(ctx, m.store, m.Name(), m.cost)
+	return ctx, func(reply string, err error) {
+		finish(func() *llmUsage { return estimateLLMUsage(messages, reply) }, err)
+	}
+}
+
+// beginLLMCall starts metering a call to provider. The returned context
+// collects what the provider reports; finish records the call, with the
+// usage from estimate when the provider reported none.
+func beginLLMCall(ctx context.Context, store db.Store, provider string, cost func(*llmUsage) float64) (context.Context, func(estimate func() *llmUsage, err error)) {
+	report := &llmCallReport{}
+	ctx = context.WithValue(ctx, llmCallReportContextKey{}, report)
+	start := time.Now()
+	return ctx, func(estimate func() *llmUsage, err error) {
+		recordLLMCall(ctx, store, provider, report, estimate, cost, time.Since(start), err)
+	}
+}
+
+func recordLLMCall(ctx context.Context, store db.Store, provider string, report *llmCallReport, estimate func() *llmUsage, cost func(*llmUsage) float64, latency time.Duration, callErr error) {
+	caller, _ := ctx.Value(llmCallerContextKey{}).(llmCaller)
+	feature := caller.Feature
+	if feature == "" {
+		feature = llmFeatureOther
+	}
+
+	arg := db.CreateLLMUsageParams{
+		UserUsername: sql.NullString{String: caller.Username, Valid: caller.Username != ""},
+		Feature:      feature,
+		Provider:     provider,
+		Model:        report.Model,
+		LatencyMs:    int32(latency.Milliseconds()),
+		Status:       llmUsageStatusOK,
+	}
+	if callErr != nil {
+		arg.Status = llmUsageStatusError
+	}
+
+	// A failed call the provider reported no usage for counts no tokens.
+	usage := report.Usage
+	if usage == nil && callErr == nil {
+		usage = estimate()
+		arg.Estimated = true
+	}
+	if usage != nil {
+		arg.PromptTokens = int32(usage.PromptTokens)
+		arg.CompletionTokens = int32(usage.CompletionTokens)
+		arg.TotalTokens = int32(usage.TotalTokens)
+		arg.CostUsd = cost(usage)
+	}
+
+	// The call is recorded even when the request that made it was cancelled
+	if _, err := store.CreateLLMUsage(context.WithoutCancel(ctx), arg); err != nil {
+		log.Printf("[LLM-USAGE] Record %s call failed: %v", feature, err)
+	}
+}
+
+// cost is the price of the tokens in USD.
+func (m *meteredLLM) cost(usage *llmUsage) float64 {
+	return (float64(usage.PromptTokens)*m.promptPrice + float64(usage.CompletionTokens)*m.completionPrice) / 1e6
+}
+
+// meteredEmbedder records every call to a remote embedder in llm_usage like
+// meteredLLM does, under the feature of the caller (chat retrieval counts as
+// chat). Embedding tokens are prompt tokens.
+type meteredEmbedder struct {
+	Embedder
+	store db.Store
+	price float64 // USD per million tokens
+}
+
+// newMeteredEmbedder wraps a remote embedder. The hashing embedder runs in
+// process and costs nothing, so it is returned as is.
+func newMeteredEmbedder(embedder Embedder, store db.Store, config util.Config) Embedder {
+	if _, ok := embedder.(*hashingEmbedder); ok {
+		return embedder
+	}
+	return &meteredEmbedder{Embedder: embedder, store: store, price: config.EmbeddingPricePerMTok}
+}
+
+func (e *meteredEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	provider, _, _ := strings.Cut(e.Name(), ":")
+	ctx, finish := beginLLMCall(ctx, e.store, provider, e.cost)
+	vectors, err := e.Embedder.Embed(ctx, texts)
+	finish(func() *llmUsage {
+		usage := &llmUsage{}
+		for _, text := range texts {
+			usage.PromptTokens += int(estimateTokens(text))
+		}
+		usage.TotalTokens = usage.PromptTokens
+		return usage
+	}, err)
+	return vectors, err
+}
+
+func (e *meteredEmbedder) cost(usage *llmUsage) float64 {
+	return float64(usage.PromptTokens) * e.price / 1e6
+}
+
+// llmBudgetReset returns when the user's daily token budget resets, or the
+// zero time while they are within it (or there is no budget).
+func (s *Server) llmBudgetReset(ctx context.Context, username string) (time.Time, error) {
+	budget := s.config.LLMDailyTokenBudget
+	if budget <= 0 {
+		return time.Time{}, nil
+	}
+	dayStart := time.Now().UTC().Truncate(24 * time.Hour)
+	used, err := s.store.SumUserLLMTokens(ctx, db.SumUserLLMTokensParams{
+		Username: username,
+		Since:    dayStart,
+	})
+	if err != nil || used < budget {
+		return time.Time{}, err
+	}
+	return dayStart.Add(24 * time.Hour), nil
+}
+
+// requireLLMBudget answers 429 once the user's LLM calls used
+// LLM_DAILY_TOKEN_BUDGET tokens today (UTC). Resuming a chat reply with
+// Last-Event-ID makes no new call and always passes.
+func (s *Server) requireLLMBudget(c *fiber.Ctx) error {
+	budget := s.config.LLMDailyTokenBudget
+	if budget <= 0 || c.Get("Last-Event-ID") != "" {
+		return c.Next()
+	}
+
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	reset, err := s.llmBudgetReset(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if !reset.IsZero() {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(time.Until(reset).Seconds())+1))
+		return c.Status(fiber.StatusTooManyRequests).JSON(errorResponse(
+			fmt.Errorf("daily AI token budget of %d tokens used up, it resets at %s", budget, reset.Format(time.RFC3339))))
+	}
+	return c.Next()
+}
+
+type llmUsageReportRequest struct {
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Username string `query:"username" validate:"omitempty,max=100"`
+	Feature  string `query:"feature" validate:"omitempty,max=50"`
+}
+
+type llmUsageReportRow struct {
+	Day              string  `json:"day"`
+	Feature          string  `json:"feature"`
+	Username         string  `json:"username"`
+	Calls            int64   `json:"calls"`
+	Errors           int64   `json:"errors"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	AvgLatencyMs     float64 `json:"avg_latency_ms"`
+}
+
+type llmUsageTotals struct {
+	Calls            int64   `json:"calls"`
+	Errors           int64   `json:"errors"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	TotalTokens      int64   `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+type llmUsageReportResponse struct {
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Rows   []llmUsageReportRow `json:"rows"`
+	Totals llmUsageTotals      `json:"totals"`
+}
+
+// GET /api/admin/usage?from=2026-10-01&to=2026-10-17&username=&feature=
+// LLM usage by day, feature and user. from and to are inclusive UTC days,
+// by default the last 30 days.
+func (s *Server) llmUsageReport(c *fiber.Ctx) error {
+	var req llmUsageReportRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		to, _ = time.Parse(usageReportDateLayout, req.To)
+	}
+	from := to.AddDate(0, 0, 1-defaultUsageReportDays)
+	if req.From != "" {
+		from, _ = time.Parse(usageReportDateLayout, req.From)
+	}
+	if from.After(to) {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("from must not be after to")))
+	}
+
+	rows, err := s.store.ReportLLMUsage(c.Context(), db.ReportLLMUsageParams{
+		FromTime: from,
+		ToTime:   to.AddDate(0, 0, 1),
+		Username: req.Username,
+		Feature:  req.Feature,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	resp := llmUsageReportResponse{
+		From: from.Format(usageReportDateLayout),
+		To:   to.Format(usageReportDateLayout),
+		Rows: make([]llmUsageReportRow, 0, len(rows)),
+	}
+	for _, r := range rows {
+		resp.Rows = append(resp.Rows, llmUsageReportRow{
+			Day:              r.Day.Format(usageReportDateLayout),
+			Feature:          r.Feature,
+			Username:         r.Username,
+			Calls:            r.Calls,
+			Errors:           r.Errors,
+			PromptTokens:     r.PromptTokens,
+			CompletionTokens: r.CompletionTokens,
+			TotalTokens:      r.TotalTokens,
+			CostUSD:          r.CostUsd,
+			AvgLatencyMs:     r.AvgLatencyMs,
+		})
+		resp.Totals.Calls += r.Calls
+		resp.Totals.Errors += r.Errors
+		resp.Totals.PromptTokens += r.PromptTokens
+		resp.Totals.CompletionTokens += r.CompletionTokens
+		resp.Totals.TotalTokens += r.TotalTokens
+		resp.Totals.CostUSD += r.CostUsd
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,432 @@
+// server/api/llm_usage_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeteredLLM(t *testing.T) {
+	var got openAIChatRequest
+	srv := newTestCompletionsServer(t, &got)
+	defer srv.Close()
+
+	config := util.Config{LLMPromptPricePerMTok: 0.15, LLMCompletionPricePerMTok: 0.60}
+	messages := []aiMessage{{Role: "user", Content: "12345678"}}
+
+	testCases := []struct {
+		name     string
+		provider LLMProvider
+		call     func(t *testing.T, ctx context.Context, llm LLMProvider) error
+		check    func(t *testing.T, arg db.CreateLLMUsageParams)
+	}{
+		{
+			name:     "ReportedUsage",
+			provider: newOpenAICompatibleProvider(srv.URL+"/v1", "", "llama3"),
+			call: func(t *testing.T, ctx context.Context, llm LLMProvider) error {
+				_, err := llm.ChatJSON(withLLMCaller(ctx, "student", llmFeatureSummary), messages)
+				return err
+			},
+			check: func(t *testing.T, arg db.CreateLLMUsageParams) {
+				require.Equal(t, sql.NullString{String: "student", Valid: true}, arg.UserUsername)
+				require.Equal(t, llmFeatureSummary, arg.Feature)
+				require.Equal(t, llmProviderOpenAICompatible, arg.Provider)
+				require.Equal(t, "llama3", arg.Model)
+				require.Equal(t, int32(12), arg.PromptTokens)
+				require.Equal(t, int32(3), arg.CompletionTokens)
+				require.Equal(t, int32(15), arg.TotalTokens)
+				require.False(t, arg.Estimated)
+				require.InDelta(t, (12*0.15+3*0.60)/1e6, arg.CostUsd, 1e-12)
+				require.Equal(t, llmUsageStatusOK, arg.Status)
+			},
+		},
+		{
+			name:     "StreamedToolCalls",
+			provider: newOpenAICompatibleProvider(srv.URL+"/v1", "", "llama3"),
+			call: func(t *testing.T, ctx context.Context, llm LLMProvider) error {
+				caller, ok := llm.(ToolCaller)
+				require.True(t, ok)
+				tools := []llmTool{{Type: "function", Function: llmToolFunction{Name: "check_prerequisites"}}}
+				result, err := caller.ChatStreamTools(withLLMCaller(ctx, "student", llmFeatureChat), messages, tools, func(string) error { return nil })
+				require.Len(t, result.ToolCalls, 1)
+				return err
+			},
+			check: func(t *testing.T, arg db.CreateLLMUsageParams) {
+				require.Equal(t, llmFeatureChat, arg.Feature)
+				require.Equal(t, int32(27), arg.TotalTokens)
+				require.False(t, arg.Estimated)
+			},
+		},
+		{
+			name:     "EstimatedUsage",
+			provider: newFakeLLMProvider(),
+			call: func(t *testing.T, ctx context.Context, llm LLMProvider) error {
+				_, err := llm.Chat(ctx, messages)
+				return err
+			},
+			check: func(t *testing.T, arg db.CreateLLMUsageParams) {
+				require.False(t, arg.UserUsername.Valid)
+				require.Equal(t, llmFeatureOther, arg.Feature)
+				require.Equal(t, llmProviderFake, arg.Provider)
+				require.True(t, arg.Estimated)
+				require.Equal(t, int32(6), arg.PromptTokens)
+				require.Positive(t, arg.CompletionTokens)
+				require.Equal(t, arg.PromptTokens+arg.CompletionTokens, arg.TotalTokens)
+			},
+		},
+		{
+			name:     "Error",
+			provider: newOpenAICompatibleProvider(srv.URL+"/v1", "", "broken"),
+			call: func(t *testing.T, ctx context.Context, llm LLMProvider) error {
+				_, err := llm.Chat(withLLMCaller(ctx, "student", llmFeatureRecommendation), messages)
+				require.Error(t, err)
+				return nil
+			},
+			check: func(t *testing.T, arg db.CreateLLMUsageParams) {
+				require.Equal(t, "broken", arg.Model)
+				require.Equal(t, llmUsageStatusError, arg.Status)
+				require.Zero(t, arg.TotalTokens)
+				require.Zero(t, arg.CostUsd)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+
+			var recorded db.CreateLLMUsageParams
+			store.EXPECT().CreateLLMUsage(gomock.Any(), gomock.Any()).Times(1).
+				DoAndReturn(func(_ context.Context, arg db.CreateLLMUsageParams) (db.LlmUsage, error) {
+					recorded = arg
+					return db.LlmUsage{}, nil
+				})
+
+			require.NoError(t, tc.call(t, context.Background(), newMeteredLLM(tc.provider, store, config)))
+			tc.check(t, recorded)
+		})
+	}
+}
+
+func TestMeteredEmbedder(t *testing.T) {
+	reportUsage := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reportUsage {
+			w.Write([]byte(`{"data":[{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":5,"total_tokens":5}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	var recorded []db.CreateLLMUsageParams
+	store.EXPECT().CreateLLMUsage(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, arg db.CreateLLMUsageParams) (db.LlmUsage, error) {
+			recorded = append(recorded, arg)
+			return db.LlmUsage{}, nil
+		})
+
+	config := util.Config{EmbeddingPricePerMTok: 0.02}
+	embedder := newMeteredEmbedder(newOpenAICompatibleEmbedder(srv.URL, "", "nomic-embed-text"), store, config)
+	require.Equal(t, "openai_compatible:nomic-embed-text", embedder.Name())
+
+	ctx := withLLMCaller(context.Background(), "student", llmFeatureChat)
+	_, err := embedder.Embed(ctx, []string{"machine learning"})
+	require.NoError(t, err)
+	reportUsage = false
+	_, err = embedder.Embed(ctx, []string{"12345678"})
+	require.NoError(t, err)
+
+	require.Len(t, recorded, 2)
+	require.Equal(t, sql.NullString{String: "student", Valid: true}, recorded[0].UserUsername)
+	require.Equal(t, llmFeatureChat, recorded[0].Feature)
+	require.Equal(t, embedderOpenAICompatible, recorded[0].Provider)
+	require.Equal(t, "nomic-embed-text", recorded[0].Model)
+	require.Equal(t, int32(5), recorded[0].PromptTokens)
+	require.Equal(t, int32(5), recorded[0].TotalTokens)
+	require.False(t, recorded[0].Estimated)
+	require.InDelta(t, 5*0.02/1e6, recorded[0].CostUsd, 1e-12)
+	require.True(t, recorded[1].Estimated)
+	require.Equal(t, int32(estimateTokens("12345678")), recorded[1].PromptTokens)
+	require.Zero(t, recorded[1].CompletionTokens)
+
+	// The offline embedder is not metered
+	hashing := newHashingEmbedder(hashingEmbedderDims)
+	require.Equal(t, hashing, newMeteredEmbedder(hashing, store, config))
+}
+
+func TestChatStreamMetersRetrieval(t *testing.T) {
+	username := util.RandomOwner()
+	embeddings := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIEmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		data := make([]map[string]any, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string]any{"index": i, "embedding": []float32{1, 0}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer embeddings.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListActiveCourses(gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Any()).AnyTimes().Return([]db.ListUserScholarshipsRow{{
+		ID: 3, Title: "Nordic Master Scholarship", CanonicalUrl: "https://example.org/nordic",
+	}}, nil)
+
+	var mu sync.Mutex
+	var recorded []db.CreateLLMUsageParams
+	store.EXPECT().CreateLLMUsage(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.CreateLLMUsageParams) (db.LlmUsage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			recorded = append(recorded, arg)
+			return db.LlmUsage{}, nil
+		})
+
+	server := newFiberTestServer(t, store)
+	server.embedder = newMeteredEmbedder(newOpenAICompatibleEmbedder(embeddings.URL, "", "nomic-embed-text"), store, server.config)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", strings.NewReader(`{"messages":[{"role":"user","content":"Which scholarships fit me?"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var embedCalls int
+	for _, arg := range recorded {
+		if arg.Provider != embedderOpenAICompatible {
+			continue
+		}
+		embedCalls++
+		require.Equal(t, sql.NullString{String: username, Valid: true}, arg.UserUsername)
+		require.Equal(t, llmFeatureChat, arg.Feature)
+	}
+	require.Positive(t, embedCalls)
+}
+
+func TestRequireLLMBudget(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		budget        int64
+		lastEventID   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "UnderBudget",
+			budget: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SumUserLLMTokensParams) (int64, error) {
+						require.Equal(t, username, arg.Username)
+						require.Equal(t, arg.Since.Truncate(24*time.Hour), arg.Since)
+						return 999, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				// Passed on to the handler, which rejects the empty body
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "OverBudget",
+			budget: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(1).Return(int64(1000), nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+				retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+				require.NoError(t, err)
+				require.Positive(t, retryAfter)
+				require.LessOrEqual(t, retryAfter, 24*3600+1)
+			},
+		},
+		{
+			name:        "Resume",
+			budget:      1000,
+			lastEventID: "missing:1",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:   "Unlimited",
+			budget: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SumUserLLMTokens(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			server.config.LLMDailyTokenBudget = tc.budget
+
+			req := httptest.NewRequest(http.MethodPost, "/api/chat/stream", strings.NewReader("{}"))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestLLMUsageReportAPI(t *testing.T) {
+	rows := []db.ReportLLMUsageRow{
+		{Day: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), Feature: llmFeatureChat, Username: "alice", Calls: 3, Errors: 1, PromptTokens: 300, CompletionTokens: 60, TotalTokens: 360, CostUsd: 0.0001, AvgLatencyMs: 850},
+		{Day: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Feature: llmFeatureSummary, Username: "bob", Calls: 1, PromptTokens: 900, CompletionTokens: 200, TotalTokens: 1100, CostUsd: 0.0002, AvgLatencyMs: 2100},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		role          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "OK",
+			query: "?from=2026-10-01&to=2026-10-02&feature=chat",
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReportLLMUsageParams{
+					FromTime: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+					ToTime:   time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC),
+					Feature:  llmFeatureChat,
+				}
+				store.EXPECT().ReportLLMUsage(gomock.Any(), gomock.Eq(arg)).Times(1).Return(rows, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var got llmUsageReportResponse
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(body, &got))
+				require.Equal(t, "2026-10-01", got.From)
+				require.Equal(t, "2026-10-02", got.To)
+				require.Len(t, got.Rows, 2)
+				require.Equal(t, "2026-10-02", got.Rows[0].Day)
+				require.Equal(t, int64(4), got.Totals.Calls)
+				require.Equal(t, int64(1), got.Totals.Errors)
+				require.Equal(t, int64(1460), got.Totals.TotalTokens)
+				require.InDelta(t, 0.0003, got.Totals.CostUSD, 1e-12)
+			},
+		},
+		{
+			name: "DefaultRange",
+			role: util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReportLLMUsage(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.ReportLLMUsageParams) ([]db.ReportLLMUsageRow, error) {
+						require.Equal(t, time.Duration(defaultUsageReportDays)*24*time.Hour, arg.ToTime.Sub(arg.FromTime))
+						return []db.ReportLLMUsageRow{}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:  "InvalidDate",
+			query: "?from=10/01/2026",
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReportLLMUsage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:  "FromAfterTo",
+			query: "?from=2026-10-05&to=2026-10-01",
+			role:  util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReportLLMUsage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "NotAdmin",
+			role: util.StudentRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReportLLMUsage(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/usage"+tc.query, nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, util.RandomOwner(), tc.role, time.Minute)
+
+			resp, err := server.app.Test(req)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
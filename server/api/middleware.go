@@ -58,6 +58,14 @@ func authMiddlewareFiber(tokenMaker token.Maker) fiber.Handler {
 			})
 		}
 
+		// Refresh tokens outlive access tokens and are revoked through their
+		// session, so they must not authorize API calls themselves
+		if payload.Type != token.TokenTypeAccess {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "not an access token",
+			})
+		}
+
 		// Store the verified payload in Fiber locals for handlers to access
 		c.Locals(authorizationPayloadKey, payload)
 
@@ -65,3 +73,25 @@ func authMiddlewareFiber(tokenMaker token.Maker) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// authorize returns a Fiber middleware that only lets through callers whose
+// token role is one of roles. It must run after authMiddlewareFiber.
+func authorize(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+		if !ok || payload == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "unauthorized",
+			})
+		}
+
+		for _, role := range roles {
+			if payload.Role == role {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "insufficient role",
+		})
+	}
+}
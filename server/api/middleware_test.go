@@ -22,9 +22,10 @@ func addAuthorization(
 	tokenMaker token.Maker,
 	authorizationType string,
 	username string,
+	role string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(username, role, token.TokenTypeAccess, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -47,7 +48,7 @@ func TestAuthMiddlewareFiber(t *testing.T) {
 		{
 			name: "OK",
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, resp *http.Response) {
 				require.Equal(t, http.StatusOK, resp.StatusCode)
@@ -63,7 +64,7 @@ func TestAuthMiddlewareFiber(t *testing.T) {
 		{
 			name: "UnsupportedAuthorization",
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuthorization(t, req, maker, "unsupported", username, time.Minute)
+				addAuthorization(t, req, maker, "unsupported", username, util.StudentRole, time.Minute)
 			},
 			checkResponse: func(t *testing.T, resp *http.Response) {
 				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
@@ -78,10 +79,21 @@ func TestAuthMiddlewareFiber(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 			},
 		},
+		{
+			name: "RefreshToken",
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				refreshToken, _, err := maker.CreateToken(username, util.StudentRole, token.TokenTypeRefresh, time.Minute)
+				require.NoError(t, err)
+				req.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, refreshToken))
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
 		{
 			name: "ExpiredToken",
 			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
-				addAuthorization(t, req, maker, authorizationTypeBearer, username, -time.Minute)
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, util.StudentRole, -time.Minute)
 			},
 			checkResponse: func(t *testing.T, resp *http.Response) {
 				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
@@ -114,3 +126,45 @@ func TestAuthMiddlewareFiber(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------
+// Tests for Role Middleware
+// ---------------------------
+
+func TestAuthorizeMiddleware(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		role       string
+		wantStatus int
+	}{
+		{name: "Advisor", role: util.AdvisorRole, wantStatus: http.StatusOK},
+		{name: "Admin", role: util.AdminRole, wantStatus: http.StatusOK},
+		{name: "Student", role: util.StudentRole, wantStatus: http.StatusForbidden},
+		{name: "NoRole", role: "", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFiberTestServer(t, nil)
+
+			authPath := "/advisors-only"
+			server.app.Get(authPath,
+				authMiddlewareFiber(server.tokenMaker),
+				authorize(util.AdvisorRole, util.AdminRole),
+				func(ctx *fiber.Ctx) error {
+					return ctx.JSON(fiber.Map{"status": "ok"})
+				},
+			)
+
+			req, err := http.NewRequest(http.MethodGet, authPath, nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, tc.role, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// server/api/notifications.go
+
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+const (
+	defaultNotificationLimit = 50
+	defaultRemindDaysBefore  = 7
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+type listNotificationsRequest struct {
+	Unread bool  `query:"unread"`
+	Limit  int64 `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type notificationResponse struct {
+	ID        int64      `json:"id"`
+	Kind      string     `json:"kind"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Link      string     `json:"link,omitempty"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func newNotificationResponse(n db.Notification) notificationResponse {
+	resp := notificationResponse{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		Title:     n.Title,
+		Body:      n.Body,
+		Link:      n.Link.String,
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.ReadAt.Valid {
+		resp.ReadAt = &n.ReadAt.Time
+	}
+	return resp
+}
+
+type listNotificationsResponse struct {
+	Notifications []notificationResponse `json:"notifications"`
+	Unread        int64                  `json:"unread"`
+}
+
+type notificationPreferencesRequest struct {
+	DeadlineReminders *bool    `json:"deadline_reminders" validate:"required"`
+	RemindDaysBefore  int32    `json:"remind_days_before" validate:"required,min=1,max=60"`
+	Channels          []string `json:"channels" validate:"required,min=1,dive,oneof=in_app email webhook"`
+}
+
+type notificationPreferencesResponse struct {
+	DeadlineReminders bool     `json:"deadline_reminders"`
+	RemindDaysBefore  int32    `json:"remind_days_before"`
+	Channels          []string `json:"channels"`
+	// AvailableChannels are the channels this server can deliver on.
+	AvailableChannels []string `json:"available_channels"`
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// availableChannels lists the configured notifier channels, sorted.
+func (s *Server) availableChannels() []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for channel := range s.notifiers {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func (s *Server) newNotificationPreferencesResponse(p db.NotificationPreference) notificationPreferencesResponse {
+	return notificationPreferencesResponse{
+		DeadlineReminders: p.DeadlineReminders,
+		RemindDaysBefore:  p.RemindDaysBefore,
+		Channels:          p.Channels,
+		AvailableChannels: s.availableChannels(),
+	}
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// GET /api/notifications?unread=true&limit=50
+// Newest first, with the caller's unread count.
+func (s *Server) listNotifications(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req listNotificationsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultNotificationLimit
+	}
+
+	var (
+		notifications []db.Notification
+		err           error
+	)
+	if req.Unread {
+		notifications, err = s.store.ListUnreadNotifications(c.Context(), db.ListUnreadNotificationsParams{
+			UserUsername: payload.Username,
+			Limit:        req.Limit,
+		})
+	} else {
+		notifications, err = s.store.ListNotifications(c.Context(), db.ListNotificationsParams{
+			UserUsername: payload.Username,
+			Limit:        req.Limit,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	unread, err := s.store.CountUnreadNotifications(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	resp := listNotificationsResponse{
+		Notifications: make([]notificationResponse, 0, len(notifications)),
+		Unread:        unread,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, newNotificationResponse(n))
+	}
+	return c.JSON(resp)
+}
+
+// POST /api/notifications/:id/read
+func (s *Server) markNotificationRead(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	n, err := s.store.MarkNotificationRead(c.Context(), db.MarkNotificationReadParams{
+		ID:           id,
+		UserUsername: payload.Username,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if n == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("notification not found")))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// POST /api/notifications/read_all
+func (s *Server) markAllNotificationsRead(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	n, err := s.store.MarkAllNotificationsRead(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(fiber.Map{"updated": n})
+}
+
+// GET /api/notifications/preferences
+// Users without saved preferences get the defaults: reminders on, 7 days
+// before the deadline, in-app only.
+func (s *Server) getNotificationPreferences(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	prefs, err := s.store.GetNotificationPreferences(c.Context(), payload.Username)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		prefs = db.NotificationPreference{
+			UserUsername:      payload.Username,
+			DeadlineReminders: true,
+			RemindDaysBefore:  defaultRemindDaysBefore,
+			Channels:          []string{channelInApp},
+		}
+	}
+	return c.JSON(s.newNotificationPreferencesResponse(prefs))
+}
+
+// PUT /api/notifications/preferences
+// Replaces the caller's reminder preferences. Channels must be configured on
+// this server (see available_channels).
+func (s *Server) updateNotificationPreferences(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req notificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	channels := normalizeList(req.Channels)
+	for _, channel := range channels {
+		if _, ok := s.notifiers[channel]; !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("channel %q is not configured on this server", channel)))
+		}
+	}
+
+	prefs, err := s.store.UpsertNotificationPreferences(c.Context(), db.UpsertNotificationPreferencesParams{
+		UserUsername:      payload.Username,
+		DeadlineReminders: *req.DeadlineReminders,
+		RemindDaysBefore:  req.RemindDaysBefore,
+		Channels:          channels,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(s.newNotificationPreferencesResponse(prefs))
+}
@@ -0,0 +1,287 @@
+// server/api/notifications_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNotificationsAPI(t *testing.T) {
+	username := util.RandomOwner()
+	notifications := []db.Notification{
+		{ID: 2, UserUsername: username, Kind: notificationScholarshipDeadline, Title: "Deadline tomorrow: B"},
+		{ID: 1, UserUsername: username, Kind: notificationScholarshipDeadline, Title: "Deadline in 3 days: A", ReadAt: sql.NullTime{Time: time.Now(), Valid: true}},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "All",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(db.ListNotificationsParams{UserUsername: username, Limit: defaultNotificationLimit})).
+					Times(1).Return(notifications, nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(username)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got listNotificationsResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got.Notifications, 2)
+				require.Equal(t, int64(1), got.Unread)
+				require.False(t, got.Notifications[0].Read)
+				require.True(t, got.Notifications[1].Read)
+			},
+		},
+		{
+			name:  "Unread",
+			query: "?unread=true&limit=10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListUnreadNotifications(gomock.Any(), gomock.Eq(db.ListUnreadNotificationsParams{UserUsername: username, Limit: 10})).
+					Times(1).Return(notifications[:1], nil)
+				store.EXPECT().CountUnreadNotifications(gomock.Any(), gomock.Eq(username)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got listNotificationsResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Len(t, got.Notifications, 1)
+			},
+		},
+		{
+			name:  "BadLimit",
+			query: "?limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/notifications"+tc.query, nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestMarkNotificationReadAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		path       string
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name: "OK",
+			path: "/api/notifications/7/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Eq(db.MarkNotificationReadParams{ID: 7, UserUsername: username})).
+					Times(1).Return(int64(1), nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "NotFound",
+			path: "/api/notifications/8/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "InvalidID",
+			path: "/api/notifications/abc/read",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "ReadAll",
+			path: "/api/notifications/read_all",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(username)).Times(1).Return(int64(4), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestGetNotificationPreferencesAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(username)).Times(1).Return(db.NotificationPreference{}, sql.ErrNoRows)
+	server := newFiberTestServer(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/notifications/preferences", nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got notificationPreferencesResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.True(t, got.DeadlineReminders)
+	require.Equal(t, int32(defaultRemindDaysBefore), got.RemindDaysBefore)
+	require.Equal(t, []string{channelInApp}, got.Channels)
+	require.Equal(t, []string{channelInApp}, got.AvailableChannels)
+}
+
+func TestUpdateNotificationPreferencesAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		body       fiber.Map
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name: "OK",
+			body: fiber.Map{"deadline_reminders": true, "remind_days_before": 14, "channels": []string{"in_app", "webhook", "in_app"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Eq(db.UpsertNotificationPreferencesParams{
+					UserUsername:      username,
+					DeadlineReminders: true,
+					RemindDaysBefore:  14,
+					Channels:          []string{"in_app", "webhook"},
+				})).Times(1).DoAndReturn(func(_ any, arg db.UpsertNotificationPreferencesParams) (db.NotificationPreference, error) {
+					return db.NotificationPreference{
+						UserUsername:      arg.UserUsername,
+						DeadlineReminders: arg.DeadlineReminders,
+						RemindDaysBefore:  arg.RemindDaysBefore,
+						Channels:          arg.Channels,
+					}, nil
+				})
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Disable",
+			body: fiber.Map{"deadline_reminders": false, "remind_days_before": 7, "channels": []string{"in_app"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(1).Return(db.NotificationPreference{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "ChannelNotConfigured",
+			body: fiber.Map{"deadline_reminders": true, "remind_days_before": 7, "channels": []string{"email"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "UnknownChannel",
+			body: fiber.Map{"deadline_reminders": true, "remind_days_before": 7, "channels": []string{"sms"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "DaysOutOfRange",
+			body: fiber.Map{"deadline_reminders": true, "remind_days_before": 90, "channels": []string{"in_app"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "MissingToggle",
+			body: fiber.Map{"remind_days_before": 7, "channels": []string{"in_app"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+			server.notifiers[channelWebhook] = newWebhookNotifier("http://localhost/hook", "")
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPut, "/api/notifications/preferences", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// server/api/notify.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Notification channels, as stored in notification_preferences.channels.
+const (
+	channelInApp   = "in_app"
+	channelEmail   = "email"
+	channelWebhook = "webhook"
+)
+
+// Notification kinds (notifications.kind)
+const (
+	notificationScholarshipDeadline = "scholarship_deadline"
+)
+
+const (
+	webhookTimeout         = 10 * time.Second
+	webhookSignatureHeader = "X-EduSphere-Signature"
+)
+
+// notification is one message to a user, whatever the channel.
+type notification struct {
+	Username string    `json:"username"`
+	Email    string    `json:"-"`
+	FullName string    `json:"-"`
+	Kind     string    `json:"kind"`
+	Title    string    `json:"title"`
+	Body     string    `json:"body"`
+	Link     string    `json:"link,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Notifier delivers notifications over one channel.
+// Implementations must be safe for concurrent use.
+type Notifier interface {
+	// Channel is the name users pick in their preferences.
+	Channel() string
+
+	Notify(ctx context.Context, n notification) error
+}
+
+// newNotifiers returns the configured notifiers keyed by channel. In-app
+// notifications are always available; email and webhook need their settings.
+func newNotifiers(config util.Config, store db.Store, events *eventHub) map[string]Notifier {
+	notifiers := map[string]Notifier{
+		channelInApp: &inAppNotifier{store: store, events: events},
+	}
+	if strings.TrimSpace(config.SMTPHost) != "" {
+		notifiers[channelEmail] = newSMTPNotifier(config)
+	}
+	if strings.TrimSpace(config.NotifyWebhookURL) != "" {
+		notifiers[channelWebhook] = newWebhookNotifier(config.NotifyWebhookURL, config.NotifyWebhookSecret)
+	}
+	return notifiers
+}
+
+// -----------------------------------------------------------------------------
+// IN-APP
+// -----------------------------------------------------------------------------
+
+// inAppNotifier saves the notification for GET /api/notifications and pushes
+// it to the user's open event streams.
+type inAppNotifier struct {
+	store  db.Store
+	events *eventHub
+}
+
+func (n *inAppNotifier) Channel() string {
+	return channelInApp
+}
+
+func (n *inAppNotifier) Notify(ctx context.Context, msg notification) error {
+	saved, err := n.store.CreateNotification(ctx, db.CreateNotificationParams{
+		UserUsername: msg.Username,
+		Kind:         msg.Kind,
+		Title:        msg.Title,
+		Body:         msg.Body,
+		Link:         sqlStringOrNull(msg.Link),
+	})
+	if err != nil {
+		return err
+	}
+	n.events.publish(msg.Username, progressEvent{
+		Type: eventNotificationCreated,
+		Data: newNotificationResponse(saved),
+		Time: time.Now(),
+	})
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// SMTP
+// -----------------------------------------------------------------------------
+
+// smtpNotifier emails the notification to the user's address.
+type smtpNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+
+	// sendMail is smtp.SendMail; tests replace it.
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func newSMTPNotifier(config util.Config) *smtpNotifier {
+	port := config.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	n := &smtpNotifier{
+		addr:     net.JoinHostPort(config.SMTPHost, strconv.Itoa(port)),
+		from:     config.SMTPFrom,
+		sendMail: smtp.SendMail,
+	}
+	if n.from == "" {
+		n.from = config.SMTPUsername
+	}
+	if config.SMTPUsername != "" {
+		n.auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+	return n
+}
+
+func (n *smtpNotifier) Channel() string {
+	return channelEmail
+}
+
+func (n *smtpNotifier) Notify(_ context.Context, msg notification) error {
+	if msg.Email == "" {
+		return errors.New("user has no email address")
+	}
+	return n.sendMail(n.addr, n.auth, n.from, []string{msg.Email}, n.message(msg))
+}
+
+// mailSubject makes a header-safe Subject from a title that may come from
+// web results: every run of whitespace, CR and LF included, becomes one
+// space, and non-ASCII text is Q-encoded.
+func mailSubject(title string) string {
+	return mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(title), " "))
+}
+
+// message renders a plain-text email.
+func (n *smtpNotifier) message(msg notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mailSubject(msg.Title))
+	fmt.Fprintf(&b, "Date: %s\r\n", msg.Time.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	if msg.FullName != "" {
+		fmt.Fprintf(&b, "Hi %s,\r\n\r\n", msg.FullName)
+	}
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	if msg.Link != "" {
+		fmt.Fprintf(&b, "\r\n\r\n%s", msg.Link)
+	}
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
+
+// -----------------------------------------------------------------------------
+// WEBHOOK
+// -----------------------------------------------------------------------------
+
+// webhookNotifier POSTs the notification as JSON. With a secret the body is
+// signed: X-EduSphere-Signature: sha256=<hex HMAC of the body>.
+type webhookNotifier struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+func newWebhookNotifier(url, secret string) *webhookNotifier {
+	return &webhookNotifier{
+		url:        url,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (n *webhookNotifier) Channel() string {
+	return channelWebhook
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, msg notification) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.secret))
+		mac.Write(body)
+		req.Header.Set(webhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// server/api/notify_test.go
+
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func randomNotification() notification {
+	return notification{
+		Username: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+		FullName: util.RandomOwner(),
+		Kind:     notificationScholarshipDeadline,
+		Title:    "Deadline tomorrow: EDUFI Fellowship",
+		Body:     "The application deadline is 2026-10-18.",
+		Link:     "https://edufi.fi/fellowship",
+		Time:     time.Now(),
+	}
+}
+
+func TestNewNotifiers(t *testing.T) {
+	notifiers := newNotifiers(util.Config{}, nil, nil)
+	require.Len(t, notifiers, 1)
+	require.Contains(t, notifiers, channelInApp)
+
+	notifiers = newNotifiers(util.Config{SMTPHost: "smtp.example.org", NotifyWebhookURL: "https://example.org/hook"}, nil, nil)
+	require.Len(t, notifiers, 3)
+	for channel, notifier := range notifiers {
+		require.Equal(t, channel, notifier.Channel())
+	}
+	require.Equal(t, "smtp.example.org:587", notifiers[channelEmail].(*smtpNotifier).addr)
+}
+
+func TestInAppNotifier(t *testing.T) {
+	msg := randomNotification()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().CreateNotification(gomock.Any(), gomock.Eq(db.CreateNotificationParams{
+		UserUsername: msg.Username,
+		Kind:         msg.Kind,
+		Title:        msg.Title,
+		Body:         msg.Body,
+		Link:         sql.NullString{String: msg.Link, Valid: true},
+	})).Times(1).Return(db.Notification{ID: 3, UserUsername: msg.Username, Title: msg.Title}, nil)
+
+	events := newEventHub()
+	ch, unsubscribe := events.subscribe(msg.Username)
+	defer unsubscribe()
+
+	notifier := &inAppNotifier{store: store, events: events}
+	require.NoError(t, notifier.Notify(context.Background(), msg))
+
+	ev := <-ch
+	require.Equal(t, eventNotificationCreated, ev.Type)
+	require.Equal(t, int64(3), ev.Data.(notificationResponse).ID)
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	msg := randomNotification()
+
+	notifier := newSMTPNotifier(util.Config{SMTPHost: "smtp.example.org", SMTPPort: 2525, SMTPFrom: "noreply@edusphere.test"})
+	var sentTo []string
+	var sent string
+	notifier.sendMail = func(addr string, _ smtp.Auth, from string, to []string, body []byte) error {
+		require.Equal(t, "smtp.example.org:2525", addr)
+		require.Equal(t, "noreply@edusphere.test", from)
+		sentTo = to
+		sent = string(body)
+		return nil
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), msg))
+	require.Equal(t, []string{msg.Email}, sentTo)
+	require.Contains(t, sent, "Subject: "+msg.Title+"\r\n")
+	require.Contains(t, sent, "Hi "+msg.FullName)
+	require.Contains(t, sent, msg.Link)
+
+	msg.Email = ""
+	require.Error(t, notifier.Notify(context.Background(), msg))
+}
+
+func TestMailSubject(t *testing.T) {
+	require.Equal(t, "Deadline soon: DAAD Scholarship", mailSubject("Deadline soon: DAAD Scholarship"))
+	require.Equal(t, "Grant Bcc: victim@example.org", mailSubject("Grant\rBcc: victim@example.org"))
+	require.Equal(t, "Grant X-Evil: 1", mailSubject("Grant\r\n X-Evil: 1\n"))
+	require.Equal(t, "=?utf-8?q?Stipendi_p=C3=A4=C3=A4ttyy?=", mailSubject("Stipendi päättyy"))
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	msg := randomNotification()
+	secret := util.RandomString(16)
+
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(webhookSignatureHeader))
+
+		var got notification
+		require.NoError(t, json.Unmarshal(body, &got))
+		require.Equal(t, msg.Username, got.Username)
+		require.Equal(t, msg.Title, got.Title)
+		require.NotContains(t, string(body), msg.Email)
+
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	notifier := newWebhookNotifier(srv.URL, secret)
+	require.NoError(t, notifier.Notify(context.Background(), msg))
+
+	status = http.StatusBadGateway
+	err := notifier.Notify(context.Background(), msg)
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), "502"))
+}
+
+func TestDeadlineNotification(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 15, 30, 0, 0, time.UTC)
+	row := db.ListDueScholarshipRemindersRow{
+		UserUsername: util.RandomOwner(),
+		Title:        "EDUFI Fellowship",
+		CanonicalUrl: "https://edufi.fi/fellowship",
+		MatchScore:   88,
+	}
+
+	testCases := []struct {
+		deadline time.Time
+		title    string
+	}{
+		{deadline: time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), title: "Deadline today: EDUFI Fellowship"},
+		{deadline: time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC), title: "Deadline tomorrow: EDUFI Fellowship"},
+		{deadline: time.Date(2026, time.October, 24, 0, 0, 0, 0, time.UTC), title: "Deadline in 7 days: EDUFI Fellowship"},
+	}
+
+	for _, tc := range testCases {
+		row.Deadline = sql.NullTime{Time: tc.deadline, Valid: true}
+		n := deadlineNotification(row, now)
+		require.Equal(t, tc.title, n.Title)
+		require.Equal(t, row.CanonicalUrl, n.Link)
+		require.Contains(t, n.Body, tc.deadline.Format("2006-01-02"))
+		require.Contains(t, n.Body, "88%")
+	}
+}
+
+func TestSendDeadlineReminders(t *testing.T) {
+	deadline := sql.NullTime{Time: time.Now().AddDate(0, 0, 3), Valid: true}
+	inApp := db.ListDueScholarshipRemindersRow{UserUsername: util.RandomOwner(), ScholarshipID: 1, Title: "A", Deadline: deadline, Channels: []string{channelInApp, channelEmail}}
+	taken := db.ListDueScholarshipRemindersRow{UserUsername: util.RandomOwner(), ScholarshipID: 2, Title: "B", Deadline: deadline, Channels: []string{channelInApp}}
+	emailOnly := db.ListDueScholarshipRemindersRow{UserUsername: util.RandomOwner(), ScholarshipID: 3, Title: "C", Deadline: deadline, Channels: []string{channelEmail}}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListDueScholarshipReminders(gomock.Any(), gomock.Eq(int64(reminderBatchSize))).Times(1).
+		Return([]db.ListDueScholarshipRemindersRow{inApp, taken, emailOnly}, nil)
+	store.EXPECT().RecordScholarshipReminder(gomock.Any(), gomock.Any()).Times(3).
+		DoAndReturn(func(_ any, arg db.RecordScholarshipReminderParams) (int64, error) {
+			if arg.ScholarshipID == taken.ScholarshipID {
+				return 0, nil
+			}
+			return 1, nil
+		})
+	store.EXPECT().CreateNotification(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ any, arg db.CreateNotificationParams) (db.Notification, error) {
+			require.Equal(t, inApp.UserUsername, arg.UserUsername)
+			return db.Notification{ID: 1, UserUsername: arg.UserUsername}, nil
+		})
+	// Email is not configured, so the email-only reminder is released for a later run
+	store.EXPECT().DeleteScholarshipReminder(gomock.Any(), gomock.Eq(db.DeleteScholarshipReminderParams{
+		UserUsername:  emailOnly.UserUsername,
+		ScholarshipID: emailOnly.ScholarshipID,
+		Deadline:      deadline.Time,
+	})).Times(1).Return(nil)
+
+	server := newFiberTestServer(t, store)
+	sent, err := server.sendDeadlineReminders(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, sent)
+}
+
+func TestSendDeadlineRemindersListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().ListDueScholarshipReminders(gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db down"))
+	store.EXPECT().RecordScholarshipReminder(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	_, err := server.sendDeadlineReminders(context.Background())
+	require.Error(t, err)
+}
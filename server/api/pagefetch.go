@@ -0,0 +1,375 @@
+// server/api/pagefetch.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+const (
+	defaultPageFetchTimeout   = 10 * time.Second
+	defaultPageFetchMaxBytes  = 1 << 20
+	defaultPageFetchUserAgent = "EduSphereBot/1.0"
+	pageFetchWorkers          = 4
+	pageFetchMaxRedirects     = 5
+	robotsCacheTTL            = time.Hour
+)
+
+var (
+	errUnsupportedPage = errors.New("unsupported content type")
+	errBlockedAddress  = errors.New("address is not public")
+)
+
+// fetchedPage is a downloaded page reduced to plain text.
+type fetchedPage struct {
+	URL       string
+	Title     string
+	Text      string
+	Truncated bool // body was cut at the size limit
+	Cached    bool // served from the disk cache
+}
+
+// pageFetcher downloads scholarship pages for extraction. It honours
+// robots.txt, caps the body size and download time, and keeps every page it
+// downloads on disk (named by the URL's scholarship_catalog.url_hash) so a
+// run can be reproduced and tests can use stored HTML.
+// It is safe for concurrent use.
+type pageFetcher struct {
+	httpClient *http.Client
+	userAgent  string
+	maxBytes   int64
+	cacheDir   string        // "" disables the disk cache
+	cacheTTL   time.Duration // 0 keeps cached pages forever
+
+	mu     sync.Mutex
+	robots map[string]robotsEntry // by scheme://host
+}
+
+// newPageFetcher returns nil when SCHOLARSHIP_FETCH_ENABLED is false.
+func newPageFetcher(config util.Config) *pageFetcher {
+	if !config.ScholarshipFetchEnabled {
+		return nil
+	}
+	timeout := config.ScholarshipFetchTimeout
+	if timeout <= 0 {
+		timeout = defaultPageFetchTimeout
+	}
+	maxBytes := config.ScholarshipFetchMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultPageFetchMaxBytes
+	}
+	userAgent := strings.TrimSpace(config.ScholarshipFetchUserAgent)
+	if userAgent == "" {
+		userAgent = defaultPageFetchUserAgent
+	}
+	return &pageFetcher{
+		httpClient: newPageFetchClient(timeout, publicAddressOnly),
+		userAgent:  userAgent,
+		maxBytes:   maxBytes,
+		cacheDir:   strings.TrimSpace(config.ScholarshipFetchCacheDir),
+		cacheTTL:   config.ScholarshipFetchCacheTTL,
+		robots:     make(map[string]robotsEntry),
+	}
+}
+
+// newPageFetchClient returns a client that follows at most
+// pageFetchMaxRedirects http(s) redirects. control, when set, vets every
+// address the client connects to, after DNS resolution.
+func newPageFetchClient(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // a proxy would hide the real address from control
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= pageFetchMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", pageFetchMaxRedirects)
+			}
+			if !isWebURL(req.URL) {
+				return fmt.Errorf("redirect to unsupported URL %q", req.URL.Redacted())
+			}
+			return nil
+		},
+	}
+}
+
+// publicAddressOnly is a net.Dialer Control hook that refuses loopback,
+// private, link-local (cloud metadata) and other non-public addresses, so
+// search results and their redirects cannot reach the internal network.
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return fmt.Errorf("%s: %w", ip, errBlockedAddress)
+	}
+	return nil
+}
+
+func isWebURL(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Fetch returns the page at rawURL, from the disk cache when possible.
+func (f *pageFetcher) Fetch(ctx context.Context, rawURL string) (fetchedPage, error) {
+	canonical, key, ok := canonicalScholarshipURL(rawURL)
+	if !ok {
+		return fetchedPage{}, fmt.Errorf("invalid page URL %q", rawURL)
+	}
+
+	if body, ext, ok := f.readCache(key); ok {
+		page := parsePage(body, ext)
+		page.URL = canonical
+		page.Cached = true
+		return page, nil
+	}
+
+	allowed, err := f.robotsAllowed(ctx, canonical)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("robots.txt: %w", err)
+	}
+	if !allowed {
+		return fetchedPage{}, fmt.Errorf("%s: disallowed by robots.txt", canonical)
+	}
+
+	body, ext, truncated, err := f.download(ctx, strings.TrimSpace(rawURL))
+	if err != nil {
+		return fetchedPage{}, err
+	}
+	f.writeCache(key, ext, body)
+
+	page := parsePage(body, ext)
+	page.URL = canonical
+	page.Truncated = truncated
+	return page, nil
+}
+
+// FetchAll fetches the pages with pageFetchWorkers in parallel and returns
+// them by canonical URL key. Pages that fail are logged and left out.
+func (f *pageFetcher) FetchAll(ctx context.Context, urls []string) map[string]fetchedPage {
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		pages = make(map[string]fetchedPage, len(urls))
+		sem   = make(chan struct{}, pageFetchWorkers)
+	)
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		_, key, ok := canonicalScholarshipURL(u)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		wg.Add(1)
+		go func(u, key string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			page, err := f.Fetch(ctx, u)
+			if err != nil {
+				log.Printf("[FETCH] %s: %v", u, err)
+				return
+			}
+			mu.Lock()
+			pages[key] = page
+			mu.Unlock()
+		}(u, key)
+	}
+	wg.Wait()
+	return pages
+}
+
+// download GETs an HTML or plain text page, converted to UTF-8 and cut at
+// maxBytes. ext is ".html" or ".txt".
+func (f *pageFetcher) download(ctx context.Context, rawURL string) (body []byte, ext string, truncated bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if !isWebURL(req.URL) {
+		return nil, "", false, fmt.Errorf("unsupported page URL %q", rawURL)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.8")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("%s returned %d", rawURL, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/html", "application/xhtml+xml", "":
+		ext = ".html"
+	case "text/plain":
+		ext = ".txt"
+	default:
+		return nil, "", false, fmt.Errorf("%s: %w %q", rawURL, errUnsupportedPage, mediaType)
+	}
+
+	reader, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes+1), contentType)
+	if err != nil {
+		return nil, "", false, err
+	}
+	body, err = io.ReadAll(reader)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if int64(len(body)) > f.maxBytes {
+		body, truncated = body[:f.maxBytes], true
+	}
+	return body, ext, truncated, nil
+}
+
+func (f *pageFetcher) cachePath(key, ext string) string {
+	return filepath.Join(f.cacheDir, scholarshipURLHash(key)+ext)
+}
+
+// readCache returns a stored page that has not expired.
+func (f *pageFetcher) readCache(key string) ([]byte, string, bool) {
+	if f.cacheDir == "" {
+		return nil, "", false
+	}
+	for _, ext := range []string{".html", ".txt"} {
+		path := f.cachePath(key, ext)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if f.cacheTTL > 0 && time.Since(info.ModTime()) > f.cacheTTL {
+			continue
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		return body, ext, true
+	}
+	return nil, "", false
+}
+
+func (f *pageFetcher) writeCache(key, ext string, body []byte) {
+	if f.cacheDir == "" {
+		return
+	}
+	if err := os.MkdirAll(f.cacheDir, 0o755); err != nil {
+		log.Printf("[FETCH] Cache dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(f.cachePath(key, ext), body, 0o644); err != nil {
+		log.Printf("[FETCH] Cache write: %v", err)
+	}
+}
+
+// parsePage turns a stored body into a fetchedPage.
+func parsePage(body []byte, ext string) fetchedPage {
+	if ext == ".txt" {
+		return fetchedPage{Text: cleanPageText(string(body))}
+	}
+	title, text := htmlToText(bytes.NewReader(body))
+	return fetchedPage{Title: title, Text: text}
+}
+
+// Elements whose content is not page text (code, styles, menus).
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Svg: true, atom.Iframe: true, atom.Nav: true,
+}
+
+// Elements that start a new line of text.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Ul: true, atom.Ol: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Tr: true, atom.Td: true, atom.Th: true, atom.Table: true, atom.Section: true, atom.Article: true,
+	atom.Header: true, atom.Footer: true, atom.Main: true, atom.Dt: true, atom.Dd: true, atom.Dl: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Hr: true, atom.Form: true, atom.Aside: true,
+}
+
+// htmlToText returns the document title and its visible text, one block
+// element per line.
+func htmlToText(r io.Reader) (title, text string) {
+	z := html.NewTokenizer(r)
+	var sb, tb strings.Builder
+	skip := 0
+	inTitle := false
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(tb.String()), " "), cleanPageText(sb.String())
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			tag := atom.Lookup(name)
+			if skippedElements[tag] && tt != html.SelfClosingTagToken {
+				if tt == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			}
+			if tag == atom.Title {
+				inTitle = tt == html.StartTagToken
+			}
+			if blockElements[tag] {
+				sb.WriteByte('\n')
+			}
+		case html.TextToken:
+			switch {
+			case inTitle:
+				tb.Write(z.Text())
+			case skip == 0:
+				sb.Write(z.Text())
+			}
+		}
+	}
+}
+
+// cleanPageText collapses whitespace within lines and drops empty lines.
+func cleanPageText(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
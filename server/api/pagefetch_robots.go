@@ -0,0 +1,197 @@
+// server/api/pagefetch_robots.go
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const maxRobotsBytes = 512 << 10
+
+// robotsRule is one Allow or Disallow line of robots.txt.
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// robotsRules are the rules of robots.txt that apply to one user agent.
+type robotsRules struct {
+	disallowAll bool
+	rules       []robotsRule
+}
+
+type robotsEntry struct {
+	rules   robotsRules
+	expires time.Time
+}
+
+// parseRobots returns the rules of the groups naming agent's product token
+// ("EduSphereBot" of "EduSphereBot/1.0"), or of the "*" groups when no
+// group names it (RFC 9309).
+func parseRobots(data []byte, agent string) robotsRules {
+	token, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(agent)), "/")
+
+	var specific, wildcard []robotsRule
+	var hasSpecific bool
+	var groupAgents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				groupAgents, inRules = nil, false
+			}
+			value = strings.ToLower(value)
+			groupAgents = append(groupAgents, value)
+			if value == token {
+				hasSpecific = true
+			}
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // an empty Disallow allows everything
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value}
+			matchesAgent, matchesAny := false, false
+			for _, a := range groupAgents {
+				matchesAgent = matchesAgent || a == token
+				matchesAny = matchesAny || a == "*"
+			}
+			if matchesAgent {
+				specific = append(specific, rule)
+			}
+			if matchesAny {
+				wildcard = append(wildcard, rule)
+			}
+		}
+	}
+
+	if hasSpecific {
+		return robotsRules{rules: specific}
+	}
+	return robotsRules{rules: wildcard}
+}
+
+// allowed applies the longest matching rule to path (with query); Allow
+// wins a tie and no match means allowed.
+func (r robotsRules) allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || (n == best && rule.allow) {
+			best, allow = n, rule.allow
+		}
+	}
+	return allow
+}
+
+// robotsMatch reports whether a robots.txt path pattern ("*" wildcards, "$"
+// end anchor) matches path.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, p)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(p):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// robotsAllowed checks pageURL against its host's robots.txt, which is kept
+// for robotsCacheTTL. A missing robots.txt (4xx) allows everything; a
+// server error disallows everything until it is asked again.
+func (f *pageFetcher) robotsAllowed(ctx context.Context, pageURL string) (bool, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false, err
+	}
+	origin := u.Scheme + "://" + u.Host
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	f.mu.Lock()
+	entry, ok := f.robots[origin]
+	f.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.rules.allowed(path), nil
+	}
+
+	rules, err := f.fetchRobots(ctx, origin)
+	if err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	f.robots[origin] = robotsEntry{rules: rules, expires: time.Now().Add(robotsCacheTTL)}
+	f.mu.Unlock()
+	return rules.allowed(path), nil
+}
+
+func (f *pageFetcher) fetchRobots(ctx context.Context, origin string) (robotsRules, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return robotsRules{}, err
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return robotsRules{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return robotsRules{disallowAll: true}, nil
+	case resp.StatusCode != http.StatusOK:
+		return robotsRules{}, nil
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+	if err != nil {
+		return robotsRules{}, err
+	}
+	return parseRobots(data, f.userAgent), nil
+}
@@ -0,0 +1,229 @@
+// server/api/pagefetch_test.go
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPageFetcher returns a fetcher that may reach httptest servers on
+// the loopback address.
+func newTestPageFetcher(t *testing.T, cacheDir string) *pageFetcher {
+	f := newPageFetcher(util.Config{
+		ScholarshipFetchEnabled:  true,
+		ScholarshipFetchMaxBytes: 4096,
+		ScholarshipFetchCacheDir: cacheDir,
+	})
+	f.httpClient = newPageFetchClient(defaultPageFetchTimeout, nil)
+	return f
+}
+
+// storePageFixture puts a stored HTML page from testdata/pages into the
+// fetcher's disk cache as the page at pageURL.
+func storePageFixture(t *testing.T, f *pageFetcher, pageURL, name string) {
+	body, err := os.ReadFile(filepath.Join("testdata", "pages", name))
+	require.NoError(t, err)
+	_, key, ok := canonicalScholarshipURL(pageURL)
+	require.True(t, ok)
+	f.writeCache(key, ".html", body)
+}
+
+func TestHTMLToText(t *testing.T) {
+	f := newTestPageFetcher(t, t.TempDir())
+	storePageFixture(t, f, "https://example.edu/finland-scholarship", "finland_scholarship.html")
+
+	page, err := f.Fetch(context.Background(), "https://www.example.edu/finland-scholarship/?utm_source=x")
+	require.NoError(t, err)
+	require.True(t, page.Cached)
+	require.Equal(t, "https://www.example.edu/finland-scholarship", page.URL)
+	require.Equal(t, "Finland Scholarship for International Students | Example University", page.Title)
+	require.Contains(t, page.Text, "The scholarship is worth €10,000 per year and covers the tuition fee.")
+	require.Contains(t, page.Text, "Application deadline\n15th January 2027")
+	require.NotContains(t, page.Text, "dataLayer")
+	require.NotContains(t, page.Text, "font-family")
+	require.NotContains(t, page.Text, "Home")
+}
+
+func TestPageFetcher(t *testing.T) {
+	var pageHits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /private\n\nUser-agent: OtherBot\nDisallow: /\n"))
+		case "/grant":
+			pageHits.Add(1)
+			require.Equal(t, defaultPageFetchUserAgent, r.Header.Get("User-Agent"))
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><head><title>Grant</title></head><body><p>Deadline: 1 March 2027</p></body></html>"))
+		case "/big":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(strings.Repeat("a", 5000)))
+		case "/file.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.4"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	f := newTestPageFetcher(t, t.TempDir())
+
+	page, err := f.Fetch(ctx, srv.URL+"/grant")
+	require.NoError(t, err)
+	require.False(t, page.Cached)
+	require.Equal(t, "Grant", page.Title)
+	require.Equal(t, "Deadline: 1 March 2027", page.Text)
+
+	// The second fetch comes from the disk cache
+	page, err = f.Fetch(ctx, srv.URL+"/grant")
+	require.NoError(t, err)
+	require.True(t, page.Cached)
+	require.Equal(t, int32(1), pageHits.Load())
+
+	page, err = f.Fetch(ctx, srv.URL+"/big")
+	require.NoError(t, err)
+	require.True(t, page.Truncated)
+	require.Len(t, page.Text, 4096)
+
+	_, err = f.Fetch(ctx, srv.URL+"/private/grant")
+	require.ErrorContains(t, err, "robots.txt")
+
+	_, err = f.Fetch(ctx, srv.URL+"/file.pdf")
+	require.ErrorIs(t, err, errUnsupportedPage)
+
+	_, err = f.Fetch(ctx, srv.URL+"/missing")
+	require.Error(t, err)
+
+	pages := f.FetchAll(ctx, []string{srv.URL + "/grant", srv.URL + "/grant/", srv.URL + "/missing", "not a url"})
+	require.Len(t, pages, 1)
+
+	// An expired cache entry is fetched again
+	f.cacheTTL = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	_, err = f.Fetch(ctx, srv.URL+"/grant")
+	require.NoError(t, err)
+	require.Equal(t, int32(2), pageHits.Load())
+
+	require.Nil(t, newPageFetcher(util.Config{}))
+}
+
+func TestPageFetcherRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			http.NotFound(w, r)
+		case "/moved":
+			http.Redirect(w, r, "/grant", http.StatusFound)
+		case "/grant":
+			w.Write([]byte("<p>Grant</p>"))
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		default:
+			http.Redirect(w, r, r.URL.Path+"x", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	f := newTestPageFetcher(t, "")
+
+	page, err := f.Fetch(ctx, srv.URL+"/moved")
+	require.NoError(t, err)
+	require.Equal(t, "Grant", page.Text)
+
+	_, err = f.Fetch(ctx, srv.URL+"/file")
+	require.ErrorContains(t, err, "unsupported URL")
+
+	_, err = f.Fetch(ctx, srv.URL+"/loop")
+	require.ErrorContains(t, err, "redirects")
+
+	_, _, _, err = f.download(ctx, "ftp://example.edu/grant")
+	require.ErrorContains(t, err, "unsupported page URL")
+}
+
+func TestPageFetcherBlocksPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL)
+	}))
+	defer srv.Close()
+
+	f := newPageFetcher(util.Config{ScholarshipFetchEnabled: true})
+	_, err := f.Fetch(context.Background(), srv.URL+"/grant")
+	require.ErrorIs(t, err, errBlockedAddress)
+
+	for address, blocked := range map[string]bool{
+		"127.0.0.1:80":          true,
+		"10.1.2.3:443":          true,
+		"172.16.0.1:80":         true,
+		"192.168.1.1:80":        true,
+		"169.254.169.254:80":    true,
+		"0.0.0.0:80":            true,
+		"[::1]:443":             true,
+		"[fe80::1]:443":         true,
+		"[fd00::1]:443":         true,
+		"[::ffff:127.0.0.1]:80": true,
+		"93.184.216.34:443":     false,
+		"[2606:4700::1]:443":    false,
+	} {
+		err := publicAddressOnly("tcp", address, nil)
+		if blocked {
+			require.ErrorIs(t, err, errBlockedAddress, address)
+		} else {
+			require.NoError(t, err, address)
+		}
+	}
+}
+
+func TestRobotsRules(t *testing.T) {
+	robots := []byte(`# example
+User-agent: *
+Disallow: /private
+Allow: /private/open
+Disallow: /*.pdf$
+
+User-agent: EduSphereBot
+User-agent: OtherBot
+Disallow: /search
+Allow: /search/scholarships
+
+Sitemap: https://example.edu/sitemap.xml
+`)
+
+	testCases := []struct {
+		name  string
+		agent string
+		path  string
+		want  bool
+	}{
+		{name: "OwnGroupDisallow", agent: "EduSphereBot/1.0", path: "/search?q=x", want: false},
+		{name: "OwnGroupLongerAllow", agent: "EduSphereBot/1.0", path: "/search/scholarships/1", want: true},
+		{name: "OwnGroupIgnoresWildcard", agent: "EduSphereBot/1.0", path: "/private", want: true},
+		{name: "WildcardDisallow", agent: "SomeBot", path: "/private/page", want: false},
+		{name: "WildcardLongerAllow", agent: "SomeBot", path: "/private/open/page", want: true},
+		{name: "EndAnchor", agent: "SomeBot", path: "/files/guide.pdf", want: false},
+		{name: "EndAnchorNoMatch", agent: "SomeBot", path: "/files/guide.pdf?x=1", want: true},
+		{name: "NoRule", agent: "SomeBot", path: "/", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, parseRobots(robots, tc.agent).allowed(tc.path))
+		})
+	}
+
+	require.False(t, robotsRules{disallowAll: true}.allowed("/"))
+	require.True(t, parseRobots([]byte("User-agent: *\nDisallow:\n"), "EduSphereBot").allowed("/a"))
+}
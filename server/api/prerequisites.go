@@ -0,0 +1,263 @@
+// server/api/prerequisites.go
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"regexp"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Eligibility of a candidate course given the student's completed courses.
+const (
+	eligibilityEligible = "eligible"
+	eligibilityPartial  = "partially_eligible"
+	eligibilityBlocked  = "blocked"
+)
+
+const (
+	prereqOpAnd = "and"
+	prereqOpOr  = "or"
+)
+
+var (
+	prereqCodeRe = regexp.MustCompile(`\b[A-ZÅÄÖ]{2,8}\d{2,5}[A-Z]?\b`)
+	prereqOrRe   = regexp.MustCompile(`(?i)\bor\b|\btai\b|/`)
+
+	// Wording that makes unmet prerequisites a warning rather than a blocker.
+	prereqAdvisoryRe   = regexp.MustCompile(`(?i)recommend|suositel|beneficial|nice-to-have|helps|equivalent|vastaava|not mandatory|not required|welcome`)
+	prereqConcurrentRe = regexp.MustCompile(`(?i)simultaneous|concurrent|same time|yhtä aikaa|samanaikaisesti`)
+)
+
+// prereqExpr is a node of a prerequisite expression: either a course code
+// leaf or an AND/OR over child expressions.
+type prereqExpr struct {
+	Op   string       `json:"op,omitempty"`
+	Code string       `json:"code,omitempty"`
+	Args []prereqExpr `json:"args,omitempty"`
+}
+
+// coursePrerequisites is what gets stored in course_prerequisites.expression.
+type coursePrerequisites struct {
+	Expr       *prereqExpr `json:"expr,omitempty"` // nil when no course codes are referenced
+	Advisory   bool        `json:"advisory"`       // recommended / "or equivalent" wording
+	Concurrent bool        `json:"concurrent"`     // may be taken at the same time
+}
+
+// parsePrerequisites turns free text such as "KOGS1003 Empirical Research
+// Methods and KOGS1004 User Research or KOGS1005 Argumentative Design" into
+// an AND of OR-groups: and(KOGS1003, or(KOGS1004, KOGS1005)). Codes joined by
+// "or", "tai" or "/" form one group; any other separator starts a new group.
+// ownCode is ignored if the text mentions the course itself.
+func parsePrerequisites(text, ownCode string) coursePrerequisites {
+	out := coursePrerequisites{
+		Advisory:   prereqAdvisoryRe.MatchString(text),
+		Concurrent: prereqConcurrentRe.MatchString(text),
+	}
+
+	var groups [][]string
+	seen := map[string]bool{strings.ToUpper(ownCode): true}
+	prevEnd := -1
+
+	for _, loc := range prereqCodeRe.FindAllStringIndex(text, -1) {
+		code := strings.ToUpper(text[loc[0]:loc[1]])
+		between := ""
+		if prevEnd >= 0 {
+			between = text[prevEnd:loc[0]]
+		}
+		prevEnd = loc[1]
+
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		if len(groups) > 0 && prereqOrRe.MatchString(between) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], code)
+		} else {
+			groups = append(groups, []string{code})
+		}
+	}
+
+	if len(groups) == 0 {
+		return out
+	}
+
+	args := make([]prereqExpr, 0, len(groups))
+	for _, g := range groups {
+		if len(g) == 1 {
+			args = append(args, prereqExpr{Code: g[0]})
+			continue
+		}
+		alts := make([]prereqExpr, 0, len(g))
+		for _, code := range g {
+			alts = append(alts, prereqExpr{Code: code})
+		}
+		args = append(args, prereqExpr{Op: prereqOpOr, Args: alts})
+	}
+
+	if len(args) == 1 {
+		out.Expr = &args[0]
+	} else {
+		out.Expr = &prereqExpr{Op: prereqOpAnd, Args: args}
+	}
+	return out
+}
+
+// satisfied reports whether the expression holds for the completed codes.
+func (e prereqExpr) satisfied(done map[string]bool) bool {
+	switch e.Op {
+	case prereqOpAnd:
+		for _, a := range e.Args {
+			if !a.satisfied(done) {
+				return false
+			}
+		}
+		return true
+	case prereqOpOr:
+		for _, a := range e.Args {
+			if a.satisfied(done) {
+				return true
+			}
+		}
+		return false
+	default:
+		return done[e.Code]
+	}
+}
+
+// missing lists the unmet parts of the expression, one entry per top-level
+// group; OR groups are rendered as "A or B".
+func (e prereqExpr) missing(done map[string]bool) []string {
+	if e.satisfied(done) {
+		return nil
+	}
+	switch e.Op {
+	case prereqOpAnd:
+		var out []string
+		for _, a := range e.Args {
+			out = append(out, a.missing(done)...)
+		}
+		return out
+	case prereqOpOr:
+		return []string{e.String()}
+	default:
+		return []string{e.Code}
+	}
+}
+
+func (e prereqExpr) String() string {
+	if e.Op == "" {
+		return e.Code
+	}
+	parts := make([]string, 0, len(e.Args))
+	for _, a := range e.Args {
+		s := a.String()
+		if a.Op != "" {
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " "+e.Op+" ")
+}
+
+// codes lists every course code referenced by the expression.
+func (e prereqExpr) codes() []string {
+	if e.Op == "" {
+		return []string{e.Code}
+	}
+	var out []string
+	for _, a := range e.Args {
+		out = append(out, a.codes()...)
+	}
+	return out
+}
+
+// evaluate classifies a course for a student:
+//   - eligible: no coded prerequisites, or all of them completed
+//   - partially eligible: the unmet requirements are only recommended / may
+//     be taken concurrently
+//   - blocked: any required group is unmet
+func (p coursePrerequisites) evaluate(done map[string]bool) (string, []string) {
+	if p.Expr == nil {
+		return eligibilityEligible, []string{}
+	}
+	missing := p.Expr.missing(done)
+	switch {
+	case len(missing) == 0:
+		return eligibilityEligible, []string{}
+	case p.Advisory || p.Concurrent:
+		return eligibilityPartial, missing
+	default:
+		return eligibilityBlocked, missing
+	}
+}
+
+// courseEligibility is the prerequisite check result for one candidate course.
+type courseEligibility struct {
+	Status  string
+	Missing []string
+}
+
+// loadPrerequisiteGraph returns the parsed prerequisites of every course,
+// keyed by course ID. Rows that are missing or were parsed from an older
+// version of courses.prerequisites are (re)parsed and upserted.
+func (s *Server) loadPrerequisiteGraph(ctx context.Context, courses []db.Course) (map[int64]coursePrerequisites, error) {
+	stored, err := s.store.ListCoursePrerequisites(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCourse := make(map[int64]db.CoursePrerequisite, len(stored))
+	for _, row := range stored {
+		byCourse[row.CourseID] = row
+	}
+
+	graph := make(map[int64]coursePrerequisites, len(courses))
+	for _, course := range courses {
+		text := course.Prerequisites.String
+		if row, ok := byCourse[course.ID]; ok && row.SourceText == text {
+			var p coursePrerequisites
+			if err := json.Unmarshal(row.Expression, &p); err == nil {
+				graph[course.ID] = p
+				continue
+			}
+		}
+
+		p := parsePrerequisites(text, course.Code)
+		graph[course.ID] = p
+
+		expr, _ := json.Marshal(p)
+		if _, err := s.store.UpsertCoursePrerequisite(ctx, db.UpsertCoursePrerequisiteParams{
+			CourseID:   course.ID,
+			Expression: expr,
+			SourceText: text,
+		}); err != nil {
+			log.Printf("[DB] Save prerequisites for %s failed: %v", course.Code, err)
+		}
+	}
+	return graph, nil
+}
+
+// checkEligibility evaluates every candidate against the completed codes.
+func (s *Server) checkEligibility(ctx context.Context, candidates []db.Course, completedCodes []string) (map[int64]courseEligibility, error) {
+	graph, err := s.loadPrerequisiteGraph(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool, len(completedCodes))
+	for _, code := range completedCodes {
+		done[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	out := make(map[int64]courseEligibility, len(candidates))
+	for _, course := range candidates {
+		status, missing := graph[course.ID].evaluate(done)
+		out[course.ID] = courseEligibility{Status: status, Missing: missing}
+	}
+	return out, nil
+}
@@ -0,0 +1,141 @@
+// server/api/prerequisites_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrerequisites(t *testing.T) {
+	testCases := []struct {
+		name       string
+		text       string
+		ownCode    string
+		expr       string
+		advisory   bool
+		concurrent bool
+	}{
+		{
+			name: "AndOfOrGroups",
+			text: "KOGS1003 Empirical Research Methods and KOGS1004 User Research or KOGS1005 Argumentative Design",
+			expr: "KOGS1003 and (KOGS1004 or KOGS1005)",
+		},
+		{
+			name: "SlashAlternatives",
+			text: "TJTSM51 / KYBS3070",
+			expr: "TJTSM51 or KYBS3070",
+		},
+		{
+			name: "FinnishTai",
+			text: "TIEP111 Ohjelmointi 2 tai TIEA306 Ohjelmointityö",
+			expr: "TIEP111 or TIEA306",
+		},
+		{
+			name:     "Recommended",
+			text:     "Recommended: TJTS5010 and TJTS5011 or equivalent knowledge.",
+			expr:     "TJTS5010 and TJTS5011",
+			advisory: true,
+		},
+		{
+			name:       "Concurrent",
+			text:       "TIES454 can be taken simultaneously.",
+			expr:       "TIES454",
+			concurrent: true,
+		},
+		{
+			name:    "IgnoresOwnCodeAndDuplicates",
+			text:    "TIES4530 requires TIES454, TIES454 and TIEP111",
+			ownCode: "TIES4530",
+			expr:    "TIES454 and TIEP111",
+		},
+		{
+			name: "NoCodes",
+			text: "Basic programming skills.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := parsePrerequisites(tc.text, tc.ownCode)
+			if tc.expr == "" {
+				require.Nil(t, p.Expr)
+			} else {
+				require.NotNil(t, p.Expr)
+				require.Equal(t, tc.expr, p.Expr.String())
+			}
+			require.Equal(t, tc.advisory, p.Advisory)
+			require.Equal(t, tc.concurrent, p.Concurrent)
+		})
+	}
+}
+
+func TestEvaluatePrerequisites(t *testing.T) {
+	strict := parsePrerequisites("KOGS1003 and KOGS1004 or KOGS1005", "")
+	both := parsePrerequisites("TIEP111 and TIEP112", "")
+	advisory := parsePrerequisites("Recommended: TJTS5010", "")
+
+	testCases := []struct {
+		name    string
+		prereq  coursePrerequisites
+		done    []string
+		status  string
+		missing []string
+	}{
+		{name: "NoPrerequisites", prereq: coursePrerequisites{}, status: eligibilityEligible, missing: []string{}},
+		{name: "AllMet", prereq: strict, done: []string{"KOGS1003", "KOGS1005"}, status: eligibilityEligible, missing: []string{}},
+		{name: "SomeMet", prereq: strict, done: []string{"KOGS1004"}, status: eligibilityBlocked, missing: []string{"KOGS1003"}},
+		{name: "OneOfTwoMet", prereq: both, done: []string{"TIEP111"}, status: eligibilityBlocked, missing: []string{"TIEP112"}},
+		{name: "NoneMet", prereq: strict, status: eligibilityBlocked, missing: []string{"KOGS1003", "KOGS1004 or KOGS1005"}},
+		{name: "AdvisoryOnly", prereq: advisory, status: eligibilityPartial, missing: []string{"TJTS5010"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			done := make(map[string]bool)
+			for _, code := range tc.done {
+				done[code] = true
+			}
+			status, missing := tc.prereq.evaluate(done)
+			require.Equal(t, tc.status, status)
+			require.Equal(t, tc.missing, missing)
+		})
+	}
+}
+
+func TestCheckEligibility(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	courses := []db.Course{
+		{ID: 1, Code: "TJTS5011", Prerequisites: sql.NullString{String: "TJTS5010", Valid: true}},
+		{ID: 2, Code: "TJTS5012", Prerequisites: sql.NullString{String: "TJTS5011", Valid: true}},
+		{ID: 3, Code: "TIES454"},
+	}
+
+	// Course 1 is cached and current; course 2 is stale; course 3 has no row.
+	cached, _ := json.Marshal(parsePrerequisites("TJTS5010", "TJTS5011"))
+	store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).Return([]db.CoursePrerequisite{
+		{CourseID: 1, Expression: cached, SourceText: "TJTS5010"},
+		{CourseID: 2, Expression: []byte(`{}`), SourceText: "old text"},
+	}, nil)
+	store.EXPECT().UpsertCoursePrerequisite(gomock.Any(), gomock.Any()).Times(2).Return(db.CoursePrerequisite{}, nil)
+
+	server := &Server{store: store}
+	got, err := server.checkEligibility(context.Background(), courses, []string{"tjts5010"})
+	require.NoError(t, err)
+
+	require.Equal(t, courseEligibility{Status: eligibilityEligible, Missing: []string{}}, got[1])
+	require.Equal(t, courseEligibility{Status: eligibilityBlocked, Missing: []string{"TJTS5011"}}, got[2])
+	require.Equal(t, courseEligibility{Status: eligibilityEligible, Missing: []string{}}, got[3])
+
+	require.Len(t, filterEligibleCourses(courses, got), 2)
+}
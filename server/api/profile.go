@@ -0,0 +1,118 @@
+// server/api/profile.go
+
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+// studentProfileRequest replaces the caller's profile; empty fields clear it.
+type studentProfileRequest struct {
+	Nationality     string   `json:"nationality" validate:"max=100"`
+	DegreeLevel     string   `json:"degree_level" validate:"omitempty,oneof=bachelor master doctoral"`
+	FieldOfStudy    string   `json:"field_of_study" validate:"max=200"`
+	TargetCountries []string `json:"target_countries" validate:"max=10,dive,max=100"`
+	Languages       []string `json:"languages" validate:"max=10,dive,max=50"`
+}
+
+type studentProfileResponse struct {
+	Nationality     string     `json:"nationality"`
+	DegreeLevel     string     `json:"degree_level"`
+	FieldOfStudy    string     `json:"field_of_study"`
+	TargetCountries []string   `json:"target_countries"`
+	Languages       []string   `json:"languages"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func newStudentProfileResponse(p db.StudentProfile) studentProfileResponse {
+	resp := studentProfileResponse{
+		Nationality:     p.Nationality.String,
+		DegreeLevel:     p.DegreeLevel.String,
+		FieldOfStudy:    p.FieldOfStudy.String,
+		TargetCountries: normalizeList(p.TargetCountries),
+		Languages:       normalizeList(p.Languages),
+	}
+	if !p.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &p.UpdatedAt
+	}
+	return resp
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// getStudentProfile returns the user's profile, or an empty one if none is
+// saved yet.
+func (s *Server) getStudentProfile(c *fiber.Ctx, username string) (db.StudentProfile, error) {
+	profile, err := s.store.GetStudentProfile(c.Context(), username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.StudentProfile{UserUsername: username}, nil
+	}
+	return profile, err
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// GET /api/profile
+// The caller's student profile (empty fields when not set).
+func (s *Server) getProfile(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	profile, err := s.getStudentProfile(c, payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newStudentProfileResponse(profile))
+}
+
+// PUT /api/profile
+// Replaces the caller's student profile. Degree level synonyms ("MSc",
+// "PhD", ...) are mapped to bachelor / master / doctoral.
+func (s *Server) updateProfile(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req studentProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if level, ok := degreeLevelAliases[strings.ToLower(strings.TrimSpace(req.DegreeLevel))]; ok {
+		req.DegreeLevel = level
+	}
+	if err := s.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	profile, err := s.store.UpsertStudentProfile(c.Context(), db.UpsertStudentProfileParams{
+		UserUsername:    payload.Username,
+		Nationality:     sqlStringOrNull(req.Nationality),
+		DegreeLevel:     sqlStringOrNull(req.DegreeLevel),
+		FieldOfStudy:    sqlStringOrNull(req.FieldOfStudy),
+		TargetCountries: normalizeList(req.TargetCountries),
+		Languages:       normalizeList(req.Languages),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newStudentProfileResponse(profile))
+}
@@ -0,0 +1,128 @@
+// server/api/profile_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetProfileAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetStudentProfile(gomock.Any(), gomock.Eq(username)).Times(1).Return(db.StudentProfile{}, sql.ErrNoRows)
+	server := newFiberTestServer(t, store)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profile", nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got studentProfileResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Empty(t, got.FieldOfStudy)
+	require.Equal(t, []string{}, got.TargetCountries)
+	require.Nil(t, got.UpdatedAt)
+}
+
+func TestUpdateProfileAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name       string
+		body       fiber.Map
+		buildStubs func(store *mockdb.MockStore)
+		wantStatus int
+	}{
+		{
+			name: "OK",
+			body: fiber.Map{
+				"nationality":      "Bangladeshi",
+				"degree_level":     "MSc",
+				"field_of_study":   "Computer Science",
+				"target_countries": []string{" Finland", "Germany", "finland"},
+				"languages":        []string{"English"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.UpsertStudentProfileParams{
+					UserUsername:    username,
+					Nationality:     sql.NullString{String: "Bangladeshi", Valid: true},
+					DegreeLevel:     sql.NullString{String: "master", Valid: true},
+					FieldOfStudy:    sql.NullString{String: "Computer Science", Valid: true},
+					TargetCountries: []string{"Finland", "Germany"},
+					Languages:       []string{"English"},
+				}
+				store.EXPECT().UpsertStudentProfile(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.StudentProfile{UserUsername: username, DegreeLevel: arg.DegreeLevel, UpdatedAt: time.Now()}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Cleared",
+			body: fiber.Map{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertStudentProfile(gomock.Any(), gomock.Eq(db.UpsertStudentProfileParams{
+					UserUsername:    username,
+					TargetCountries: []string{},
+					Languages:       []string{},
+				})).Times(1).Return(db.StudentProfile{UserUsername: username}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "InvalidDegreeLevel",
+			body: fiber.Map{"degree_level": "kindergarten"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertStudentProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "InternalError",
+			body: fiber.Map{"field_of_study": "Physics"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertStudentProfile(gomock.Any(), gomock.Any()).Times(1).Return(db.StudentProfile{}, sql.ErrConnDone)
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			server := newFiberTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPut, "/api/profile", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// server/api/recommender_lexical.go
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Recommendation engines accepted in RECOMMENDATION_ENGINE and recorded as
+// "engine" in the recommendation payload.
+const (
+	recoEngineLLM     = "llm"
+	recoEngineLexical = "lexical"
+)
+
+const (
+	lexicalRecoLimit = 5
+
+	// BM25 parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// Transcript terms count less than the stated preference.
+	transcriptTermWeight = 0.3
+	// Partially eligible courses rank below equally relevant eligible ones.
+	partialEligibilityPenalty = 0.8
+)
+
+// bm25Index scores documents against weighted query terms.
+type bm25Index struct {
+	docs   []map[string]float64 // term frequencies per document
+	lens   []float64
+	avgLen float64
+	df     map[string]float64
+}
+
+func newBM25Index(texts []string) *bm25Index {
+	idx := &bm25Index{df: make(map[string]float64)}
+	var total float64
+	for _, text := range texts {
+		tf := make(map[string]float64)
+		tokens := embedTokens(text)
+		for _, tok := range tokens {
+			tf[tok]++
+		}
+		for tok := range tf {
+			idx.df[tok]++
+		}
+		idx.docs = append(idx.docs, tf)
+		idx.lens = append(idx.lens, float64(len(tokens)))
+		total += float64(len(tokens))
+	}
+	if len(texts) > 0 {
+		idx.avgLen = total / float64(len(texts))
+	}
+	return idx
+}
+
+func (idx *bm25Index) idf(term string) float64 {
+	n := float64(len(idx.docs))
+	df := idx.df[term]
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// score returns the BM25 score of document i for the weighted query.
+func (idx *bm25Index) score(i int, query map[string]float64) float64 {
+	if idx.avgLen == 0 {
+		return 0
+	}
+	var s float64
+	doc := idx.docs[i]
+	for term, w := range query {
+		tf := doc[term]
+		if tf == 0 {
+			continue
+		}
+		norm := tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*idx.lens[i]/idx.avgLen))
+		s += w * idx.idf(term) * norm
+	}
+	return s
+}
+
+// lexicalQuery weights preference terms fully and transcript terms less.
+func lexicalQuery(preference, transcriptText string) map[string]float64 {
+	q := make(map[string]float64)
+	for _, tok := range embedTokens(transcriptText) {
+		q[tok] = transcriptTermWeight
+	}
+	for _, tok := range embedTokens(preference) {
+		q[tok] = 1
+	}
+	return q
+}
+
+// lexicalRecommend ranks candidates by BM25 similarity between the query
+// (preference + transcript text) and each course's name and learning
+// outcomes. Match scores are relative to the best candidate (0–100), and
+// the rationale names the matched preference terms. It needs no LLM.
+func lexicalRecommend(candidates []db.Course, eligibility map[int64]courseEligibility, preference, transcriptText string, limit int) []Recommendation {
+	texts := make([]string, len(candidates))
+	for i, c := range candidates {
+		texts[i] = c.Name + "\n" + c.Name + "\n" + c.LearningOutcomes.String
+	}
+	idx := newBM25Index(texts)
+	query := lexicalQuery(preference, transcriptText)
+
+	type scored struct {
+		i     int
+		score float64
+	}
+	ranked := make([]scored, 0, len(candidates))
+	for i, c := range candidates {
+		s := idx.score(i, query)
+		if eligibility[c.ID].Status == eligibilityPartial {
+			s *= partialEligibilityPenalty
+		}
+		ranked = append(ranked, scored{i: i, score: s})
+	}
+	sort.SliceStable(ranked, func(a, b int) bool { return ranked[a].score > ranked[b].score })
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	maxScore := 0.0
+	if len(ranked) > 0 {
+		maxScore = ranked[0].score
+	}
+	prefTerms := embedTokens(preference)
+
+	recs := make([]Recommendation, 0, len(ranked))
+	for _, r := range ranked {
+		c := candidates[r.i]
+		match := 0.0
+		if maxScore > 0 {
+			match = math.Round(r.score / maxScore * 100)
+		}
+		check := eligibility[c.ID]
+		missing := check.Missing
+		if missing == nil {
+			missing = []string{}
+		}
+		recs = append(recs, Recommendation{
+			Type:                 "course",
+			Title:                c.Name,
+			Code:                 c.Code,
+			Description:          lexicalRationale(idx.docs[r.i], prefTerms, r.score),
+			Match:                match,
+			Link:                 c.CourseLink.String,
+			CourseID:             c.ID,
+			Eligibility:          check.Status,
+			MissingPrerequisites: missing,
+		})
+	}
+	return recs
+}
+
+// lexicalRationale is the template explanation for a lexical match.
+func lexicalRationale(doc map[string]float64, prefTerms []string, score float64) string {
+	var matched []string
+	seen := make(map[string]bool)
+	for _, t := range prefTerms {
+		if doc[t] > 0 && !seen[t] {
+			seen[t] = true
+			matched = append(matched, t)
+		}
+		if len(matched) == 3 {
+			break
+		}
+	}
+	switch {
+	case len(matched) > 0:
+		return fmt.Sprintf("Matches your interest in %s.", strings.Join(matched, ", "))
+	case score > 0:
+		return "Related to topics in your completed studies."
+	default:
+		return "Available course you can enrol in; no direct keyword match with your preference."
+	}
+}
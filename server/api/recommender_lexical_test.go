@@ -0,0 +1,128 @@
+// server/api/recommender_lexical_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLexicalRecommend(t *testing.T) {
+	candidates := []db.Course{
+		testEmbedCourse(1, "Finnish Literature", "Poetry and novels of the 1800s"),
+		testEmbedCourse(2, "Machine Learning", "Neural networks, supervised learning and model evaluation"),
+		testEmbedCourse(3, "Deep Learning", "Training deep neural networks on images"),
+		testEmbedCourse(4, "Accounting Basics", "Bookkeeping and financial statements"),
+	}
+	eligibility := map[int64]courseEligibility{
+		1: {Status: eligibilityEligible, Missing: []string{}},
+		2: {Status: eligibilityEligible, Missing: []string{}},
+		3: {Status: eligibilityPartial, Missing: []string{"TEST2"}},
+		4: {Status: eligibilityEligible, Missing: []string{}},
+	}
+
+	recs := lexicalRecommend(candidates, eligibility, "machine learning with neural networks", "", 3)
+	require.Len(t, recs, 3)
+
+	require.Equal(t, int64(2), recs[0].CourseID)
+	require.Equal(t, 100.0, recs[0].Match)
+	require.Equal(t, "Matches your interest in machine, learning, neural.", recs[0].Description)
+	require.Equal(t, eligibilityEligible, recs[0].Eligibility)
+
+	require.Equal(t, int64(3), recs[1].CourseID)
+	require.Less(t, recs[1].Match, 100.0)
+	require.Equal(t, []string{"TEST2"}, recs[1].MissingPrerequisites)
+
+	require.Zero(t, recs[2].Match)
+
+	// Deterministic
+	require.Equal(t, recs, lexicalRecommend(candidates, eligibility, "machine learning with neural networks", "", 3))
+}
+
+func TestCreateRecommendationLexicalFallback(t *testing.T) {
+	username := util.RandomOwner()
+	transcript := db.Transcript{
+		ID:            5,
+		UserUsername:  username,
+		TextExtracted: sql.NullString{String: "TIEP111 Programming 2 5 op 4 12.05.2023", Valid: true},
+	}
+	catalog := []db.Course{
+		testEmbedCourse(1, "Machine Learning", "Neural networks and supervised learning"),
+		testEmbedCourse(2, "Accounting Basics", "Bookkeeping and financial statements"),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return(transcript, nil)
+	store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).
+		Return([]db.TranscriptCourse{testTranscriptCourse("TIEP111", 5, "4", "Spring 2023", courseStatusCompleted)}, nil)
+	store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).Return(catalog, nil)
+	store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).Return([]db.CoursePrerequisite{}, nil)
+	store.EXPECT().UpsertCoursePrerequisite(gomock.Any(), gomock.Any()).Times(2).Return(db.CoursePrerequisite{}, nil)
+	store.EXPECT().ListUserScholarships(gomock.Any(), gomock.Eq(db.ListUserScholarshipsParams{UserUsername: username, Limit: scholarshipContextLimit})).
+		Times(1).Return([]db.ListUserScholarshipsRow{}, nil)
+	store.EXPECT().CreateRecommendation(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ any, arg db.CreateRecommendationParams) (db.Recommendation, error) {
+			var saved struct {
+				Engine string `json:"engine"`
+			}
+			require.NoError(t, json.Unmarshal(arg.Payload, &saved))
+			require.Equal(t, recoEngineLexical, saved.Engine)
+			return db.Recommendation{ID: 1, UserUsername: username, Payload: arg.Payload}, nil
+		})
+	store.EXPECT().CreateLLMUsage(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ any, arg db.CreateLLMUsageParams) (db.LlmUsage, error) {
+			require.Equal(t, llmFeatureRecommendation, arg.Feature)
+			require.Equal(t, llmUsageStatusError, arg.Status)
+			require.Zero(t, arg.TotalTokens)
+			return db.LlmUsage{}, nil
+		})
+
+	// No OPENAI_API_KEY in the test config, so the LLM call fails.
+	server := newFiberTestServer(t, store)
+	events, unsubscribe := server.events.subscribe(username)
+	defer unsubscribe()
+
+	body, _ := json.Marshal(createRecommendationRequest{TranscriptID: transcript.ID, Preference: "neural networks"})
+	req := httptest.NewRequest(http.MethodPost, "/api/recommendations", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got struct {
+		Engine  string           `json:"engine"`
+		Courses []Recommendation `json:"courses"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Equal(t, recoEngineLexical, got.Engine)
+	require.NotEmpty(t, got.Courses)
+	require.Equal(t, "Machine Learning", got.Courses[0].Title)
+
+	var published []string
+	for len(events) > 0 {
+		published = append(published, (<-events).Type)
+	}
+	require.Equal(t, []string{
+		eventRecoHistoryExtracted,
+		eventRecoCandidates,
+		eventRecoRetrieved,
+		eventRecoRanked,
+		eventRecoSaved,
+	}, published)
+}
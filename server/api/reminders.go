@@ -0,0 +1,128 @@
+// server/api/reminders.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// reminderBatchSize caps the reminders sent per run; the rest go out on the
+// next tick.
+const reminderBatchSize = 500
+
+// startReminders checks for due scholarship deadlines every
+// REMINDER_INTERVAL until ctx is done. A zero interval disables reminders.
+func (s *Server) startReminders(ctx context.Context) {
+	interval := s.config.ReminderInterval
+	if interval <= 0 {
+		log.Println("[REMINDERS] REMINDER_INTERVAL=0: deadline reminders disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if n, err := s.sendDeadlineReminders(ctx); err != nil {
+				log.Printf("[REMINDERS] %v", err)
+			} else if n > 0 {
+				log.Printf("[REMINDERS] Sent %d deadline reminders", n)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sendDeadlineReminders notifies users about matched scholarships whose
+// deadline falls within their reminder window, over the channels they chose.
+// Each (user, scholarship, deadline) is claimed in scholarship_reminders
+// before sending, so several instances never send the same reminder twice.
+// The claim is released when every channel fails, to retry on the next run.
+// It returns the number of reminders delivered.
+func (s *Server) sendDeadlineReminders(ctx context.Context) (int, error) {
+	due, err := s.store.ListDueScholarshipReminders(ctx, reminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("list due reminders: %w", err)
+	}
+
+	sent := 0
+	for _, row := range due {
+		if !row.Deadline.Valid {
+			continue
+		}
+		claim := db.RecordScholarshipReminderParams{
+			UserUsername:  row.UserUsername,
+			ScholarshipID: row.ScholarshipID,
+			Deadline:      row.Deadline.Time,
+		}
+		claimed, err := s.store.RecordScholarshipReminder(ctx, claim)
+		if err != nil {
+			return sent, fmt.Errorf("record reminder: %w", err)
+		}
+		if claimed == 0 {
+			continue // another instance got it
+		}
+
+		if s.notify(ctx, row.Channels, deadlineNotification(row, time.Now())) {
+			sent++
+			continue
+		}
+		if err := s.store.DeleteScholarshipReminder(ctx, db.DeleteScholarshipReminderParams(claim)); err != nil {
+			log.Printf("[REMINDERS] Release reminder for %s failed: %v", row.UserUsername, err)
+		}
+	}
+	return sent, nil
+}
+
+// notify sends n over each of the channels that is configured on this
+// server. It reports whether at least one channel delivered it.
+func (s *Server) notify(ctx context.Context, channels []string, n notification) bool {
+	delivered := false
+	for _, channel := range channels {
+		notifier, ok := s.notifiers[channel]
+		if !ok {
+			continue
+		}
+		if err := notifier.Notify(ctx, n); err != nil {
+			log.Printf("[NOTIFY] %s to %s failed: %v", channel, n.Username, err)
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+func deadlineNotification(row db.ListDueScholarshipRemindersRow, now time.Time) notification {
+	deadline := row.Deadline.Time
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(deadline.Sub(today).Hours() / 24)
+
+	when := fmt.Sprintf("in %d days", days)
+	switch days {
+	case 0:
+		when = "today"
+	case 1:
+		when = "tomorrow"
+	}
+
+	return notification{
+		Username: row.UserUsername,
+		Email:    row.Email,
+		FullName: row.FullName,
+		Kind:     notificationScholarshipDeadline,
+		Title:    fmt.Sprintf("Deadline %s: %s", when, row.Title),
+		Body: fmt.Sprintf("The application deadline for %s is %s (%s). Your match score: %.0f%%.",
+			row.Title, deadline.Format("2006-01-02"), when, row.MatchScore),
+		Link: row.CanonicalUrl,
+		Time: now,
+	}
+}
@@ -0,0 +1,230 @@
+// server/api/scholarship_catalog.go
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// scholarshipContextLimit is how many of a user's matches go into summary
+// PDFs, chat context and recommendation payloads.
+const scholarshipContextLimit = 10
+
+// Query parameters that only track the visit and never identify the page.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "msclkid": true, "mc_cid": true, "mc_eid": true, "ref": true,
+}
+
+var scholarshipDeadlineLayouts = []string{
+	"2006-01-02",
+	"2006/01/02",
+	"2.1.2006",
+	"2 January 2006",
+	"January 2, 2006",
+	"January 2 2006",
+	"2 Jan 2006",
+	"Jan 2, 2006",
+	time.RFC3339,
+}
+
+var currencySymbols = map[string]string{"€": "EUR", "$": "USD", "£": "GBP", "¥": "JPY"}
+
+// Degree level synonyms mapped to bachelor / master / doctoral.
+var degreeLevelAliases = map[string]string{
+	"bachelor": "bachelor", "bachelors": "bachelor", "bachelor's": "bachelor", "bsc": "bachelor", "ba": "bachelor", "undergraduate": "bachelor",
+	"master": "master", "masters": "master", "master's": "master", "msc": "master", "ma": "master", "graduate": "master", "postgraduate": "master",
+	"doctoral": "doctoral", "doctorate": "doctoral", "phd": "doctoral", "ph.d.": "doctoral", "doctor": "doctoral",
+}
+
+// canonicalScholarshipURL normalizes a scholarship link and returns it with
+// its dedup key: the lower-cased host (without "www.") plus path and the
+// remaining query, ignoring scheme, fragment, tracking parameters and a
+// trailing slash. ok is false for anything that is not an http(s) URL.
+func canonicalScholarshipURL(raw string) (canonical, key string, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "", false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	query := u.Query()
+	for name := range query {
+		if trackingParams[strings.ToLower(name)] || strings.HasPrefix(strings.ToLower(name), "utm_") {
+			query.Del(name)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	key = strings.TrimPrefix(u.Host, "www.") + u.Path
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return u.String(), strings.ToLower(key), true
+}
+
+// scholarshipURLHash is the scholarship_catalog.url_hash of a dedup key.
+func scholarshipURLHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func parseScholarshipDeadline(s string) sql.NullTime {
+	s = strings.TrimSpace(s)
+	for _, layout := range scholarshipDeadlineLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return sql.NullTime{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+		}
+	}
+	return sql.NullTime{}
+}
+
+// normalizeCurrency returns an ISO 4217 style code ("EUR") or "".
+func normalizeCurrency(s string) string {
+	s = strings.TrimSpace(s)
+	if code, ok := currencySymbols[s]; ok {
+		return code
+	}
+	s = strings.ToUpper(s)
+	if len(s) != 3 {
+		return ""
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return ""
+		}
+	}
+	return s
+}
+
+// normalizeList trims the values and drops empty and case-insensitive
+// duplicates, keeping the first spelling.
+func normalizeList(values []string) []string {
+	out := []string{}
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[strings.ToLower(v)] {
+			continue
+		}
+		seen[strings.ToLower(v)] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func normalizeDegreeLevels(values []string) []string {
+	mapped := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if level, ok := degreeLevelAliases[v]; ok {
+			v = level
+		}
+		mapped = append(mapped, v)
+	}
+	return normalizeList(mapped)
+}
+
+// newCatalogScholarship turns a ranked result into a scholarship_catalog
+// row. ok is false when the link cannot be canonicalized.
+func newCatalogScholarship(r ScholarshipReco) (db.UpsertCatalogScholarshipParams, bool) {
+	canonical, key, ok := canonicalScholarshipURL(r.Link)
+	if !ok {
+		return db.UpsertCatalogScholarshipParams{}, false
+	}
+	currency := normalizeCurrency(r.Currency)
+	return db.UpsertCatalogScholarshipParams{
+		UrlHash:               scholarshipURLHash(key),
+		CanonicalUrl:          canonical,
+		Title:                 r.Title,
+		Description:           sqlStringOrNull(r.Description),
+		Provider:              sqlStringOrNull(r.Provider),
+		Amount:                sql.NullFloat64{Float64: r.Amount, Valid: r.Amount > 0},
+		Currency:              sqlStringOrNull(currency),
+		Deadline:              parseScholarshipDeadline(r.Deadline),
+		EligibleNationalities: normalizeList(r.EligibleNationalities),
+		DegreeLevels:          normalizeDegreeLevels(r.DegreeLevels),
+		FieldsOfStudy:         normalizeList(r.FieldsOfStudy),
+	}, true
+}
+
+// dedupeScholarships keeps the first result per canonical URL and drops
+// results without a usable link. Sort by match first to keep the best one.
+func dedupeScholarships(recs []ScholarshipReco) []ScholarshipReco {
+	out := make([]ScholarshipReco, 0, len(recs))
+	seen := make(map[string]bool, len(recs))
+	for _, r := range recs {
+		canonical, key, ok := canonicalScholarshipURL(r.Link)
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		r.Link = canonical
+		out = append(out, r)
+	}
+	return out
+}
+
+// saveScholarshipMatches upserts each result into the shared catalog and
+// records the user's score for it. Failed rows are logged and skipped; the
+// returned results carry their catalog IDs.
+func (s *Server) saveScholarshipMatches(ctx context.Context, username string, recs []ScholarshipReco) []ScholarshipReco {
+	saved := make([]ScholarshipReco, 0, len(recs))
+	for _, r := range recs {
+		arg, ok := newCatalogScholarship(r)
+		if !ok {
+			continue
+		}
+		entry, err := s.store.UpsertCatalogScholarship(ctx, arg)
+		if err != nil {
+			log.Printf("[DB] Save scholarship failed for %s: %v", r.Title, err)
+			continue
+		}
+		if _, err := s.store.UpsertUserScholarshipMatch(ctx, db.UpsertUserScholarshipMatchParams{
+			UserUsername:  username,
+			ScholarshipID: entry.ID,
+			MatchScore:    r.Match,
+			SearchQuery:   sqlStringOrNull(r.Query),
+		}); err != nil {
+			log.Printf("[DB] Save scholarship match failed for %s: %v", r.Title, err)
+			continue
+		}
+		r.ID = entry.ID
+		saved = append(saved, r)
+	}
+	return saved
+}
+
+// scholarshipDetails renders the known provider, amount and deadline of a
+// match on one line, e.g. "Provider: EDUFI | Amount: 5000 EUR | Deadline: 2026-03-01".
+func scholarshipDetails(sch db.ListUserScholarshipsRow) string {
+	var parts []string
+	if sch.Provider.Valid {
+		parts = append(parts, "Provider: "+sch.Provider.String)
+	}
+	if sch.Amount.Valid {
+		amount := strconv.FormatFloat(sch.Amount.Float64, 'f', -1, 64)
+		if sch.Currency.Valid {
+			amount += " " + sch.Currency.String
+		}
+		parts = append(parts, "Amount: "+amount)
+	}
+	if sch.Deadline.Valid {
+		parts = append(parts, "Deadline: "+sch.Deadline.Time.Format("2006-01-02"))
+	}
+	return strings.Join(parts, " | ")
+}
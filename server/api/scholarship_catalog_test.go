@@ -0,0 +1,131 @@
+// server/api/scholarship_catalog_test.go
+
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalScholarshipURL(t *testing.T) {
+	testCases := []struct {
+		name      string
+		raw       string
+		canonical string
+		key       string
+		ok        bool
+	}{
+		{
+			name:      "Plain",
+			raw:       "https://www.Example.org/grants/",
+			canonical: "https://www.example.org/grants",
+			key:       "example.org/grants",
+			ok:        true,
+		},
+		{
+			name:      "TrackingAndFragment",
+			raw:       "http://example.org/grants?utm_source=brave&id=7&gclid=x#apply",
+			canonical: "http://example.org/grants?id=7",
+			key:       "example.org/grants?id=7",
+			ok:        true,
+		},
+		{
+			name: "NotHTTP",
+			raw:  "mailto:grants@example.org",
+		},
+		{
+			name: "Relative",
+			raw:  "/grants",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			canonical, key, ok := canonicalScholarshipURL(tc.raw)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.canonical, canonical)
+			require.Equal(t, tc.key, key)
+		})
+	}
+}
+
+func TestDedupeScholarships(t *testing.T) {
+	recs := dedupeScholarships([]ScholarshipReco{
+		{Title: "EDUFI Fellowship", Match: 90, Link: "https://www.edufi.fi/fellowship/"},
+		{Title: "EDUFI fellowship (copy)", Match: 80, Link: "http://edufi.fi/fellowship?utm_medium=web"},
+		{Title: "No link", Match: 70, Link: "not a url"},
+		{Title: "Other", Match: 60, Link: "https://example.org/other"},
+	})
+	require.Len(t, recs, 2)
+	require.Equal(t, "EDUFI Fellowship", recs[0].Title)
+	require.Equal(t, "https://www.edufi.fi/fellowship", recs[0].Link)
+	require.Equal(t, "Other", recs[1].Title)
+}
+
+func TestNewCatalogScholarship(t *testing.T) {
+	arg, ok := newCatalogScholarship(ScholarshipReco{
+		Title:                 "EDUFI Fellowship",
+		Link:                  "https://edufi.fi/fellowship",
+		Provider:              " EDUFI ",
+		Amount:                1500,
+		Currency:              "€",
+		Deadline:              "15 January 2027",
+		EligibleNationalities: []string{"Any", "any", ""},
+		DegreeLevels:          []string{"PhD", "MSc", "Master's"},
+		FieldsOfStudy:         []string{"Computer Science"},
+	})
+	require.True(t, ok)
+	require.Equal(t, scholarshipURLHash("edufi.fi/fellowship"), arg.UrlHash)
+	require.Equal(t, "EDUFI", arg.Provider.String)
+	require.True(t, arg.Amount.Valid)
+	require.Equal(t, "EUR", arg.Currency.String)
+	require.True(t, arg.Deadline.Valid)
+	require.Equal(t, time.Date(2027, time.January, 15, 0, 0, 0, 0, time.UTC), arg.Deadline.Time)
+	require.Equal(t, []string{"Any"}, arg.EligibleNationalities)
+	require.Equal(t, []string{"doctoral", "master"}, arg.DegreeLevels)
+	require.Empty(t, arg.Description.String)
+
+	arg, ok = newCatalogScholarship(ScholarshipReco{Title: "Unknown", Link: "https://example.org", Currency: "euros", Deadline: "soon"})
+	require.True(t, ok)
+	require.False(t, arg.Currency.Valid)
+	require.False(t, arg.Deadline.Valid)
+	require.False(t, arg.Amount.Valid)
+	require.Equal(t, []string{}, arg.DegreeLevels)
+}
+
+func TestSaveScholarshipMatches(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().UpsertCatalogScholarship(gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ any, arg db.UpsertCatalogScholarshipParams) (db.ScholarshipCatalog, error) {
+			if arg.Title == "Broken" {
+				return db.ScholarshipCatalog{}, errors.New("db down")
+			}
+			return db.ScholarshipCatalog{ID: 7, UrlHash: arg.UrlHash, Title: arg.Title}, nil
+		})
+	store.EXPECT().UpsertUserScholarshipMatch(gomock.Any(), gomock.Eq(db.UpsertUserScholarshipMatchParams{
+		UserUsername:  username,
+		ScholarshipID: 7,
+		MatchScore:    88,
+	})).Times(1).Return(db.UserScholarshipMatch{}, nil)
+
+	server := newFiberTestServer(t, store)
+	saved := server.saveScholarshipMatches(context.Background(), username, []ScholarshipReco{
+		{Title: "EDUFI Fellowship", Match: 88, Link: "https://edufi.fi/fellowship"},
+		{Title: "Broken", Match: 50, Link: "https://example.org/broken"},
+	})
+	require.Len(t, saved, 1)
+	require.Equal(t, int64(7), saved[0].ID)
+}
@@ -0,0 +1,236 @@
+// server/api/scholarship_extract.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxEligibilityLines = 5
+	pageExcerptChars    = 1200
+)
+
+// scholarshipFacts are the details found on a scholarship page by
+// extractScholarshipFacts.
+type scholarshipFacts struct {
+	Deadline     string // YYYY-MM-DD
+	Amount       float64
+	Currency     string
+	DegreeLevels []string
+	Eligibility  []string // lines about who may apply
+}
+
+// scholarshipPage is a fetched page with the facts found on it.
+type scholarshipPage struct {
+	Page  fetchedPage
+	Facts scholarshipFacts
+}
+
+var (
+	deadlineLineRe    = regexp.MustCompile(`(?i)deadline|apply by|applications? (?:close|are due|due|must be received)|closing date|due date|submit\b.*\bby\b`)
+	amountLineRe      = regexp.MustCompile(`(?i)amount|award|stipend|worth|value|grant of|scholarship of|funding of|covers|per (?:year|month|annum|semester)`)
+	eligibilityLineRe = regexp.MustCompile(`(?i)eligib|open to|who can apply|applicants? (?:must|should)|requirements?`)
+	degreeWordRe      = regexp.MustCompile(`(?i)\b(bachelor'?s?|undergraduate|master'?s?|msc|postgraduate|ph\.?d\.?|doctoral|doctorate)\b`)
+
+	months       = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	pageDateRe   = regexp.MustCompile(`(?i)\b(?:\d{4}-\d{2}-\d{2}|\d{4}/\d{2}/\d{2}|\d{1,2}\.\d{1,2}\.\d{4}|\d{1,2}(?:st|nd|rd|th)?\s+` + months + `,?\s+\d{4}|` + months + `\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4})\b`)
+	ordinalRe    = regexp.MustCompile(`(?i)(\d)(?:st|nd|rd|th)\b`)
+	monthWordRe  = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?`)
+	pageAmountRe = regexp.MustCompile(`(?i)(€|\$|£|\b(?:EUR|USD|GBP|CAD|AUD|CHF|SEK|NOK|DKK)\b)\s?(\d{1,3}(?:[,. ]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d{1,2})?)|(\d{1,3}(?:[,. ]\d{3})+(?:[.,]\d{1,2})?|\d+(?:[.,]\d{1,2})?)\s?(€|\b(?:EUR|USD|GBP|CAD|AUD|CHF|SEK|NOK|DKK|euros?|dollars?|pounds?)\b)`)
+)
+
+var amountCurrencyWords = map[string]string{"euro": "EUR", "euros": "EUR", "dollar": "USD", "dollars": "USD", "pound": "GBP", "pounds": "GBP"}
+
+// extractScholarshipFacts finds the deadline, award amount and degree levels
+// in a page's text with plain pattern matching:
+//   - deadline: the earliest date from now on in a line that mentions a
+//     deadline (or the line after it), else the latest past one
+//   - amount: the first sum with a currency in a line about the award
+//   - degree levels and eligibility: lines about who may apply
+func extractScholarshipFacts(text string, now time.Time) scholarshipFacts {
+	var facts scholarshipFacts
+	lines := strings.Split(text, "\n")
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var dates []time.Time
+	var degreeWords []string
+	for i, line := range lines {
+		if deadlineLineRe.MatchString(line) {
+			candidates := pageDateRe.FindAllString(line, -1)
+			if len(candidates) == 0 && i+1 < len(lines) {
+				candidates = pageDateRe.FindAllString(lines[i+1], -1)
+			}
+			for _, c := range candidates {
+				if d := parsePageDate(c); !d.IsZero() {
+					dates = append(dates, d)
+				}
+			}
+		}
+		if facts.Amount == 0 && amountLineRe.MatchString(line) {
+			facts.Amount, facts.Currency = parsePageAmount(line)
+		}
+		if eligibilityLineRe.MatchString(line) {
+			if len(facts.Eligibility) < maxEligibilityLines {
+				facts.Eligibility = append(facts.Eligibility, truncate(line, 200))
+			}
+			degreeWords = append(degreeWords, degreeWordRe.FindAllString(line, -1)...)
+		}
+	}
+
+	if len(dates) > 0 {
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+		deadline := dates[len(dates)-1]
+		for _, d := range dates {
+			if !d.Before(today) {
+				deadline = d
+				break
+			}
+		}
+		facts.Deadline = deadline.Format("2006-01-02")
+	}
+
+	for i, w := range degreeWords {
+		degreeWords[i] = strings.NewReplacer(".", "", "'", "").Replace(strings.ToLower(w))
+	}
+	facts.DegreeLevels = normalizeDegreeLevels(degreeWords)
+	if len(facts.DegreeLevels) == 0 {
+		facts.DegreeLevels = nil
+	}
+	return facts
+}
+
+// parsePageDate parses a date matched by pageDateRe, or returns zero.
+func parsePageDate(s string) time.Time {
+	s = ordinalRe.ReplaceAllString(s, "$1")
+	s = monthWordRe.ReplaceAllStringFunc(s, func(m string) string {
+		m = strings.ToLower(strings.TrimSuffix(m, "."))
+		return strings.ToUpper(m[:1]) + m[1:3]
+	})
+	s = strings.Join(strings.Fields(strings.ReplaceAll(s, ",", " ")), " ")
+	for _, layout := range []string{"2006-01-02", "2006/01/02", "2.1.2006", "2 Jan 2006", "Jan 2 2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parsePageAmount returns the first sum with a currency in line.
+func parsePageAmount(line string) (float64, string) {
+	m := pageAmountRe.FindStringSubmatch(line)
+	if m == nil {
+		return 0, ""
+	}
+	symbol, number := m[1], m[2]
+	if symbol == "" {
+		number, symbol = m[3], m[4]
+	}
+	currency := normalizeCurrency(symbol)
+	if code, ok := amountCurrencyWords[strings.ToLower(symbol)]; ok {
+		currency = code
+	}
+	return parseAmountNumber(number), currency
+}
+
+// parseAmountNumber reads "10,000", "10.000", "10 000" and "2,500.50":
+// a separator followed by one or two final digits is the decimal point,
+// any other is a thousands separator.
+func parseAmountNumber(s string) float64 {
+	decimals := ""
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 <= 2 {
+		s, decimals = s[:i], s[i+1:]
+	}
+	s = strings.NewReplacer(",", "", ".", "", " ", "").Replace(s)
+	if decimals != "" {
+		s += "." + decimals
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// fetchScholarshipPages downloads the pages of the search results and
+// extracts their facts, by canonical URL key. Returns nil when page fetching
+// is disabled.
+func (s *Server) fetchScholarshipPages(ctx context.Context, results []WebResult) map[string]scholarshipPage {
+	if s.pages == nil || len(results) == 0 {
+		return nil
+	}
+	limit := s.config.ScholarshipFetchMaxPages
+	if limit <= 0 || limit > len(results) {
+		limit = len(results)
+	}
+	urls := make([]string, 0, limit)
+	for _, r := range results[:limit] {
+		urls = append(urls, r.URL)
+	}
+
+	now := time.Now()
+	fetched := s.pages.FetchAll(ctx, urls)
+	pages := make(map[string]scholarshipPage, len(fetched))
+	for key, page := range fetched {
+		pages[key] = scholarshipPage{Page: page, Facts: extractScholarshipFacts(page.Text, now)}
+	}
+	return pages
+}
+
+// pagePromptText describes a fetched page for the ranking prompt: the facts
+// found by pattern matching, then the opening and eligibility lines.
+func pagePromptText(p scholarshipPage) string {
+	var sb strings.Builder
+	var found []string
+	if p.Facts.Deadline != "" {
+		found = append(found, "deadline "+p.Facts.Deadline)
+	}
+	if p.Facts.Amount > 0 {
+		found = append(found, strings.TrimSpace(fmt.Sprintf("amount %s %s", strconv.FormatFloat(p.Facts.Amount, 'f', -1, 64), p.Facts.Currency)))
+	}
+	if len(p.Facts.DegreeLevels) > 0 {
+		found = append(found, "degree levels "+strings.Join(p.Facts.DegreeLevels, ", "))
+	}
+	if len(found) > 0 {
+		sb.WriteString("  Found on page: " + strings.Join(found, "; ") + "\n")
+	}
+
+	excerpt := p.Page.Text
+	if len(p.Facts.Eligibility) > 0 {
+		excerpt = truncate(excerpt, pageExcerptChars/2) + "\n" + strings.Join(p.Facts.Eligibility, "\n")
+	}
+	sb.WriteString("  Page: " + strings.ReplaceAll(truncate(excerpt, pageExcerptChars), "\n", " / ") + "\n")
+	return sb.String()
+}
+
+// applyScholarshipFacts merges the facts found on each result's page into
+// the model's answer. A deadline found on the page replaces the model's
+// (which tends to guess the year); the other facts only fill gaps.
+func applyScholarshipFacts(recs []ScholarshipReco, pages map[string]scholarshipPage) {
+	for i := range recs {
+		_, key, ok := canonicalScholarshipURL(recs[i].Link)
+		if !ok {
+			continue
+		}
+		p, ok := pages[key]
+		if !ok {
+			continue
+		}
+		r := &recs[i]
+		if p.Facts.Deadline != "" {
+			r.Deadline = p.Facts.Deadline
+		}
+		if r.Amount <= 0 && p.Facts.Amount > 0 {
+			r.Amount, r.Currency = p.Facts.Amount, p.Facts.Currency
+		}
+		if len(r.DegreeLevels) == 0 {
+			r.DegreeLevels = p.Facts.DegreeLevels
+		}
+	}
+}
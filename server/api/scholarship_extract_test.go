@@ -0,0 +1,84 @@
+// server/api/scholarship_extract_test.go
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractScholarshipFacts(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		text string
+		want scholarshipFacts
+	}{
+		{
+			name: "Full",
+			text: "Example Grant\nThe award is worth 10.000 EUR per year.\n" +
+				"Eligibility: open to Bachelor's and MSc students.\n" +
+				"Deadlines: 1 March 2026 (EU), March 15th, 2027 (non-EU)",
+			want: scholarshipFacts{
+				Deadline:     "2027-03-15",
+				Amount:       10000,
+				Currency:     "EUR",
+				DegreeLevels: []string{"bachelor", "master"},
+				Eligibility:  []string{"Eligibility: open to Bachelor's and MSc students."},
+			},
+		},
+		{
+			name: "DateOnNextLine",
+			text: "Application deadline\n31.12.2026\nStipend: $1,250.50 per month",
+			want: scholarshipFacts{Deadline: "2026-12-31", Amount: 1250.50, Currency: "USD"},
+		},
+		{
+			name: "OnlyPastDeadline",
+			text: "Applications closed. The deadline was 2025-01-10.\nA grant of 5 000 euros.",
+			want: scholarshipFacts{Deadline: "2025-01-10", Amount: 5000, Currency: "EUR"},
+		},
+		{
+			name: "NothingFound",
+			text: "Founded in 1990, we have 2,000 students.\nPublished 1 January 2026.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, extractScholarshipFacts(tc.text, now))
+		})
+	}
+}
+
+func TestScholarshipPageFixture(t *testing.T) {
+	f := newTestPageFetcher(t, t.TempDir())
+	storePageFixture(t, f, "https://example.edu/finland-scholarship", "finland_scholarship.html")
+	server := &Server{pages: f}
+
+	pages := server.fetchScholarshipPages(context.Background(), []WebResult{{Title: "Finland", URL: "https://example.edu/finland-scholarship"}})
+	require.Len(t, pages, 1)
+	page := pages["example.edu/finland-scholarship"]
+	facts := page.Facts
+	require.Equal(t, 10000.0, facts.Amount)
+	require.Equal(t, "EUR", facts.Currency)
+	require.Equal(t, []string{"master", "doctoral"}, facts.DegreeLevels)
+	require.NotEmpty(t, facts.Deadline)
+
+	prompt := pagePromptText(page)
+	require.Contains(t, prompt, "Found on page: deadline "+facts.Deadline+"; amount 10000 EUR; degree levels master, doctoral")
+	require.Contains(t, prompt, "Page: Finland Scholarship / The Finland Scholarship supports")
+
+	recs := []ScholarshipReco{
+		{Title: "Finland", Link: "https://example.edu/finland-scholarship", Deadline: "2030-01-01", Amount: 12000, Currency: "EUR"},
+		{Title: "Other", Link: "https://other.example.org"},
+	}
+	applyScholarshipFacts(recs, pages)
+	require.Equal(t, facts.Deadline, recs[0].Deadline)
+	require.Equal(t, 12000.0, recs[0].Amount)
+	require.Equal(t, []string{"master", "doctoral"}, recs[0].DegreeLevels)
+	require.Empty(t, recs[1].Deadline)
+}
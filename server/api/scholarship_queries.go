@@ -0,0 +1,199 @@
+// server/api/scholarship_queries.go
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// defaultScholarshipQuery is searched when neither the profile nor the
+// transcript name a field of study.
+const defaultScholarshipQuery = "scholarships for international students studying computer science OR artificial intelligence"
+
+const (
+	maxScholarshipQueries = 5
+	transcriptTopicCount  = 3
+	maxCountryQueries     = 2
+)
+
+var degreeQueryTerms = map[string]string{
+	"bachelor": "bachelor's",
+	"master":   "master's",
+	"doctoral": "PhD",
+}
+
+// Words in course names that describe the kind of course, not its subject.
+var genericCourseWords = map[string]bool{
+	"introduction": true, "intro": true, "basic": true, "basics": true, "advanced": true, "fundamentals": true,
+	"principles": true, "seminar": true, "project": true, "projects": true, "thesis": true, "studies": true,
+	"study": true, "special": true, "topics": true, "selected": true, "practical": true, "workshop": true,
+	"course": true, "part": true, "ii": true, "iii": true, "iv": true, "bachelor": true, "master": true,
+	"master's": true, "bachelor's": true, "orientation": true, "internship": true, "training": true,
+}
+
+// transcriptTopics returns the n subject words that carry the most credits
+// across the passed and in-progress courses of a transcript.
+func transcriptTopics(courses []db.TranscriptCourse, n int) []string {
+	weights := make(map[string]float64)
+	for _, course := range courses {
+		if course.Status == courseStatusFailed {
+			continue
+		}
+		weight := course.Credits.Float64
+		if weight <= 0 {
+			weight = 1
+		}
+		seen := make(map[string]bool)
+		words := strings.FieldsFunc(strings.ToLower(course.CourseName), func(r rune) bool {
+			return !unicode.IsLetter(r) && r != '\''
+		})
+		for _, w := range words {
+			w = strings.Trim(w, "'")
+			if len([]rune(w)) < 3 || stopWords[w] || genericCourseWords[w] || seen[w] {
+				continue
+			}
+			seen[w] = true
+			weights[w] += weight
+		}
+	}
+
+	topics := make([]string, 0, len(weights))
+	for w := range weights {
+		topics = append(topics, w)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		if weights[topics[i]] != weights[topics[j]] {
+			return weights[topics[i]] > weights[topics[j]]
+		}
+		return topics[i] < topics[j]
+	})
+	if len(topics) > n {
+		topics = topics[:n]
+	}
+	return topics
+}
+
+// scholarshipSearchQueries builds up to maxScholarshipQueries web searches
+// from the student's profile and transcript topics: one for the field (and
+// nationality), one per target country, one for the transcript's topics,
+// one for a non-English language and a fully funded one. The field falls
+// back to the topics and, without either, to defaultScholarshipQuery.
+func scholarshipSearchQueries(profile db.StudentProfile, topics []string) []string {
+	field := strings.TrimSpace(profile.FieldOfStudy.String)
+	topicQuery := field != ""
+	if field == "" {
+		field = strings.Join(topics[:min(2, len(topics))], " ")
+	}
+	if field == "" {
+		return []string{defaultScholarshipQuery}
+	}
+	degree := degreeQueryTerms[profile.DegreeLevel.String]
+	nationality := strings.TrimSpace(profile.Nationality.String)
+
+	var queries []string
+	seen := make(map[string]bool)
+	add := func(parts ...string) {
+		q := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+		if q == "" || seen[strings.ToLower(q)] {
+			return
+		}
+		seen[strings.ToLower(q)] = true
+		queries = append(queries, q)
+	}
+
+	if nationality != "" {
+		add(degree, "scholarships in", field, "for", nationality, "students")
+	} else {
+		add(degree, "scholarships for international students in", field)
+	}
+	for i, country := range normalizeList(profile.TargetCountries) {
+		if i == maxCountryQueries {
+			break
+		}
+		add(field, degree, "scholarships in", country)
+	}
+	if topicQuery && len(topics) > 0 {
+		add(strings.Join(topics, " "), "research scholarship", degree)
+	}
+	for _, lang := range normalizeList(profile.Languages) {
+		if !strings.EqualFold(lang, "english") {
+			add(field, "scholarships for", lang, "speakers")
+			break
+		}
+	}
+	add("fully funded", degree, field, "scholarships")
+
+	if len(queries) > maxScholarshipQueries {
+		queries = queries[:maxScholarshipQueries]
+	}
+	return queries
+}
+
+// searchScholarshipQueries runs every query and merges the results by rank,
+// one result per URL. Each result records the query that surfaced it. It
+// fails only when every query does.
+func (s *Server) searchScholarshipQueries(ctx context.Context, queries []string) ([]WebResult, error) {
+	lists := make([][]WebResult, 0, len(queries))
+	var errs []error
+	for _, q := range queries {
+		results, err := s.webSearch(ctx, q)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", q, err))
+			continue
+		}
+		for i := range results {
+			results[i].Query = q
+		}
+		lists = append(lists, results)
+	}
+	if len(lists) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return mergeWebResults(lists, 0), nil
+}
+
+// attachScholarshipQueries sets each result's Query to the search query of
+// the web result with the same canonical URL.
+func attachScholarshipQueries(recs []ScholarshipReco, web []WebResult) {
+	byKey := make(map[string]string, len(web))
+	for _, w := range web {
+		if _, key, ok := canonicalScholarshipURL(w.URL); ok && w.Query != "" {
+			if _, dup := byKey[key]; !dup {
+				byKey[key] = w.Query
+			}
+		}
+	}
+	for i := range recs {
+		if _, key, ok := canonicalScholarshipURL(recs[i].Link); ok {
+			recs[i].Query = byKey[key]
+		}
+	}
+}
+
+// profilePromptText describes the profile for the ranking prompt, or "".
+func profilePromptText(p db.StudentProfile) string {
+	var lines []string
+	if p.Nationality.Valid {
+		lines = append(lines, "Nationality: "+p.Nationality.String)
+	}
+	if p.DegreeLevel.Valid {
+		lines = append(lines, "Degree level: "+p.DegreeLevel.String)
+	}
+	if p.FieldOfStudy.Valid {
+		lines = append(lines, "Field of study: "+p.FieldOfStudy.String)
+	}
+	if len(p.TargetCountries) > 0 {
+		lines = append(lines, "Target countries: "+strings.Join(p.TargetCountries, ", "))
+	}
+	if len(p.Languages) > 0 {
+		lines = append(lines, "Languages: "+strings.Join(p.Languages, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,93 @@
+// server/api/scholarship_queries_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscriptTopics(t *testing.T) {
+	courses := []db.TranscriptCourse{
+		{CourseName: "Introduction to Machine Learning", Credits: sql.NullFloat64{Float64: 5, Valid: true}, Status: courseStatusCompleted},
+		{CourseName: "Advanced Machine Learning II", Credits: sql.NullFloat64{Float64: 5, Valid: true}, Status: courseStatusInProgress},
+		{CourseName: "Computer Vision", Credits: sql.NullFloat64{Float64: 3, Valid: true}, Status: courseStatusCompleted},
+		{CourseName: "Quantum Chemistry", Credits: sql.NullFloat64{Float64: 10, Valid: true}, Status: courseStatusFailed},
+		{CourseName: "Thesis Seminar", Status: courseStatusCompleted},
+	}
+
+	require.Equal(t, []string{"learning", "machine", "computer"}, transcriptTopics(courses, 3))
+	require.Empty(t, transcriptTopics(nil, 3))
+}
+
+func TestScholarshipSearchQueries(t *testing.T) {
+	full := db.StudentProfile{
+		Nationality:     sql.NullString{String: "Bangladeshi", Valid: true},
+		DegreeLevel:     sql.NullString{String: "master", Valid: true},
+		FieldOfStudy:    sql.NullString{String: "Computer Science", Valid: true},
+		TargetCountries: []string{"Finland", "Germany", "Sweden"},
+		Languages:       []string{"English", "Bengali"},
+	}
+
+	testCases := []struct {
+		name    string
+		profile db.StudentProfile
+		topics  []string
+		want    []string
+	}{
+		{
+			name:    "FullProfile",
+			profile: full,
+			topics:  []string{"learning", "machine"},
+			want: []string{
+				"master's scholarships in Computer Science for Bangladeshi students",
+				"Computer Science master's scholarships in Finland",
+				"Computer Science master's scholarships in Germany",
+				"learning machine research scholarship master's",
+				"Computer Science scholarships for Bengali speakers",
+			},
+		},
+		{
+			name:   "TopicsOnly",
+			topics: []string{"learning", "machine", "vision"},
+			want: []string{
+				"scholarships for international students in learning machine",
+				"fully funded learning machine scholarships",
+			},
+		},
+		{
+			name: "Empty",
+			want: []string{defaultScholarshipQuery},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, scholarshipSearchQueries(tc.profile, tc.topics))
+		})
+	}
+}
+
+func TestSearchScholarshipQueries(t *testing.T) {
+	server := &Server{search: stubSearchProvider{name: "stub", results: []WebResult{
+		{Title: "A", URL: "https://a.example.org"},
+	}}}
+
+	results, err := server.searchScholarshipQueries(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	require.Equal(t, []WebResult{{Title: "A", URL: "https://a.example.org", Query: "first"}}, results)
+
+	recs := []ScholarshipReco{{Title: "A", Link: "https://www.a.example.org/"}, {Title: "B", Link: "https://b.example.org"}}
+	attachScholarshipQueries(recs, results)
+	require.Equal(t, "first", recs[0].Query)
+	require.Empty(t, recs[1].Query)
+
+	server.search = stubSearchProvider{name: "stub", err: errors.New("down")}
+	_, err = server.searchScholarshipQueries(context.Background(), []string{"first"})
+	require.Error(t, err)
+}
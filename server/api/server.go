@@ -4,6 +4,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/etag"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
@@ -32,6 +34,14 @@ type Server struct {
 	tokenMaker   token.Maker
 	app          *fiber.App
 	validate     *validator.Validate
+	llm          LLMProvider
+	embedder     Embedder
+	search       SearchProvider
+	pages        *pageFetcher
+	jobs         *jobQueue
+	events       *eventHub
+	replies      *replyStreamHub
+	notifiers    map[string]Notifier
 
 	uploadsDir   string
 	summariesDir string
@@ -44,6 +54,21 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	llm, err := newLLMProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create LLM provider: %w", err)
+	}
+
+	embedder, err := newEmbedder(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create embedder: %w", err)
+	}
+
+	search, err := newSearchProvider(config, store)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create web search provider: %w", err)
+	}
+
 	app := fiber.New(fiber.Config{})
 
 	// --- Global Middleware ---
@@ -56,13 +81,14 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		ExposeHeaders:    "Content-Length, Content-Type",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, Last-Event-ID",
+		ExposeHeaders:    "Content-Length, Content-Type, X-Stream-ID",
 		AllowCredentials: true,
 	}))
 
 	validate := validator.New()
 	validate.RegisterValidation("currency", validCurrency)
+	validate.RegisterValidation("coursecode", validCourseCode)
 
 	server := &Server{
 		config:       config,
@@ -70,10 +96,19 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		tokenMaker:   tokenMaker,
 		app:          app,
 		validate:     validate,
+		llm:          newMeteredLLM(llm, store, config),
+		embedder:     newMeteredEmbedder(embedder, store, config),
+		search:       search,
+		pages:        newPageFetcher(config),
+		events:       newEventHub(),
+		replies:      newReplyStreamHub(),
 		uploadsDir:   "./uploads",
 		summariesDir: "./summaries",
 	}
 
+	server.jobs = newJobQueue(store, config.JobWorkers, server.jobHandlers(), server.events)
+	server.notifiers = newNotifiers(config, store, server.events)
+
 	// Ensure upload and summary directories exist
 	_ = os.MkdirAll(server.uploadsDir, 0o755)
 	_ = os.MkdirAll(server.summariesDir, 0o755)
@@ -94,23 +129,37 @@ func (server *Server) setUpRoutes() {
 	api := app.Group("/api")
 	api.Post("/users", server.createUser)
 	api.Post("/users/login", server.loginUser)
+	api.Post("/users/logout", server.logoutUser)
+	api.Post("/tokens/renew_access", server.renewAccessToken)
 
 	// --- Web Search ---
 	api.Get("/websearch", server.handleLocalWebSearch)
 
+	// --- Course Catalog (ETag: If-None-Match answers 304) ---
+	courses := api.Group("/courses", etag.New())
+	courses.Get("/", server.listCatalogCourses)
+	courses.Get("/:code", server.getCatalogCourse)
+	courses.Get("/:code/related", server.listRelatedCourses)
+
 	// --- PROTECTED ROUTES (Require Authorization) ---
 	auth := api.Group("/", authMiddlewareFiber(server.tokenMaker))
 
+	// --- Sessions (refresh tokens) ---
+	auth.Get("/sessions", server.listSessions)
+	auth.Delete("/sessions/:id", server.revokeSession)
+
 	// ====== EDU-SPHERE CORE FEATURES ======
 
 	// --- Transcript Management ---
-	auth.Post("/transcripts/upload", server.uploadTranscript)
+	auth.Post("/transcripts/upload", server.requireLLMBudget, server.uploadTranscript)
 	auth.Get("/transcripts", server.listTranscripts)
 	auth.Get("/transcripts/:id", server.getTranscript)
+	auth.Get("/transcripts/:id/courses", server.listTranscriptCourses)
+	auth.Get("/transcripts/:id/analytics", server.getTranscriptAnalytics)
 
 	// --- Recommendations ---
 	// Create (Smart Filtered Recommendation)
-	auth.Post("/recommendations", server.createRecommendation)
+	auth.Post("/recommendations", server.requireLLMBudget, server.createRecommendation)
 
 	// List & Get (History)
 	auth.Get("/recommendations", server.listRecommendations)
@@ -121,12 +170,30 @@ func (server *Server) setUpRoutes() {
 
 	// REMOVED: auth.Post("/recommendations/generate", ...) because we merged it into createRecommendation
 
+	// --- Study Plans (multi-term, prerequisite-ordered) ---
+	auth.Post("/plans", server.createStudyPlan)
+	auth.Get("/plans", server.listStudyPlans)
+	auth.Get("/plans/:id", server.getStudyPlan)
+	auth.Put("/plans/:id", server.updateStudyPlan)
+	auth.Delete("/plans/:id", server.deleteStudyPlan)
+
+	// --- Chat Conversations (saved history, optionally pinned to a recommendation) ---
+	auth.Post("/conversations", server.createConversation)
+	auth.Get("/conversations", server.listConversations)
+	auth.Delete("/conversations/:id", server.deleteConversation)
+	auth.Get("/conversations/:id/messages", server.listConversationMessages)
+	auth.Post("/conversations/:id/messages", server.requireLLMBudget, server.sendConversationMessage)
+
+	// --- Student Profile (drives scholarship search queries) ---
+	auth.Get("/profile", server.getProfile)
+	auth.Put("/profile", server.updateProfile)
+
 	// --- Scholarships (AI + Web Search) ---
-	auth.Post("/scholarships/generate", server.generateScholarships)
+	auth.Post("/scholarships/generate", server.requireLLMBudget, server.generateScholarships)
 
 	// --- Summaries ---
 	// Step 1: Generate summary text (AI only, not saved)
-	auth.Post("/summaries/generate", server.generateSummary)
+	auth.Post("/summaries/generate", server.requireLLMBudget, server.generateSummary)
 
 	// Step 2: Create & save PDF (includes summary + recommendations + scholarships)
 	auth.Post("/summaries", server.createSummaryPDF)
@@ -136,27 +203,76 @@ func (server *Server) setUpRoutes() {
 	auth.Get("/summaries/:id/download", server.downloadSummaryPDF) // Download a specific PDF
 	auth.Delete("/summaries/:id", server.deleteSummary)         // Delete summary + file
 
+	// --- Background Jobs (upload, recommendation, scholarship and summary generation) ---
+	auth.Get("/jobs/:id", server.getJob)
+	auth.Get("/jobs/:id/events", server.streamJobEvents)
+
+	// --- Progress Events (SSE, all of the user's pipelines) ---
+	auth.Get("/events", server.streamEvents)
+
+	// --- Notifications (in-app list and reminder preferences) ---
+	auth.Get("/notifications", server.listNotifications)
+	auth.Post("/notifications/read_all", server.markAllNotificationsRead)
+	auth.Post("/notifications/:id/read", server.markNotificationRead)
+	auth.Get("/notifications/preferences", server.getNotificationPreferences)
+	auth.Put("/notifications/preferences", server.updateNotificationPreferences)
+
+	// --- Advisors (read-only access to assigned students) ---
+	advisor := auth.Group("/advisor", authorize(util.AdvisorRole, util.AdminRole))
+	advisor.Get("/students", server.listAdvisorStudents)
+	advisor.Get("/students/:username/transcripts", server.listTranscripts)
+	advisor.Get("/students/:username/recommendations", server.listRecommendations)
+	advisor.Get("/students/:username/summaries", server.listSummaries)
+	advisor.Get("/students/:username/summaries/:id/download", server.downloadSummaryPDF)
+
+	// --- Admin (roles and advisor assignments) ---
+	admin := auth.Group("/admin", authorize(util.AdminRole))
+	admin.Put("/users/:username/role", server.updateUserRole)
+	admin.Post("/advisors/:username/students", server.assignAdvisorStudent)
+	admin.Delete("/advisors/:username/students/:student", server.unassignAdvisorStudent)
+
+	// --- Admin course catalog (upsert by code, archive instead of delete) ---
+	admin.Post("/courses", server.upsertCourse)
+	admin.Post("/courses/import", server.importCourses)
+	admin.Put("/courses/:id", server.updateCourse)
+	admin.Delete("/courses/:id", server.archiveCourse)
+
+	// --- Admin audit of the tools run by the chat assistant ---
+	admin.Get("/chat/tool-calls", server.listChatToolCalls)
+
+	// --- Admin report of LLM tokens, cost and latency ---
+	admin.Get("/usage", server.llmUsageReport)
+
 	// --- Simple AI Chat (for debugging/testing) ---
-	auth.Post("/chat/stream", server.chatStream)
+	auth.Post("/chat/stream", server.requireLLMBudget, server.chatStream)
+	auth.Delete("/chat/streams/:id", server.cancelReplyStream)
 }
 
-// Start launches the Fiber HTTP server and warms up the OpenAI model.
+// Start launches the Fiber HTTP server, the background job workers and the
+// deadline reminders, and warms up the LLM provider.
 func (s *Server) Start(address string) error {
-	// Warm up OpenAI model to reduce first-request latency
+	if s.config.JobWorkers > 0 {
+		s.jobs.start(context.Background())
+	} else {
+		log.Println("[INIT] JOB_WORKERS=0: uploads and AI generation run inside the request")
+	}
+	s.startReminders(context.Background())
+
+	// Warm up the chat model to reduce first-request latency
 	go func() {
-		log.Println("[INIT] Warming up OpenAI chat model...")
-		if s.config.OpenAIAPIKey == "" {
+		log.Printf("[INIT] Warming up %s chat model...", s.llm.Name())
+		if s.llm.Name() == llmProviderOpenAI && s.config.OpenAIAPIKey == "" {
 			log.Println("[INIT] Skipping OpenAI warmup: OPENAI_API_KEY not set")
 			return
 		}
-		_, err := callOpenAIChat(context.Background(), s.config.OpenAIAPIKey, s.config.OpenAIModel, []aiMessage{
+		_, err := s.llm.Chat(withLLMCaller(context.Background(), "", llmFeatureWarmup), []aiMessage{
 			{Role: "system", Content: "You are EduSphere, an academic assistant."},
 			{Role: "user", Content: "Say hello briefly."},
-		}, false)
+		})
 		if err != nil {
-			log.Printf("[INIT] OpenAI warmup failed: %v", err)
+			log.Printf("[INIT] %s warmup failed: %v", s.llm.Name(), err)
 		} else {
-			log.Println("[INIT] OpenAI model reachable ✅")
+			log.Printf("[INIT] %s model reachable ✅", s.llm.Name())
 		}
 	}()
 
@@ -167,4 +283,14 @@ func (s *Server) Start(address string) error {
 // errorResponse provides a consistent JSON error payload.
 func errorResponse(err error) fiber.Map {
 	return fiber.Map{"error": err.Error()}
-}
\ No newline at end of file
+}
+
+// statusErrorResponse writes err with the status carried by a *fiber.Error,
+// or 500 for any other error.
+func statusErrorResponse(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return c.Status(fe.Code).JSON(errorResponse(fe))
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+}
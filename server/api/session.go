@@ -0,0 +1,217 @@
+// server/api/session.go
+
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// ---------------------------
+// Request and Response Structs
+// ---------------------------
+
+// refreshTokenRequest carries the refresh token returned by login
+// @Description Refresh token request payload
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// renewAccessTokenResponse represents the JSON response for a renewed access token
+// @Description Renew access token response payload
+type renewAccessTokenResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+// sessionResponse is a login session without its refresh token
+// @Description Session response payload
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		UserAgent: session.UserAgent,
+		ClientIP:  session.ClientIp,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+}
+
+// ---------------------------
+// Helpers
+// ---------------------------
+
+// verifyRefreshToken checks the refresh token and returns its live session.
+// All failures are 401 except database errors.
+func (server *Server) verifyRefreshToken(c *fiber.Ctx, refreshToken string) (db.Session, error) {
+	payload, err := server.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+	if payload.Type != token.TokenTypeRefresh {
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "not a refresh token")
+	}
+
+	session, err := server.store.GetSession(c.Context(), payload.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "session not found")
+		}
+		return db.Session{}, err
+	}
+
+	switch {
+	case session.IsBlocked:
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "blocked session")
+	case session.Username != payload.Username:
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "incorrect session user")
+	case session.RefreshToken != refreshToken:
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "mismatched session token")
+	case time.Now().After(session.ExpiresAt):
+		return db.Session{}, fiber.NewError(fiber.StatusUnauthorized, "expired session")
+	}
+	return session, nil
+}
+
+// ---------------------------
+// Handlers
+// ---------------------------
+
+// RenewAccessToken godoc
+// @Summary      Renew the access token
+// @Description  Issues a new access token for a valid, unblocked refresh token session
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      refreshTokenRequest  true  "Refresh token"
+// @Success      200   {object}  renewAccessTokenResponse
+// @Router       /tokens/renew_access [post]
+func (server *Server) renewAccessToken(c *fiber.Ctx) error {
+	var req refreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := server.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	session, err := server.verifyRefreshToken(c, req.RefreshToken)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	// Re-read the role so role changes apply from the next renewal
+	user, err := server.store.GetUser(c.Context(), session.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, token.TokenTypeAccess, server.config.AccessTokenDuration)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.JSON(renewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	})
+}
+
+// LogoutUser godoc
+// @Summary      Log out
+// @Description  Blocks the session of the given refresh token so it can no longer renew access tokens
+// @Tags         Users
+// @Accept       json
+// @Param        body  body  refreshTokenRequest  true  "Refresh token"
+// @Success      204
+// @Router       /users/logout [post]
+func (server *Server) logoutUser(c *fiber.Ctx) error {
+	var req refreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if err := server.validate.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	session, err := server.verifyRefreshToken(c, req.RefreshToken)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	_, err = server.store.BlockSession(c.Context(), db.BlockSessionParams{ID: session.ID, Username: session.Username})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListSessions godoc
+// @Summary      List active sessions
+// @Description  Lists the caller's unexpired, unblocked login sessions
+// @Tags         Users
+// @Produce      json
+// @Security     ApiKeyAuth
+// @Success      200  {array}  sessionResponse
+// @Router       /sessions [get]
+func (server *Server) listSessions(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	sessions, err := server.store.ListActiveSessions(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, newSessionResponse(session))
+	}
+	return c.JSON(resp)
+}
+
+// RevokeSession godoc
+// @Summary      Revoke a session
+// @Description  Blocks one of the caller's sessions (e.g. a lost device)
+// @Tags         Users
+// @Security     ApiKeyAuth
+// @Param        id   path  string  true  "Session ID"
+// @Success      204
+// @Router       /sessions/{id} [delete]
+func (server *Server) revokeSession(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("invalid session id")))
+	}
+
+	// Scoped to the caller, so other users' sessions read as not found
+	_, err = server.store.BlockSession(c.Context(), db.BlockSessionParams{ID: id, Username: payload.Username})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("session not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
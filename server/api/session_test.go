@@ -0,0 +1,279 @@
+// server/api/session_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newRefreshSession issues a refresh token and the session row that matches it.
+func newRefreshSession(t *testing.T, maker token.Maker, username string) (string, db.Session) {
+	refreshToken, payload, err := maker.CreateToken(username, util.StudentRole, token.TokenTypeRefresh, time.Hour)
+	require.NoError(t, err)
+	return refreshToken, db.Session{
+		ID:           payload.ID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    payload.ExpiredAt,
+	}
+}
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		modifySession func(session *db.Session)
+		getErr        error
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OK",
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got renewAccessTokenResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.NotEmpty(t, got.AccessToken)
+			},
+		},
+		{
+			name:          "Blocked",
+			modifySession: func(session *db.Session) { session.IsBlocked = true },
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:          "MismatchedToken",
+			modifySession: func(session *db.Session) { session.RefreshToken = "other" },
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:          "Expired",
+			modifySession: func(session *db.Session) { session.ExpiresAt = time.Now().Add(-time.Minute) },
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:   "NotFound",
+			getErr: sql.ErrNoRows,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:   "InternalError",
+			getErr: sql.ErrConnDone,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newFiberTestServer(t, store)
+			server.config.AccessTokenDuration = time.Minute
+
+			refreshToken, session := newRefreshSession(t, server.tokenMaker, username)
+			if tc.modifySession != nil {
+				tc.modifySession(&session)
+			}
+			store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, tc.getErr)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).AnyTimes().Return(db.User{Username: username, Role: util.StudentRole}, nil)
+
+			data, _ := json.Marshal(fiber.Map{"refresh_token": refreshToken})
+			req := httptest.NewRequest(http.MethodPost, "/api/tokens/renew_access", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestRenewAccessTokenRejectsAccessToken(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newFiberTestServer(t, store)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(username, util.StudentRole, token.TokenTypeAccess, time.Hour)
+	require.NoError(t, err)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	data, _ := json.Marshal(fiber.Map{"refresh_token": accessToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens/renew_access", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRenewAccessTokenCurrentRole(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newFiberTestServer(t, store)
+	server.config.AccessTokenDuration = time.Minute
+
+	// The refresh token was issued to a student who has since become an advisor
+	refreshToken, session := newRefreshSession(t, server.tokenMaker, username)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).Times(1).Return(db.User{Username: username, Role: util.AdvisorRole}, nil)
+
+	data, _ := json.Marshal(fiber.Map{"refresh_token": refreshToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens/renew_access", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got renewAccessTokenResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	payload, err := server.tokenMaker.VerifyToken(got.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, util.AdvisorRole, payload.Role)
+}
+
+func TestRenewAccessTokenInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+	server := newFiberTestServer(t, store)
+
+	data, _ := json.Marshal(fiber.Map{"refresh_token": "not-a-token"})
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens/renew_access", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRefreshTokenRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+	server := newFiberTestServer(t, store)
+
+	for _, path := range []string{"/api/tokens/renew_access", "/api/users/logout"} {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := server.app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+	}
+}
+
+func TestLogoutUserAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newFiberTestServer(t, store)
+
+	refreshToken, session := newRefreshSession(t, server.tokenMaker, username)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	store.EXPECT().BlockSession(gomock.Any(), gomock.Eq(db.BlockSessionParams{ID: session.ID, Username: username})).
+		Times(1).Return(session, nil)
+
+	data, _ := json.Marshal(fiber.Map{"refresh_token": refreshToken})
+	req := httptest.NewRequest(http.MethodPost, "/api/users/logout", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
+
+func TestSessionsAPI(t *testing.T) {
+	username := util.RandomOwner()
+	sessionID := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListActiveSessions(gomock.Any(), gomock.Eq(username)).Times(1).Return([]db.Session{
+		{ID: sessionID, Username: username, RefreshToken: "secret", UserAgent: "Firefox", ClientIp: "10.0.0.1"},
+	}, nil)
+	store.EXPECT().BlockSession(gomock.Any(), gomock.Eq(db.BlockSessionParams{ID: sessionID, Username: username})).
+		Times(1).Return(db.Session{}, nil)
+	store.EXPECT().BlockSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, sql.ErrNoRows)
+
+	server := newFiberTestServer(t, store)
+
+	// List never exposes refresh tokens
+	req := httptest.NewRequest(http.MethodGet, "/api/sessions", nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var raw []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&raw))
+	require.Len(t, raw, 1)
+	require.Equal(t, sessionID.String(), raw[0]["id"])
+	require.Equal(t, "Firefox", raw[0]["user_agent"])
+	require.NotContains(t, raw[0], "refresh_token")
+
+	// Revoke own session
+	req = httptest.NewRequest(http.MethodDelete, "/api/sessions/"+sessionID.String(), nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Unknown (or someone else's) session
+	req = httptest.NewRequest(http.MethodDelete, "/api/sessions/"+uuid.NewString(), nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Bad ID
+	req = httptest.NewRequest(http.MethodDelete, "/api/sessions/123", nil)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
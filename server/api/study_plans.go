@@ -0,0 +1,628 @@
+// server/api/study_plans.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+const (
+	// The catalog does not store credits; JYU courses are mostly 5 ECTS.
+	defaultCourseCredits = 5.0
+	defaultTermCreditCap = 30.0
+	maxPlanTerms         = 12
+)
+
+// Request payload for POST /api/plans and PUT /api/plans/:id.
+// Exactly one of TargetCredits or GoalCourses must be given.
+type studyPlanRequest struct {
+	Title             string   `json:"title"`
+	TranscriptID      int64    `json:"transcript_id"`     // completed courses are skipped
+	RecommendationID  int64    `json:"recommendation_id"` // its courses are planned first
+	TargetCredits     float64  `json:"target_credits"`
+	GoalCourses       []string `json:"goal_courses"`
+	MaxCreditsPerTerm float64  `json:"max_credits_per_term"` // default 30
+	Language          string   `json:"language"`             // e.g. "English"
+	StartTerm         string   `json:"start_term"`           // e.g. "Autumn 2025"; default next term
+}
+
+// studyPlanTarget is the normalized request, stored in study_plans.target.
+type studyPlanTarget struct {
+	TargetCredits     float64  `json:"target_credits,omitempty"`
+	GoalCourses       []string `json:"goal_courses,omitempty"`
+	MaxCreditsPerTerm float64  `json:"max_credits_per_term"`
+	Language          string   `json:"language,omitempty"`
+	StartTerm         string   `json:"start_term"`
+	RecommendationID  int64    `json:"recommendation_id,omitempty"`
+}
+
+// studyPlan is the generated plan, stored in study_plans.plan.
+type studyPlan struct {
+	Terms        []plannedTerm       `json:"terms"`
+	TotalCredits float64             `json:"total_credits"`
+	Unscheduled  []unscheduledCourse `json:"unscheduled,omitempty"`
+	Warnings     []string            `json:"warnings,omitempty"`
+}
+
+type plannedTerm struct {
+	Term    string          `json:"term"`
+	Credits float64         `json:"credits"`
+	Courses []plannedCourse `json:"courses"`
+}
+
+type plannedCourse struct {
+	CourseID      int64   `json:"course_id"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Credits       float64 `json:"credits"`
+	Language      string  `json:"language,omitempty"`
+	Prerequisites string  `json:"prerequisites,omitempty"` // parsed expression, e.g. "A and (B or C)"
+	Goal          bool    `json:"goal,omitempty"`
+}
+
+type unscheduledCourse struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// target validates the request and fills in defaults.
+func (req studyPlanRequest) target(now time.Time) (studyPlanTarget, error) {
+	t := studyPlanTarget{
+		TargetCredits:     req.TargetCredits,
+		MaxCreditsPerTerm: req.MaxCreditsPerTerm,
+		Language:          strings.TrimSpace(req.Language),
+		RecommendationID:  req.RecommendationID,
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range req.GoalCourses {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !seen[code] {
+			seen[code] = true
+			t.GoalCourses = append(t.GoalCourses, code)
+		}
+	}
+
+	switch {
+	case t.TargetCredits < 0:
+		return t, fmt.Errorf("target_credits must be positive")
+	case t.TargetCredits == 0 && len(t.GoalCourses) == 0:
+		return t, fmt.Errorf("either target_credits or goal_courses is required")
+	case t.TargetCredits > 0 && len(t.GoalCourses) > 0:
+		return t, fmt.Errorf("provide either target_credits or goal_courses, not both")
+	}
+
+	if t.MaxCreditsPerTerm == 0 {
+		t.MaxCreditsPerTerm = defaultTermCreditCap
+	}
+	if t.MaxCreditsPerTerm < defaultCourseCredits {
+		return t, fmt.Errorf("max_credits_per_term must be at least %.0f", defaultCourseCredits)
+	}
+	if t.TargetCredits > t.MaxCreditsPerTerm*maxPlanTerms {
+		return t, fmt.Errorf("target_credits does not fit in %d terms of %.0f credits", maxPlanTerms, t.MaxCreditsPerTerm)
+	}
+
+	if strings.TrimSpace(req.StartTerm) == "" {
+		t.StartTerm = nextTerm(termForDate(now))
+	} else {
+		m := termRe.FindStringSubmatch(req.StartTerm)
+		if m == nil {
+			return t, fmt.Errorf("start_term must look like \"Autumn 2025\"")
+		}
+		t.StartTerm = normalizeTerm(m[1], m[2])
+	}
+	return t, nil
+}
+
+// nextTerm returns the following regular term: Spring → Autumn of the same
+// year, Autumn → Spring of the next. Summer terms are not planned.
+func nextTerm(term string) string {
+	fields := strings.Fields(term)
+	if len(fields) != 2 {
+		return term
+	}
+	year, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return term
+	}
+	if fields[0] == "Autumn" {
+		return fmt.Sprintf("Spring %d", year+1)
+	}
+	return fmt.Sprintf("Autumn %d", year)
+}
+
+// languageMatches reports whether a catalog language list such as
+// "English, Finnish" includes want. An empty want matches everything.
+func languageMatches(courseLang, want string) bool {
+	if want == "" {
+		return true
+	}
+	for _, l := range strings.Split(courseLang, ",") {
+		if strings.EqualFold(strings.TrimSpace(l), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildStudyPlan selects courses for the target and sequences them over
+// terms. Only hard prerequisites (not advisory or concurrent ones) order
+// the plan: a course is placed in a term after all of its required groups
+// are met by completed or earlier-planned courses.
+//
+// Goal mode pulls in the missing prerequisites of every goal course (for OR
+// groups the first catalog alternative in the requested language wins).
+// Credit mode fills the target with courses in the requested language,
+// starting with the preferred course IDs and then in catalog order.
+//
+// Only active courses are planned; the full catalog (archived courses
+// included) is only used to look up prerequisite codes.
+func buildStudyPlan(target studyPlanTarget, active, catalog []db.Course, graph map[int64]coursePrerequisites, completed []string, preferred []int64) (studyPlan, error) {
+	plan := studyPlan{Terms: []plannedTerm{}}
+
+	byCode := make(map[string]db.Course, len(catalog))
+	for _, c := range catalog {
+		byCode[strings.ToUpper(strings.TrimSpace(c.Code))] = c
+	}
+	offered := make(map[int64]bool, len(active))
+	for _, c := range active {
+		offered[c.ID] = true
+	}
+	done := make(map[string]bool, len(completed))
+	for _, code := range completed {
+		done[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	hardExpr := func(c db.Course) *prereqExpr {
+		p := graph[c.ID]
+		if p.Expr == nil || p.Advisory || p.Concurrent {
+			return nil
+		}
+		return p.Expr
+	}
+
+	// known = completed ∪ selected
+	known := make(map[string]bool, len(done))
+	for code := range done {
+		known[code] = true
+	}
+	var selected []db.Course
+	goals := make(map[string]bool)
+
+	if len(target.GoalCourses) > 0 {
+		var pull func(c db.Course)
+		pull = func(c db.Course) {
+			code := strings.ToUpper(strings.TrimSpace(c.Code))
+			if known[code] {
+				return
+			}
+			known[code] = true
+
+			if e := hardExpr(c); e != nil {
+				groups := []prereqExpr{*e}
+				if e.Op == prereqOpAnd {
+					groups = e.Args
+				}
+				for _, g := range groups {
+					if g.satisfied(known) {
+						continue
+					}
+					alts := []prereqExpr{g}
+					if g.Op == prereqOpOr {
+						alts = g.Args
+					}
+					var pick *db.Course
+					retired := false
+					for _, alt := range alts {
+						if pc, ok := byCode[alt.Code]; ok {
+							if !offered[pc.ID] {
+								retired = true
+								continue
+							}
+							if pick == nil || (!languageMatches(pick.Language.String, target.Language) && languageMatches(pc.Language.String, target.Language)) {
+								pick = &pc
+							}
+						}
+					}
+					if pick == nil && retired {
+						plan.Warnings = append(plan.Warnings, fmt.Sprintf("prerequisite %s of %s is no longer offered", g.String(), code))
+						continue
+					}
+					if pick == nil {
+						plan.Warnings = append(plan.Warnings, fmt.Sprintf("prerequisite %s of %s is not in the catalog", g.String(), code))
+						continue
+					}
+					pull(*pick)
+				}
+			}
+			selected = append(selected, c)
+		}
+
+		for _, code := range target.GoalCourses {
+			c, ok := byCode[code]
+			if !ok {
+				return plan, fmt.Errorf("unknown goal course %s", code)
+			}
+			if !offered[c.ID] {
+				return plan, fmt.Errorf("goal course %s is no longer offered", code)
+			}
+			if done[code] {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is already completed", code))
+				continue
+			}
+			goals[code] = true
+			pull(c)
+		}
+		for _, c := range selected {
+			if !languageMatches(c.Language.String, target.Language) {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s is not taught in %s", c.Code, target.Language))
+			}
+		}
+	} else {
+		rank := make(map[int64]int, len(preferred))
+		for i, id := range preferred {
+			rank[id] = i + 1
+		}
+		var candidates, rest []db.Course
+		for _, c := range active {
+			code := strings.ToUpper(strings.TrimSpace(c.Code))
+			if done[code] || !languageMatches(c.Language.String, target.Language) {
+				continue
+			}
+			if rank[c.ID] > 0 {
+				candidates = append(candidates, c)
+			} else {
+				rest = append(rest, c)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool { return rank[candidates[i].ID] < rank[candidates[j].ID] })
+		candidates = append(candidates, rest...)
+
+		planned := 0.0
+		for progress := true; progress && planned < target.TargetCredits; {
+			progress = false
+			for _, c := range candidates {
+				code := strings.ToUpper(strings.TrimSpace(c.Code))
+				if known[code] || planned >= target.TargetCredits {
+					continue
+				}
+				if e := hardExpr(c); e != nil && !e.satisfied(known) {
+					continue
+				}
+				known[code] = true
+				selected = append(selected, c)
+				planned += defaultCourseCredits
+				progress = true
+			}
+		}
+		if planned < target.TargetCredits {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("only %.0f of %.0f credits could be planned", planned, target.TargetCredits))
+		}
+	}
+
+	// Sequence: each term takes every course whose prerequisites are met by
+	// earlier terms, in selection order, up to the credit cap.
+	passed := make(map[string]bool, len(done))
+	for code := range done {
+		passed[code] = true
+	}
+	remaining := selected
+	term := target.StartTerm
+	for len(remaining) > 0 && len(plan.Terms) < maxPlanTerms {
+		pt := plannedTerm{Term: term, Courses: []plannedCourse{}}
+		var next []db.Course
+		for _, c := range remaining {
+			e := hardExpr(c)
+			if (e != nil && !e.satisfied(passed)) || pt.Credits+defaultCourseCredits > target.MaxCreditsPerTerm {
+				next = append(next, c)
+				continue
+			}
+			code := strings.ToUpper(strings.TrimSpace(c.Code))
+			item := plannedCourse{
+				CourseID: c.ID,
+				Code:     code,
+				Name:     c.Name,
+				Credits:  defaultCourseCredits,
+				Language: strings.TrimSpace(c.Language.String),
+				Goal:     goals[code],
+			}
+			if p := graph[c.ID]; p.Expr != nil {
+				item.Prerequisites = p.Expr.String()
+			}
+			pt.Courses = append(pt.Courses, item)
+			pt.Credits += item.Credits
+		}
+		if len(pt.Courses) == 0 {
+			break
+		}
+		for _, item := range pt.Courses {
+			passed[item.Code] = true
+		}
+		plan.Terms = append(plan.Terms, pt)
+		plan.TotalCredits += pt.Credits
+		remaining = next
+		term = nextTerm(term)
+	}
+
+	for _, c := range remaining {
+		reason := fmt.Sprintf("does not fit in %d terms", maxPlanTerms)
+		if e := hardExpr(c); e != nil && !e.satisfied(passed) {
+			reason = "missing prerequisites: " + strings.Join(e.missing(passed), ", ")
+		}
+		plan.Unscheduled = append(plan.Unscheduled, unscheduledCourse{Code: c.Code, Reason: reason})
+	}
+	return plan, nil
+}
+
+// defaultTitle names a plan after its target.
+func (t studyPlanTarget) defaultTitle() string {
+	if len(t.GoalCourses) > 0 {
+		return "Study plan: " + strings.Join(t.GoalCourses, ", ")
+	}
+	return fmt.Sprintf("Study plan: %.0f ECTS", t.TargetCredits)
+}
+
+// -----------------------------------------------------------------------------
+// HANDLERS
+// -----------------------------------------------------------------------------
+
+// generateStudyPlan resolves the request against the user's transcript,
+// recommendation and the catalog. Errors are *fiber.Error with the status
+// to return.
+func (s *Server) generateStudyPlan(c *fiber.Ctx, username string, req studyPlanRequest) (studyPlanTarget, studyPlan, error) {
+	target, err := req.target(time.Now())
+	if err != nil {
+		return target, studyPlan{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	// Completed courses
+	var completed []string
+	if req.TranscriptID != 0 {
+		transcript, err := s.store.GetTranscript(c.Context(), req.TranscriptID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return target, studyPlan{}, fiber.NewError(fiber.StatusNotFound, "transcript not found")
+			}
+			return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		if transcript.UserUsername != username {
+			return target, studyPlan{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
+		}
+		completed, err = s.completedCourseCodes(c.Context(), transcript)
+		if err != nil {
+			return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to analyze transcript history: %v", err))
+		}
+	}
+
+	// Preferred courses from a saved recommendation
+	var preferred []int64
+	if req.RecommendationID != 0 {
+		reco, err := s.store.GetRecommendation(c.Context(), req.RecommendationID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return target, studyPlan{}, fiber.NewError(fiber.StatusNotFound, "recommendation not found")
+			}
+			return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		if reco.UserUsername != username {
+			return target, studyPlan{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
+		}
+		var payload struct {
+			Courses []Recommendation `json:"courses"`
+		}
+		_ = json.Unmarshal(reco.Payload, &payload)
+		for _, r := range payload.Courses {
+			preferred = append(preferred, r.CourseID)
+		}
+	}
+
+	// Archived courses are never planned, but may still be named as prerequisites
+	active, err := s.store.ListActiveCourses(c.Context())
+	if err != nil {
+		return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	catalog, err := s.store.ListAllCourses(c.Context())
+	if err != nil {
+		return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	graph, err := s.loadPrerequisiteGraph(c.Context(), catalog)
+	if err != nil {
+		return target, studyPlan{}, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("failed to load prerequisites: %v", err))
+	}
+
+	plan, err := buildStudyPlan(target, active, catalog, graph, completed, preferred)
+	if err != nil {
+		return target, studyPlan{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return target, plan, nil
+}
+
+// POST /api/plans
+func (s *Server) createStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	var req studyPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	target, plan, err := s.generateStudyPlan(c, payload.Username, req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = target.defaultTitle()
+	}
+	targetJSON, _ := json.Marshal(target)
+	planJSON, _ := json.Marshal(plan)
+
+	row, err := s.store.CreateStudyPlan(c.Context(), db.CreateStudyPlanParams{
+		UserUsername: payload.Username,
+		TranscriptID: sqlNullInt64(req.TranscriptID),
+		Title:        title,
+		Target:       targetJSON,
+		Plan:         planJSON,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(row)
+}
+
+// GET /api/plans
+func (s *Server) listStudyPlans(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	plans, err := s.store.ListStudyPlans(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(plans)
+}
+
+// fetchOwnedStudyPlan loads a plan by the :id param and checks ownership.
+func (s *Server) fetchOwnedStudyPlan(c *fiber.Ctx, username string) (db.StudyPlan, error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return db.StudyPlan{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	plan, err := s.store.GetStudyPlan(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.StudyPlan{}, fiber.NewError(fiber.StatusNotFound, "study plan not found")
+		}
+		return db.StudyPlan{}, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if plan.UserUsername != username {
+		return db.StudyPlan{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
+	}
+	return plan, nil
+}
+
+// GET /api/plans/:id
+func (s *Server) getStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	plan, err := s.fetchOwnedStudyPlan(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+	return c.JSON(plan)
+}
+
+// PUT /api/plans/:id
+// Regenerates the plan from a new target (same body as POST).
+func (s *Server) updateStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	existing, err := s.fetchOwnedStudyPlan(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	var req studyPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	target, plan, err := s.generateStudyPlan(c, payload.Username, req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = existing.Title
+	}
+	targetJSON, _ := json.Marshal(target)
+	planJSON, _ := json.Marshal(plan)
+
+	row, err := s.store.UpdateStudyPlan(c.Context(), db.UpdateStudyPlanParams{
+		Title:        title,
+		TranscriptID: sqlNullInt64(req.TranscriptID),
+		Target:       targetJSON,
+		Plan:         planJSON,
+		ID:           existing.ID,
+		UserUsername: payload.Username,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(row)
+}
+
+// DELETE /api/plans/:id
+func (s *Server) deleteStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	existing, err := s.fetchOwnedStudyPlan(c, payload.Username)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	if err := s.store.DeleteStudyPlan(c.Context(), db.DeleteStudyPlanParams{
+		ID:           existing.ID,
+		UserUsername: payload.Username,
+	}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to delete study plan: %v", err)))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// loadSummaryStudyPlan returns the plan to print in a summary PDF: the
+// requested one, or the user's latest. Returns nil when there is none.
+func (s *Server) loadSummaryStudyPlan(ctx context.Context, username string, planID int64) *studyPlan {
+	var row db.StudyPlan
+	if planID != 0 {
+		r, err := s.store.GetStudyPlan(ctx, planID)
+		if err != nil || r.UserUsername != username {
+			log.Printf("[WARN] Could not load study plan %d for %s: %v", planID, username, err)
+			return nil
+		}
+		row = r
+	} else {
+		rows, err := s.store.ListStudyPlans(ctx, username)
+		if err != nil || len(rows) == 0 {
+			return nil
+		}
+		row = rows[0]
+	}
+
+	var plan studyPlan
+	if err := json.Unmarshal(row.Plan, &plan); err != nil {
+		log.Printf("[WARN] Invalid study plan JSON in plan %d: %v", row.ID, err)
+		return nil
+	}
+	return &plan
+}
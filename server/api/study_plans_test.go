@@ -0,0 +1,262 @@
+// server/api/study_plans_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlanCourse(id int64, code, language, prerequisites string) db.Course {
+	return db.Course{
+		ID:            id,
+		Code:          code,
+		Name:          code + " course",
+		Language:      sql.NullString{String: language, Valid: language != ""},
+		Prerequisites: sql.NullString{String: prerequisites, Valid: prerequisites != ""},
+	}
+}
+
+func testPrerequisiteGraph(catalog []db.Course) map[int64]coursePrerequisites {
+	graph := make(map[int64]coursePrerequisites, len(catalog))
+	for _, c := range catalog {
+		graph[c.ID] = parsePrerequisites(c.Prerequisites.String, c.Code)
+	}
+	return graph
+}
+
+func planCodes(plan studyPlan) [][]string {
+	out := make([][]string, 0, len(plan.Terms))
+	for _, t := range plan.Terms {
+		var codes []string
+		for _, c := range t.Courses {
+			codes = append(codes, c.Code)
+		}
+		out = append(out, codes)
+	}
+	return out
+}
+
+func TestStudyPlanRequestTarget(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		req   studyPlanRequest
+		ok    bool
+		start string
+		cap   float64
+		goals []string
+	}{
+		{name: "CreditsWithDefaults", req: studyPlanRequest{TargetCredits: 60}, ok: true, start: "Spring 2027", cap: defaultTermCreditCap},
+		{name: "GoalsNormalized", req: studyPlanRequest{GoalCourses: []string{" ties454", "TIES454"}, StartTerm: "fall 2027"}, ok: true, start: "Autumn 2027", cap: defaultTermCreditCap, goals: []string{"TIES454"}},
+		{name: "NoTarget", req: studyPlanRequest{}},
+		{name: "BothTargets", req: studyPlanRequest{TargetCredits: 30, GoalCourses: []string{"TIES454"}}},
+		{name: "CapTooSmall", req: studyPlanRequest{TargetCredits: 30, MaxCreditsPerTerm: 2}},
+		{name: "TooManyCredits", req: studyPlanRequest{TargetCredits: 1000}},
+		{name: "BadStartTerm", req: studyPlanRequest{TargetCredits: 30, StartTerm: "next year"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target, err := tc.req.target(now)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.start, target.StartTerm)
+			require.Equal(t, tc.cap, target.MaxCreditsPerTerm)
+			require.Equal(t, tc.goals, target.GoalCourses)
+		})
+	}
+}
+
+func TestBuildStudyPlanGoals(t *testing.T) {
+	catalog := []db.Course{
+		testPlanCourse(1, "TJTS5010", "English", ""),
+		testPlanCourse(2, "TJTS5011", "Finnish", ""),
+		testPlanCourse(3, "TJTS5012", "English", ""),
+		testPlanCourse(4, "TJTS5020", "English", "TJTS5010 and TJTS5011 or TJTS5012"),
+		testPlanCourse(5, "TJTS5030", "English", "TJTS5020"),
+		testPlanCourse(6, "TJTS5040", "English", "Recommended: TJTS5030"),
+	}
+	graph := testPrerequisiteGraph(catalog)
+	target := studyPlanTarget{GoalCourses: []string{"TJTS5030", "TJTS5040"}, MaxCreditsPerTerm: 10, Language: "English", StartTerm: "Autumn 2026"}
+
+	plan, err := buildStudyPlan(target, catalog, catalog, graph, nil, nil)
+	require.NoError(t, err)
+
+	// TJTS5012 is picked over TJTS5011 for the language; advisory
+	// prerequisites of TJTS5040 do not hold it back.
+	require.Equal(t, [][]string{
+		{"TJTS5010", "TJTS5012"},
+		{"TJTS5020", "TJTS5040"},
+		{"TJTS5030"},
+	}, planCodes(plan))
+	require.Equal(t, []string{"Autumn 2026", "Spring 2027", "Autumn 2027"}, []string{plan.Terms[0].Term, plan.Terms[1].Term, plan.Terms[2].Term})
+	require.Equal(t, 25.0, plan.TotalCredits)
+	require.True(t, plan.Terms[2].Courses[0].Goal)
+	require.Empty(t, plan.Unscheduled)
+
+	// Completed prerequisites are not planned again
+	plan, err = buildStudyPlan(target, catalog, catalog, graph, []string{"TJTS5010", "TJTS5011", "TJTS5020"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"TJTS5030", "TJTS5040"}}, planCodes(plan))
+
+	// Unknown goal
+	_, err = buildStudyPlan(studyPlanTarget{GoalCourses: []string{"NOPE100"}, MaxCreditsPerTerm: 30}, catalog, catalog, graph, nil, nil)
+	require.Error(t, err)
+}
+
+func TestBuildStudyPlanCredits(t *testing.T) {
+	catalog := []db.Course{
+		testPlanCourse(1, "TIES100", "Finnish", ""),
+		testPlanCourse(2, "TIES200", "English", "TIES300"),
+		testPlanCourse(3, "TIES300", "English, Finnish", ""),
+		testPlanCourse(4, "TIES400", "English", ""),
+		testPlanCourse(5, "TIES500", "English", "TIES999"),
+	}
+	graph := testPrerequisiteGraph(catalog)
+	target := studyPlanTarget{TargetCredits: 15, MaxCreditsPerTerm: 10, Language: "english", StartTerm: "Spring 2027"}
+
+	// Recommended course 2 comes first but must wait for its prerequisite.
+	plan, err := buildStudyPlan(target, catalog, catalog, graph, nil, []int64{2})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"TIES300", "TIES400"}, {"TIES200"}}, planCodes(plan))
+	require.Equal(t, 15.0, plan.TotalCredits)
+
+	// Not enough reachable courses
+	target.TargetCredits = 30
+	plan, err = buildStudyPlan(target, catalog, catalog, graph, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, 15.0, plan.TotalCredits)
+	require.Len(t, plan.Warnings, 1)
+}
+
+func TestBuildStudyPlanArchived(t *testing.T) {
+	catalog := []db.Course{
+		testPlanCourse(1, "TIES100", "English", ""),
+		testPlanCourse(2, "TIES200", "English", ""),
+		testPlanCourse(3, "TIES300", "English", "TIES200"),
+		testPlanCourse(4, "TIES400", "English", "TIES100 or TIES200"),
+	}
+	catalog[1].ArchivedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	active := []db.Course{catalog[0], catalog[2], catalog[3]}
+	graph := testPrerequisiteGraph(catalog)
+
+	// Credit mode never picks the archived course
+	target := studyPlanTarget{TargetCredits: 30, MaxCreditsPerTerm: 30, StartTerm: "Spring 2027"}
+	plan, err := buildStudyPlan(target, active, catalog, graph, nil, []int64{2})
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"TIES100"}, {"TIES400"}}, planCodes(plan))
+
+	// An archived prerequisite is looked up but not planned; an active
+	// alternative is picked instead
+	target = studyPlanTarget{GoalCourses: []string{"TIES300", "TIES400"}, MaxCreditsPerTerm: 30, StartTerm: "Spring 2027"}
+	plan, err = buildStudyPlan(target, active, catalog, graph, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"TIES100"}, {"TIES400"}}, planCodes(plan))
+	require.Len(t, plan.Unscheduled, 1)
+	require.Equal(t, "TIES300", plan.Unscheduled[0].Code)
+	require.Contains(t, plan.Warnings, "prerequisite TIES200 of TIES300 is no longer offered")
+
+	// An archived goal
+	target.GoalCourses = []string{"TIES200"}
+	_, err = buildStudyPlan(target, active, catalog, graph, nil, nil)
+	require.Error(t, err)
+}
+
+func TestCreateStudyPlanAPI(t *testing.T) {
+	username := util.RandomOwner()
+	catalog := []db.Course{
+		testPlanCourse(1, "TJTS5010", "English", ""),
+		testPlanCourse(2, "TJTS5020", "English", "TJTS5010"),
+	}
+
+	testCases := []struct {
+		name          string
+		body          map[string]any
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OK",
+			body: map[string]any{"goal_courses": []string{"TJTS5020"}, "start_term": "Autumn 2026"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListActiveCourses(gomock.Any()).Times(1).Return(catalog, nil)
+				store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return(catalog, nil)
+				store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).Return([]db.CoursePrerequisite{}, nil)
+				store.EXPECT().UpsertCoursePrerequisite(gomock.Any(), gomock.Any()).Times(2).Return(db.CoursePrerequisite{}, nil)
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreateStudyPlanParams) (db.StudyPlan, error) {
+						require.Equal(t, username, arg.UserUsername)
+						require.Equal(t, "Study plan: TJTS5020", arg.Title)
+						return db.StudyPlan{ID: 1, UserUsername: username, Title: arg.Title, Target: arg.Target, Plan: arg.Plan}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+				var got struct {
+					Plan studyPlan `json:"plan"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, [][]string{{"TJTS5010"}, {"TJTS5020"}}, planCodes(got.Plan))
+			},
+		},
+		{
+			name: "MissingTarget",
+			body: map[string]any{"language": "English"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "ForeignTranscript",
+			body: map[string]any{"target_credits": 30, "transcript_id": 9},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(int64(9))).Times(1).Return(db.Transcript{ID: 9, UserUsername: "someoneelse"}, nil)
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req := httptest.NewRequest(http.MethodPost, "/api/plans", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -3,6 +3,7 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -24,6 +25,7 @@ type createSummaryReq struct {
 	RecommendationID    int64  `json:"recommendation_id"`
 	SummaryText         string `json:"summary_text"`
 	IncludeScholarships bool   `json:"include_scholarships"`
+	StudyPlanID         int64  `json:"study_plan_id"` // optional; defaults to the latest plan
 }
 
 // POST /api/summaries
@@ -38,72 +40,103 @@ func (s *Server) createSummaryPDF(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
 
+	row, err := s.writeSummaryPDF(c.Context(), payload.Username, req)
+	if err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"id":         row.ID,
+		"user":       payload.Username,
+		"pdf_path":   row.PdfPath.String,
+		"created_at": row.CreatedAt,
+	})
+}
+
+// writeSummaryPDF renders the PDF summary of one of username's
+// recommendations and records it. Errors the client can fix are returned as
+// fiber.Error. Used by POST /api/summaries and the chat's create_summary_pdf tool.
+func (s *Server) writeSummaryPDF(ctx context.Context, username string, req createSummaryReq) (db.Summary, error) {
 	// Validate recommendation ownership
-	reco, err := s.store.GetRecommendation(c.Context(), req.RecommendationID)
+	reco, err := s.store.GetRecommendation(ctx, req.RecommendationID)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+		return db.Summary{}, fiber.NewError(fiber.StatusNotFound, "recommendation not found")
 	}
-	if reco.UserUsername != payload.Username {
-		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	if reco.UserUsername != username {
+		return db.Summary{}, fiber.NewError(fiber.StatusForbidden, "forbidden")
 	}
 
-	// Fetch the most recent scholarships (avoid duplicates from past generations)
-	scholarships, err := s.store.ListRecentScholarshipsByUser(c.Context(), db.ListRecentScholarshipsByUserParams{
-		UserUsername: payload.Username,
-		Limit:        10, // adjust if you ever want more
+	// Fetch the user's best open scholarship matches (deduplicated at ingestion)
+	scholarships, err := s.store.ListUserScholarships(ctx, db.ListUserScholarshipsParams{
+		UserUsername: username,
+		Limit:        scholarshipContextLimit,
 	})
 	if err != nil {
-		log.Printf("[WARN] Could not load latest scholarships for %s: %v", payload.Username, err)
-		scholarships = []db.Scholarship{}
+		log.Printf("[WARN] Could not load latest scholarships for %s: %v", username, err)
+		scholarships = []db.ListUserScholarshipsRow{}
 	}
 
 	// 🔹 If no summary text provided, fallback to latest AI-generated summary (if exists)
 	summaryText := strings.TrimSpace(req.SummaryText)
 	if summaryText == "" {
 		// Try to find last generated summary for the same user
-		prevSummaries, _ := s.store.ListSummaries(c.Context(), payload.Username)
+		prevSummaries, _ := s.store.ListSummaries(ctx, username)
 		if len(prevSummaries) > 0 && prevSummaries[0].SummaryText.Valid {
 			summaryText = prevSummaries[0].SummaryText.String
 		}
 	}
 
+	// 🔹 Load transcript analytics (credits, GPA) if the recommendation has a transcript
+	var analytics *transcriptAnalytics
+	if reco.TranscriptID.Valid {
+		analytics, err = s.loadTranscriptAnalytics(ctx, reco.TranscriptID.Int64)
+		if err != nil {
+			log.Printf("[WARN] Could not compute transcript analytics for %s: %v", username, err)
+			analytics = nil
+		}
+	}
+
+	// 🔹 Load the study plan (requested or latest), if any
+	plan := s.loadSummaryStudyPlan(ctx, username, req.StudyPlanID)
+
 	// 🔹 Generate PDF
 	filename := fmt.Sprintf("summary_%d_%d.pdf", req.RecommendationID, time.Now().Unix())
 	outPath := filepath.Join(s.summariesDir, filename)
 
-	if err := writeRecoPDF(outPath, reco, summaryText, scholarships, payload.Username); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to create PDF: %v", err)))
+	if err := writeRecoPDF(outPath, reco, summaryText, analytics, plan, scholarships, username); err != nil {
+		return db.Summary{}, fmt.Errorf("failed to create PDF: %v", err)
 	}
 
 	// 🔹 Save summary record in DB
-	row, err := s.store.CreateSummary(c.Context(), db.CreateSummaryParams{
-		UserUsername:     payload.Username,
+	row, err := s.store.CreateSummary(ctx, db.CreateSummaryParams{
+		UserUsername:     username,
 		RecommendationID: sqlNullInt64(req.RecommendationID),
 		SummaryText:      sqlNullString(summaryText),
 		PdfPath:          sqlNullString(outPath),
 	})
 	if err != nil {
 		_ = os.Remove(outPath)
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to save summary: %v", err)))
+		return db.Summary{}, fmt.Errorf("failed to save summary: %v", err)
 	}
 
-	log.Printf("[INFO] Summary PDF created for user %s: %s", payload.Username, outPath)
+	log.Printf("[INFO] Summary PDF created for user %s: %s", username, outPath)
+	s.publishEvent(ctx, username, eventSummaryPDFWritten, fiber.Map{"summary_id": row.ID})
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"id":         row.ID,
-		"user":       payload.Username,
-		"pdf_path":   row.PdfPath.String,
-		"created_at": row.CreatedAt,
-	})
+	return row, nil
 }
 
 // GET /api/summaries
+// GET /api/advisor/students/:username/summaries
 func (s *Server) listSummaries(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
-	items, err := s.store.ListSummaries(c.Context(), payload.Username)
+	username := c.Params("username", payload.Username)
+	if err := s.checkReadAccess(c.Context(), payload, username); err != nil {
+		return statusErrorResponse(c, err)
+	}
+	items, err := s.store.ListSummaries(c.Context(), username)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
@@ -111,6 +144,7 @@ func (s *Server) listSummaries(c *fiber.Ctx) error {
 }
 
 // GET /api/summaries/:id/download
+// GET /api/advisor/students/:username/summaries/:id/download
 func (s *Server) downloadSummaryPDF(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
@@ -122,17 +156,18 @@ func (s *Server) downloadSummaryPDF(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
 
-	// Get summary for the authorized user
+	// Get summary of the authorized user, or of an advisor's student
+	username := c.Params("username", payload.Username)
+	if err := s.checkReadAccess(c.Context(), payload, username); err != nil {
+		return statusErrorResponse(c, err)
+	}
 	sum, err := s.store.GetSummary(c.Context(), db.GetSummaryParams{
 		ID:           id,
-		UserUsername: payload.Username,
+		UserUsername: username,
 	})
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("summary not found")))
 	}
-	if sum.UserUsername != payload.Username {
-		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
-	}
 
 	// ✅ Check that pdf_path exists and is valid
 	if !sum.PdfPath.Valid || strings.TrimSpace(sum.PdfPath.String) == "" {
@@ -199,8 +234,9 @@ func (s *Server) deleteSummary(c *fiber.Ctx) error {
 }
 
 // ---- PDF Generation ----
-// writeRecoPDF generates a professional PDF report including summary, courses, and scholarships.
-func writeRecoPDF(path string, reco db.Recommendation, summaryText string, scholarships []db.Scholarship, username string) error {
+// writeRecoPDF generates a professional PDF report including summary, academic record, courses, study plan, and scholarships.
+// analytics may be nil when the transcript has no parsed course records; plan may be nil when the user has none.
+func writeRecoPDF(path string, reco db.Recommendation, summaryText string, analytics *transcriptAnalytics, plan *studyPlan, scholarships []db.ListUserScholarshipsRow, username string) error {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 
@@ -227,6 +263,40 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 		pdf.Ln(8)
 	}
 
+	// --- Academic Record (computed from parsed transcript rows) ---
+	if analytics != nil {
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.Cell(0, 8, "Academic Record")
+		pdf.Ln(8)
+
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, 6, fmt.Sprintf("Completed credits: %.1f ECTS in %d courses", analytics.TotalCredits, analytics.CompletedCourses), "", "", false)
+		pdf.MultiCell(0, 6, fmt.Sprintf("Weighted GPA (0-5): %.2f over %d graded courses (%.1f ECTS)", analytics.GPA, analytics.GradedCourses, analytics.GradedCredits), "", "", false)
+
+		if len(analytics.CreditsByOrganiser) > 0 {
+			pdf.Ln(2)
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.Cell(0, 6, "Credits by organiser")
+			pdf.Ln(6)
+			pdf.SetFont("Helvetica", "", 11)
+			for _, o := range analytics.CreditsByOrganiser {
+				pdf.MultiCell(0, 6, fmt.Sprintf("- %s: %.1f ECTS (%d courses)", cleanText(o.Organiser), o.Credits, o.Courses), "", "", false)
+			}
+		}
+
+		if len(analytics.Terms) > 0 {
+			pdf.Ln(2)
+			pdf.SetFont("Helvetica", "B", 11)
+			pdf.Cell(0, 6, "Per-term trend")
+			pdf.Ln(6)
+			pdf.SetFont("Helvetica", "", 11)
+			for _, t := range analytics.Terms {
+				pdf.MultiCell(0, 6, fmt.Sprintf("- %s: %.1f ECTS, GPA %.2f", t.Term, t.Credits, t.GPA), "", "", false)
+			}
+		}
+		pdf.Ln(8)
+	}
+
 	// --- Recommended Courses ---
 	pdf.SetFont("Helvetica", "B", 14)
 	pdf.Cell(0, 8, "Recommended Courses")
@@ -243,6 +313,8 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 		Code        string  `json:"code"`
 		Link        string  `json:"link"`
 		CourseID    int64   `json:"course_id"`
+
+		MissingPrerequisites []string `json:"missing_prerequisites"`
 	}
 
 	var payload struct {
@@ -264,6 +336,9 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 
 			// 2. Print Rationale/Description
 			pdf.MultiCell(0, 6, fmt.Sprintf("Rationale: %s", cleanText(c.Description)), "", "", false) 
+			if len(c.MissingPrerequisites) > 0 {
+				pdf.MultiCell(0, 6, fmt.Sprintf("Missing prerequisites: %s", strings.Join(c.MissingPrerequisites, ", ")), "", "", false)
+			}
 
 			// 3. Print Link (Clickable)
 			if strings.TrimSpace(c.Link) != "" {
@@ -276,6 +351,36 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 		}
 	}
 
+	// --- Study Plan Section ---
+	if plan != nil && len(plan.Terms) > 0 {
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.Cell(0, 8, "Study Plan")
+		pdf.Ln(10)
+
+		for _, t := range plan.Terms {
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.MultiCell(0, 6, fmt.Sprintf("%s (%.0f ECTS)", t.Term, t.Credits), "", "", false)
+			pdf.SetFont("Helvetica", "", 11)
+			for _, pc := range t.Courses {
+				line := fmt.Sprintf("- %s %s", pc.Code, cleanText(pc.Name))
+				if pc.Prerequisites != "" {
+					line += fmt.Sprintf(" [requires %s]", pc.Prerequisites)
+				}
+				pdf.MultiCell(0, 6, line, "", "", false)
+			}
+			pdf.Ln(2)
+		}
+		pdf.MultiCell(0, 6, fmt.Sprintf("Total planned: %.0f ECTS", plan.TotalCredits), "", "", false)
+
+		for _, u := range plan.Unscheduled {
+			pdf.MultiCell(0, 6, fmt.Sprintf("Not scheduled: %s (%s)", u.Code, u.Reason), "", "", false)
+		}
+		for _, w := range plan.Warnings {
+			pdf.MultiCell(0, 6, fmt.Sprintf("Note: %s", w), "", "", false)
+		}
+	}
+
 	// --- Scholarships Section ---
 	if len(scholarships) > 0 {
 		pdf.Ln(8)
@@ -284,36 +389,23 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 		pdf.Ln(10)
 		pdf.SetFont("Helvetica", "", 11)
 
-		// Deduplicate by title (case-insensitive)
-		seen := make(map[string]bool)
-		unique := make([]db.Scholarship, 0, len(scholarships))
-		for _, s := range scholarships {
-			key := strings.ToLower(strings.TrimSpace(s.Title))
-			if key == "" || seen[key] {
-				continue
-			}
-			seen[key] = true
-			unique = append(unique, s)
-		}
-
-		for i, sch := range unique {
+		for i, sch := range scholarships {
 			title := cleanText(sch.Title)
 			desc := "No description available."
 			if sch.Description.Valid && strings.TrimSpace(sch.Description.String) != "" {
 				desc = cleanText(sch.Description.String)
 			}
-			match := ""
-			if sch.MatchScore.Valid {
-				match = fmt.Sprintf("(Match: %.1f%%)", sch.MatchScore.Float64)
-			}
+			match := fmt.Sprintf("(Match: %.1f%%)", sch.MatchScore)
 
 			pdf.MultiCell(0, 6,
 				fmt.Sprintf("%d) %s %s\n%s\n", i+1, title, match, desc),
 				"", "", false)
+			if details := scholarshipDetails(sch); details != "" {
+				pdf.MultiCell(0, 6, cleanText(details), "", "", false)
+			}
 
 			// Render link (clickable + wrapped)
-			if sch.Link.Valid && strings.TrimSpace(sch.Link.String) != "" {
-				link := strings.TrimSpace(sch.Link.String)
+			if link := strings.TrimSpace(sch.CanonicalUrl); link != "" {
 				pdf.SetTextColor(0, 0, 255)
 				linkWidth := pdf.GetStringWidth(link)
 				if linkWidth > 190 { // wrap long URLs
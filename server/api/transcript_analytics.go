@@ -0,0 +1,271 @@
+// server/api/transcript_analytics.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+const unknownBucket = "Unknown"
+
+// transcriptAnalytics is the numeric view of a transcript's parsed course rows.
+type transcriptAnalytics struct {
+	TranscriptID       int64              `json:"transcript_id"`
+	TotalCredits       float64            `json:"total_credits"`  // completed credits only
+	GradedCredits      float64            `json:"graded_credits"` // credits that count towards the GPA
+	GradedCourses      int                `json:"graded_courses"` // courses in the GPA, with or without credits
+	GPA                float64            `json:"gpa"`            // credit-weighted, 0–5 scale
+	CompletedCourses   int                `json:"completed_courses"`
+	FailedCourses      int                `json:"failed_courses"`
+	InProgressCourses  int                `json:"in_progress_courses"`
+	CreditsByOrganiser []organiserCredits `json:"credits_by_organiser"`
+	Terms              []termAnalytics    `json:"terms"`
+}
+
+type organiserCredits struct {
+	Organiser string  `json:"organiser"`
+	Credits   float64 `json:"credits"`
+	Courses   int     `json:"courses"`
+}
+
+type termAnalytics struct {
+	Term    string  `json:"term"`
+	Credits float64 `json:"credits"`
+	GPA     float64 `json:"gpa"`
+	Courses int     `json:"courses"`
+}
+
+// gpaAccumulator collects a credit-weighted grade average.
+type gpaAccumulator struct {
+	points, weight float64
+}
+
+func (a *gpaAccumulator) add(grade, weight float64) {
+	a.points += grade * weight
+	a.weight += weight
+}
+
+func (a gpaAccumulator) value() float64 {
+	if a.weight == 0 {
+		return 0
+	}
+	return math.Round(a.points/a.weight*100) / 100
+}
+
+// computeTranscriptAnalytics aggregates parsed course rows. The catalog is
+// used to resolve each course's organiser and grading scale; courses graded
+// pass/fail (by grade or by their catalog grading_scale) are excluded from
+// the GPA. Rows without stated credits weigh 1 in the GPA.
+func computeTranscriptAnalytics(transcriptID int64, rows []db.TranscriptCourse, catalog []db.Course) transcriptAnalytics {
+	byCode := make(map[string]db.Course, len(catalog))
+	for _, c := range catalog {
+		byCode[strings.ToUpper(strings.TrimSpace(c.Code))] = c
+	}
+
+	out := transcriptAnalytics{TranscriptID: transcriptID}
+	var overall gpaAccumulator
+	organisers := make(map[string]*organiserCredits)
+	terms := make(map[string]*termAnalytics)
+	termGPA := make(map[string]*gpaAccumulator)
+
+	for _, row := range rows {
+		switch row.Status {
+		case courseStatusFailed:
+			out.FailedCourses++
+			continue
+		case courseStatusInProgress:
+			out.InProgressCourses++
+			continue
+		}
+		out.CompletedCourses++
+
+		credits := row.Credits.Float64
+		out.TotalCredits += credits
+
+		course, inCatalog := byCode[strings.ToUpper(row.CourseCode)]
+
+		organiser := unknownBucket
+		if inCatalog && strings.TrimSpace(course.Organiser.String) != "" {
+			organiser = strings.TrimSpace(course.Organiser.String)
+		}
+		if organisers[organiser] == nil {
+			organisers[organiser] = &organiserCredits{Organiser: organiser}
+		}
+		organisers[organiser].Credits += credits
+		organisers[organiser].Courses++
+
+		term := strings.TrimSpace(row.Term.String)
+		if term == "" {
+			term = unknownBucket
+		}
+		if terms[term] == nil {
+			terms[term] = &termAnalytics{Term: term}
+			termGPA[term] = &gpaAccumulator{}
+		}
+		terms[term].Credits += credits
+		terms[term].Courses++
+
+		grade, graded := numericGrade(row.Grade.String)
+		if !graded || (inCatalog && isPassFailScale(course.GradingScale.String)) {
+			continue
+		}
+		weight := credits
+		if weight <= 0 {
+			weight = 1
+		}
+		overall.add(grade, weight)
+		termGPA[term].add(grade, weight)
+		out.GradedCredits += credits
+		out.GradedCourses++
+	}
+
+	out.GPA = overall.value()
+
+	out.CreditsByOrganiser = make([]organiserCredits, 0, len(organisers))
+	for _, o := range organisers {
+		out.CreditsByOrganiser = append(out.CreditsByOrganiser, *o)
+	}
+	sort.Slice(out.CreditsByOrganiser, func(i, j int) bool {
+		a, b := out.CreditsByOrganiser[i], out.CreditsByOrganiser[j]
+		if a.Credits != b.Credits {
+			return a.Credits > b.Credits
+		}
+		return a.Organiser < b.Organiser
+	})
+
+	out.Terms = make([]termAnalytics, 0, len(terms))
+	for name, t := range terms {
+		t.GPA = termGPA[name].value()
+		out.Terms = append(out.Terms, *t)
+	}
+	sort.Slice(out.Terms, func(i, j int) bool { return termSortKey(out.Terms[i].Term) < termSortKey(out.Terms[j].Term) })
+
+	return out
+}
+
+// numericGrade returns the grade on the 1–5 scale. Failing (0) and
+// pass/fail grades do not count towards the GPA.
+func numericGrade(g string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(g), 64)
+	if err != nil || v < 1 || v > 5 {
+		return 0, false
+	}
+	return v, true
+}
+
+// isPassFailScale reports whether a catalog grading_scale is non-numeric,
+// e.g. "Pass-Fail" or "Rejected-Approved".
+func isPassFailScale(scale string) bool {
+	scale = strings.ToLower(scale)
+	return strings.Contains(scale, "pass") || strings.Contains(scale, "approved")
+}
+
+// termSortKey orders "Spring 2023" < "Summer 2023" < "Autumn 2023"; unknown terms sort last.
+func termSortKey(term string) int {
+	fields := strings.Fields(term)
+	if len(fields) != 2 {
+		return math.MaxInt
+	}
+	year, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return math.MaxInt
+	}
+	season := 0
+	switch fields[0] {
+	case "Spring":
+		season = 1
+	case "Summer":
+		season = 2
+	case "Autumn":
+		season = 3
+	}
+	return year*10 + season
+}
+
+// promptText renders the analytics as a plain-text block for LLM prompts.
+func (a transcriptAnalytics) promptText() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Completed courses: %d (failed: %d, in progress: %d)\n", a.CompletedCourses, a.FailedCourses, a.InProgressCourses))
+	sb.WriteString(fmt.Sprintf("Total completed credits: %.1f ECTS\n", a.TotalCredits))
+	sb.WriteString(fmt.Sprintf("Weighted GPA (0-5 scale): %.2f over %d graded courses (%.1f ECTS)\n", a.GPA, a.GradedCourses, a.GradedCredits))
+	if len(a.CreditsByOrganiser) > 0 {
+		sb.WriteString("Credits by organiser:\n")
+		for _, o := range a.CreditsByOrganiser {
+			sb.WriteString(fmt.Sprintf("- %s: %.1f ECTS (%d courses)\n", o.Organiser, o.Credits, o.Courses))
+		}
+	}
+	if len(a.Terms) > 0 {
+		sb.WriteString("Per-term trend:\n")
+		for _, t := range a.Terms {
+			sb.WriteString(fmt.Sprintf("- %s: %.1f ECTS, GPA %.2f (%d courses)\n", t.Term, t.Credits, t.GPA, t.Courses))
+		}
+	}
+	return sb.String()
+}
+
+// loadTranscriptAnalytics computes analytics for a transcript from its stored
+// course rows. It returns nil when the transcript has no parsed rows.
+func (s *Server) loadTranscriptAnalytics(ctx context.Context, transcriptID int64) (*transcriptAnalytics, error) {
+	rows, err := s.store.ListTranscriptCourses(ctx, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	catalog, err := s.store.ListAllCourses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	analytics := computeTranscriptAnalytics(transcriptID, rows, catalog)
+	return &analytics, nil
+}
+
+// GET /api/transcripts/:id/analytics
+func (s *Server) getTranscriptAnalytics(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch transcript + access check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.checkReadAccess(c.Context(), payload, tr.UserUsername); err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	// 3) Compute
+	analytics, err := s.loadTranscriptAnalytics(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if analytics == nil {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("transcript has no parsed course records")))
+	}
+
+	return c.JSON(analytics)
+}
@@ -0,0 +1,162 @@
+// server/api/transcript_analytics_test.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testTranscriptCourse(code string, credits float64, grade, term, status string) db.TranscriptCourse {
+	return db.TranscriptCourse{
+		CourseCode: code,
+		CourseName: code,
+		Credits:    sql.NullFloat64{Float64: credits, Valid: credits > 0},
+		Grade:      sql.NullString{String: grade, Valid: grade != ""},
+		Term:       sql.NullString{String: term, Valid: term != ""},
+		Status:     status,
+	}
+}
+
+func testCatalogCourse(code, organiser, scale string) db.Course {
+	return db.Course{
+		Code:         code,
+		Organiser:    sql.NullString{String: organiser, Valid: organiser != ""},
+		GradingScale: sql.NullString{String: scale, Valid: scale != ""},
+	}
+}
+
+func TestComputeTranscriptAnalytics(t *testing.T) {
+	rows := []db.TranscriptCourse{
+		testTranscriptCourse("TIES454", 5, "4", "Spring 2023", courseStatusCompleted),
+		testTranscriptCourse("TJTS5010", 10, "2", "Autumn 2022", courseStatusCompleted),
+		testTranscriptCourse("KOGS1001", 5, "pass", "Autumn 2022", courseStatusCompleted),
+		testTranscriptCourse("HYTY0001", 5, "5", "Spring 2023", courseStatusCompleted), // pass/fail scale in catalog
+		testTranscriptCourse("TIEP111", 6, "0", "Autumn 2022", courseStatusFailed),
+		testTranscriptCourse("TJTSM51", 5, "", "", courseStatusInProgress),
+	}
+	catalog := []db.Course{
+		testCatalogCourse("TIES454", "Faculty of Information Technology", "General scale, 0-5"),
+		testCatalogCourse("TJTS5010", "Faculty of Information Technology", "General scale, 0-5"),
+		testCatalogCourse("HYTY0001", "Faculty of Education", "Rejected-Approved"),
+	}
+
+	a := computeTranscriptAnalytics(7, rows, catalog)
+
+	require.Equal(t, int64(7), a.TranscriptID)
+	require.Equal(t, 25.0, a.TotalCredits)
+	require.Equal(t, 15.0, a.GradedCredits)
+	require.Equal(t, 2, a.GradedCourses)
+	require.Equal(t, 2.67, a.GPA) // (4*5 + 2*10) / 15
+	require.Equal(t, 4, a.CompletedCourses)
+	require.Equal(t, 1, a.FailedCourses)
+	require.Equal(t, 1, a.InProgressCourses)
+
+	require.Equal(t, []organiserCredits{
+		{Organiser: "Faculty of Information Technology", Credits: 15, Courses: 2},
+		{Organiser: "Faculty of Education", Credits: 5, Courses: 1},
+		{Organiser: unknownBucket, Credits: 5, Courses: 1},
+	}, a.CreditsByOrganiser)
+
+	require.Equal(t, []termAnalytics{
+		{Term: "Autumn 2022", Credits: 15, GPA: 2, Courses: 2},
+		{Term: "Spring 2023", Credits: 10, GPA: 4, Courses: 2},
+	}, a.Terms)
+}
+
+func TestComputeTranscriptAnalyticsWithoutCredits(t *testing.T) {
+	rows := []db.TranscriptCourse{
+		testTranscriptCourse("TIES454", 0, "4", "Spring 2023", courseStatusCompleted),
+		testTranscriptCourse("TIES455", 0, "2", "Spring 2023", courseStatusCompleted),
+	}
+
+	a := computeTranscriptAnalytics(7, rows, nil)
+
+	// Rows without credits weigh 1 each
+	require.Equal(t, 3.0, a.GPA)
+	require.Zero(t, a.GradedCredits)
+	require.Equal(t, 2, a.GradedCourses)
+	require.Contains(t, a.promptText(), "Weighted GPA (0-5 scale): 3.00 over 2 graded courses (0.0 ECTS)\n")
+}
+
+func TestGetTranscriptAnalyticsAPI(t *testing.T) {
+	username := util.RandomOwner()
+	transcript := db.Transcript{ID: 3, UserUsername: username}
+
+	testCases := []struct {
+		name          string
+		owner         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "OK",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return(transcript, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).
+					Return([]db.TranscriptCourse{testTranscriptCourse("TIES454", 5, "4", "Spring 2023", courseStatusCompleted)}, nil)
+				store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return([]db.Course{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var got transcriptAnalytics
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+				require.Equal(t, 5.0, got.TotalCredits)
+				require.Equal(t, 4.0, got.GPA)
+			},
+		},
+		{
+			name:  "Forbidden",
+			owner: "someoneelse",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return(transcript, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:  "NoParsedCourses",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return(transcript, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(transcript.ID)).Times(1).Return([]db.TranscriptCourse{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/transcripts/%d/analytics", transcript.ID), nil)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, tc.owner, util.StudentRole, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
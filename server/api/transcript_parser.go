@@ -0,0 +1,322 @@
+// server/api/transcript_parser.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Course record statuses stored in transcript_courses.status.
+const (
+	courseStatusCompleted  = "completed"
+	courseStatusFailed     = "failed"
+	courseStatusInProgress = "in_progress"
+)
+
+// Parsers recorded in transcript_courses.parser.
+const (
+	transcriptParserHeuristic = "heuristic"
+	transcriptParserLLM       = "llm"
+)
+
+// parsedCourse is one normalized course record extracted from transcript text.
+type parsedCourse struct {
+	Code        string
+	Name        string
+	Credits     float64 // 0 when the transcript does not state it
+	Grade       string
+	Term        string
+	Status      string
+	CompletedOn time.Time
+}
+
+var (
+	// Sisu course codes: TIES454, TJTS5012, TJTSM51, KOGS1001, CS101 ...
+	courseLineRe = regexp.MustCompile(`^\s*([A-ZÅÄÖ]{2,8}\d{2,5}[A-Z]?)\b[\s:;,\-–]+(.+)$`)
+
+	creditsRe  = regexp.MustCompile(`(?i)\b(\d{1,3}(?:[.,]\d{1,2})?)\s*(?:op|cr|ects|ov|credits?)\b\.?`)
+	fiDateRe   = regexp.MustCompile(`\b(\d{1,2})\.(\d{1,2})\.(\d{4})\b`)
+	isoDateRe  = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	termRe     = regexp.MustCompile(`(?i)\b(autumn|fall|spring|summer|syksy|kevät|kesä)\s+(\d{4})\b`)
+	gradeRe    = regexp.MustCompile(`(?i)^(?:[0-5]|pass(?:ed)?|fail(?:ed)?|hyv\.?|hyväksytty|hyl\.?|hylätty|approved|rejected)$`)
+	numberRe   = regexp.MustCompile(`^\d{1,3}(?:[.,]\d{1,2})?$`)
+	progressRe = regexp.MustCompile(`(?i)\b(?:in progress|ongoing|enrolled|kesken)\b`)
+)
+
+// parseTranscriptCourses runs the deterministic parser over JYU/Sisu-style
+// transcript text. Lines that do not start with a course code, or that carry
+// none of credits, grade or date, are ignored.
+func parseTranscriptCourses(text string) []parsedCourse {
+	var courses []parsedCourse
+	index := make(map[string]int)
+
+	for _, line := range strings.Split(text, "\n") {
+		course, ok := parseCourseLine(line)
+		if !ok {
+			continue
+		}
+
+		// Repeated attempts: keep the latest one, unless it failed and an
+		// earlier attempt was completed.
+		if i, seen := index[course.Code]; seen {
+			prev := courses[i]
+			if course.Status == courseStatusFailed && prev.Status == courseStatusCompleted {
+				continue
+			}
+			courses[i] = course
+			continue
+		}
+		index[course.Code] = len(courses)
+		courses = append(courses, course)
+	}
+	return courses
+}
+
+// parseCourseLine parses a single transcript row such as
+// "TIES454 Agent Technologies for Developers 5 op 4 12.05.2023".
+func parseCourseLine(line string) (parsedCourse, bool) {
+	m := courseLineRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return parsedCourse{}, false
+	}
+
+	course := parsedCourse{Code: strings.ToUpper(m[1])}
+	rest := m[2]
+	nameEnd := len(rest)
+
+	// Explicit term ("Autumn 2023")
+	if loc := termRe.FindStringSubmatchIndex(rest); loc != nil {
+		course.Term = normalizeTerm(rest[loc[2]:loc[3]], rest[loc[4]:loc[5]])
+		nameEnd = min(nameEnd, loc[0])
+	}
+
+	// Completion date
+	if loc := fiDateRe.FindStringSubmatchIndex(rest); loc != nil {
+		course.CompletedOn = parseDateParts(rest[loc[6]:loc[7]], rest[loc[4]:loc[5]], rest[loc[2]:loc[3]])
+		nameEnd = min(nameEnd, loc[0])
+	} else if loc := isoDateRe.FindStringSubmatchIndex(rest); loc != nil {
+		course.CompletedOn = parseDateParts(rest[loc[2]:loc[3]], rest[loc[4]:loc[5]], rest[loc[6]:loc[7]])
+		nameEnd = min(nameEnd, loc[0])
+	}
+
+	// Credits with a unit ("5 op", "5,0 cr", "5 ECTS"), grade follows it
+	gradeSearch := ""
+	if loc := creditsRe.FindStringSubmatchIndex(rest); loc != nil {
+		course.Credits = parseDecimal(rest[loc[2]:loc[3]])
+		gradeSearch = rest[loc[1]:]
+		nameEnd = min(nameEnd, loc[0])
+	}
+
+	if gradeSearch != "" {
+		for _, tok := range strings.Fields(gradeSearch) {
+			if gradeRe.MatchString(tok) {
+				course.Grade = normalizeGrade(tok)
+				break
+			}
+		}
+	}
+
+	name := rest[:nameEnd]
+	if gradeSearch == "" {
+		// Tabular rows without units: "<name> <credits> <grade> [date]"
+		head := strings.Fields(name)
+		if n := len(head); n >= 3 && numberRe.MatchString(head[n-2]) && gradeRe.MatchString(head[n-1]) {
+			course.Credits = parseDecimal(head[n-2])
+			course.Grade = normalizeGrade(head[n-1])
+			name = strings.Join(head[:n-2], " ")
+		} else if n >= 2 && gradeRe.MatchString(head[n-1]) && !course.CompletedOn.IsZero() {
+			course.Grade = normalizeGrade(head[n-1])
+			name = strings.Join(head[:n-1], " ")
+		}
+	}
+
+	course.Name = cleanCourseName(name)
+	if course.Name == "" {
+		return parsedCourse{}, false
+	}
+	if course.Credits == 0 && course.Grade == "" && course.CompletedOn.IsZero() {
+		return parsedCourse{}, false
+	}
+
+	if course.Term == "" && !course.CompletedOn.IsZero() {
+		course.Term = termForDate(course.CompletedOn)
+	}
+
+	switch {
+	case progressRe.MatchString(rest):
+		course.Status = courseStatusInProgress
+	case isFailingGrade(course.Grade):
+		course.Status = courseStatusFailed
+	default:
+		course.Status = courseStatusCompleted
+	}
+	return course, true
+}
+
+// normalizeGrade maps Finnish and English pass/fail words onto "pass"/"fail".
+func normalizeGrade(g string) string {
+	g = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(g), "."))
+	switch g {
+	case "pass", "passed", "hyv", "hyväksytty", "approved":
+		return "pass"
+	case "fail", "failed", "hyl", "hylätty", "rejected":
+		return "fail"
+	}
+	return g
+}
+
+func isFailingGrade(g string) bool {
+	return g == "0" || g == "fail"
+}
+
+// termForDate maps a completion date onto the Finnish academic calendar:
+// August–December is the autumn term, January–July the spring term.
+func termForDate(d time.Time) string {
+	if d.Month() >= time.August {
+		return fmt.Sprintf("Autumn %d", d.Year())
+	}
+	return fmt.Sprintf("Spring %d", d.Year())
+}
+
+func normalizeTerm(season, year string) string {
+	switch strings.ToLower(season) {
+	case "autumn", "fall", "syksy":
+		return "Autumn " + year
+	case "summer", "kesä":
+		return "Summer " + year
+	default:
+		return "Spring " + year
+	}
+}
+
+func parseDateParts(year, month, day string) time.Time {
+	d, err := time.Parse("2006-1-2", fmt.Sprintf("%s-%s-%s", year, strings.TrimLeft(month, "0"), strings.TrimLeft(day, "0")))
+	if err != nil {
+		return time.Time{}
+	}
+	return d
+}
+
+func parseDecimal(s string) float64 {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func cleanCourseName(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.Trim(s, " ,;:-–|")
+}
+
+// -----------------------------------------------------------------------------
+// LLM FALLBACK + PERSISTENCE
+// -----------------------------------------------------------------------------
+
+// parseTranscript returns the course records found in text, trying the
+// heuristic parser first and only asking the LLM when it finds nothing.
+func (s *Server) parseTranscript(ctx context.Context, text string) ([]parsedCourse, string, error) {
+	if courses := parseTranscriptCourses(text); len(courses) > 0 {
+		return courses, transcriptParserHeuristic, nil
+	}
+
+	messages := []aiMessage{
+		{
+			Role: "system",
+			Content: `You are a data extraction assistant. Read the academic transcript and return a JSON object with a single key "courses".
+Each item must have:
+- "code" (string, course code as printed)
+- "name" (string)
+- "credits" (number, ECTS; 0 if unknown)
+- "grade" (string, as printed)
+- "term" (string such as "Autumn 2023", empty if unknown)
+- "status" ("completed", "failed" or "in_progress")
+- "completed_on" (string YYYY-MM-DD, empty if unknown)`,
+		},
+		{Role: "user", Content: text},
+	}
+
+	raw, err := s.llm.ChatJSON(ctx, messages)
+	if err != nil {
+		return nil, transcriptParserLLM, err
+	}
+
+	var result struct {
+		Courses []struct {
+			Code        string  `json:"code"`
+			Name        string  `json:"name"`
+			Credits     float64 `json:"credits"`
+			Grade       string  `json:"grade"`
+			Term        string  `json:"term"`
+			Status      string  `json:"status"`
+			CompletedOn string  `json:"completed_on"`
+		} `json:"courses"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, transcriptParserLLM, fmt.Errorf("invalid transcript JSON from %s: %w", s.llm.Name(), err)
+	}
+
+	courses := make([]parsedCourse, 0, len(result.Courses))
+	for _, r := range result.Courses {
+		course := parsedCourse{
+			Code:    strings.ToUpper(strings.TrimSpace(r.Code)),
+			Name:    cleanCourseName(r.Name),
+			Credits: r.Credits,
+			Grade:   normalizeGrade(r.Grade),
+			Term:    strings.TrimSpace(r.Term),
+			Status:  strings.ToLower(strings.TrimSpace(r.Status)),
+		}
+		if course.Code == "" || course.Name == "" {
+			continue
+		}
+		if d, err := time.Parse("2006-01-02", strings.TrimSpace(r.CompletedOn)); err == nil {
+			course.CompletedOn = d
+		}
+		if course.Term == "" && !course.CompletedOn.IsZero() {
+			course.Term = termForDate(course.CompletedOn)
+		}
+		switch course.Status {
+		case courseStatusCompleted, courseStatusFailed, courseStatusInProgress:
+		default:
+			course.Status = courseStatusCompleted
+		}
+		courses = append(courses, course)
+	}
+	return courses, transcriptParserLLM, nil
+}
+
+// saveTranscriptCourses persists parsed records for a transcript. Individual
+// insert failures are logged and skipped.
+func (s *Server) saveTranscriptCourses(ctx context.Context, transcriptID int64, courses []parsedCourse, parser string) []db.TranscriptCourse {
+	saved := make([]db.TranscriptCourse, 0, len(courses))
+	for _, pc := range courses {
+		row, err := s.store.CreateTranscriptCourse(ctx, db.CreateTranscriptCourseParams{
+			TranscriptID: transcriptID,
+			CourseCode:   pc.Code,
+			CourseName:   pc.Name,
+			Credits:      sql.NullFloat64{Float64: pc.Credits, Valid: pc.Credits > 0},
+			Grade:        sqlStringOrNull(pc.Grade),
+			Term:         sqlStringOrNull(pc.Term),
+			Status:       pc.Status,
+			CompletedOn:  sql.NullTime{Time: pc.CompletedOn, Valid: !pc.CompletedOn.IsZero()},
+			Parser:       parser,
+		})
+		if err != nil {
+			log.Printf("[DB] Save transcript course %s failed: %v", pc.Code, err)
+			continue
+		}
+		saved = append(saved, row)
+	}
+	return saved
+}
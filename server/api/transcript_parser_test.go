@@ -0,0 +1,101 @@
+// server/api/transcript_parser_test.go
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const sampleSisuTranscript = `
+UNIVERSITY OF JYVÄSKYLÄ
+Transcript of Records
+Code      Name                                         Credits  Grade  Date
+TIES454   Agent Technologies for Developers            5 op     4      12.05.2023
+TJTS5010  Research Methods: Quantitative               5,0 op   hyv.   03.10.2022
+TIEP111   Programming 2                                6 op     0      20.12.2022
+TIEP111   Programming 2                                6 op     3      15.03.2023
+KOGS1001  Cognitive Science, Humans and Technology     5 ECTS   pass   2023-09-01
+TJTSM51   Information Security Management              5 op     in progress
+Total 26 op
+`
+
+func TestParseTranscriptCourses(t *testing.T) {
+	courses := parseTranscriptCourses(sampleSisuTranscript)
+	require.Len(t, courses, 5)
+
+	byCode := make(map[string]parsedCourse)
+	for _, c := range courses {
+		byCode[c.Code] = c
+	}
+
+	ties := byCode["TIES454"]
+	require.Equal(t, "Agent Technologies for Developers", ties.Name)
+	require.Equal(t, 5.0, ties.Credits)
+	require.Equal(t, "4", ties.Grade)
+	require.Equal(t, "Spring 2023", ties.Term)
+	require.Equal(t, courseStatusCompleted, ties.Status)
+	require.Equal(t, time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC), ties.CompletedOn)
+
+	require.Equal(t, "Research Methods: Quantitative", byCode["TJTS5010"].Name)
+	require.Equal(t, "pass", byCode["TJTS5010"].Grade)
+	require.Equal(t, "Autumn 2022", byCode["TJTS5010"].Term)
+
+	// The failed first attempt is replaced by the later pass
+	require.Equal(t, "3", byCode["TIEP111"].Grade)
+	require.Equal(t, courseStatusCompleted, byCode["TIEP111"].Status)
+
+	require.Equal(t, "Autumn 2023", byCode["KOGS1001"].Term)
+	require.Equal(t, courseStatusInProgress, byCode["TJTSM51"].Status)
+}
+
+func TestParseCourseLine(t *testing.T) {
+	testCases := []struct {
+		name    string
+		line    string
+		ok      bool
+		credits float64
+		grade   string
+		status  string
+	}{
+		{name: "TabularWithoutUnits", line: "TIES4530 Collective Intelligence 5 5 01.06.2021", ok: true, credits: 5, grade: "5", status: courseStatusCompleted},
+		{name: "FailedFinnish", line: "TIEA3000 Johdatus sulautettuihin 3 op hyl. 1.2.2020", ok: true, credits: 3, grade: "fail", status: courseStatusFailed},
+		{name: "NoData", line: "TIES454 Agent Technologies for Developers", ok: false},
+		{name: "NoCode", line: "Agent Technologies 5 op 4 12.05.2023", ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			course, ok := parseCourseLine(tc.line)
+			require.Equal(t, tc.ok, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tc.credits, course.Credits)
+			require.Equal(t, tc.grade, course.Grade)
+			require.Equal(t, tc.status, course.Status)
+		})
+	}
+}
+
+func TestParseTranscriptLLMFallback(t *testing.T) {
+	fake := newFakeLLMProvider(`{"courses":[{"code":"cs101","name":"Intro to CS","credits":5,"grade":"A","status":"done","completed_on":"2024-02-01"}]}`)
+	server := &Server{llm: fake}
+
+	courses, parser, err := server.parseTranscript(context.Background(), "free-form text without course rows")
+	require.NoError(t, err)
+	require.Equal(t, transcriptParserLLM, parser)
+	require.Len(t, courses, 1)
+	require.Equal(t, "CS101", courses[0].Code)
+	require.Equal(t, "Spring 2024", courses[0].Term)
+	require.Equal(t, courseStatusCompleted, courses[0].Status)
+
+	// Heuristic hits never reach the LLM
+	_, parser, err = server.parseTranscript(context.Background(), sampleSisuTranscript)
+	require.NoError(t, err)
+	require.Equal(t, transcriptParserHeuristic, parser)
+	require.Len(t, fake.Calls(), 1)
+}
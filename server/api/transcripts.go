@@ -3,11 +3,13 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"strings"
 	"time"
@@ -55,7 +57,8 @@ func extractPDFText(path string) (string, error) {
 }
 
 // ocrPDFToText runs OCR using Tesseract via gosseract to extract text from scanned PDFs.
-func ocrPDFToText(pdfPath string) (string, error) {
+// onPage, if set, is called after each page with the number of pages done and the total.
+func ocrPDFToText(pdfPath string, onPage func(done, total int)) (string, error) {
 	// Step 1: Convert the PDF pages to images (using pdftoppm from poppler-utils)
 	tempDir := os.TempDir()
 	outputPrefix := filepath.Join(tempDir, fmt.Sprintf("ocr_%d", time.Now().UnixNano()))
@@ -87,18 +90,18 @@ func ocrPDFToText(pdfPath string) (string, error) {
 	client.SetLanguage("eng")
 
 	var result strings.Builder
-	for _, file := range files {
+	for i, file := range files {
 		if err := client.SetImage(file); err != nil {
 			fmt.Printf("[DEBUG] Failed to set image %s: %v\n", file, err)
-			continue
-		}
-		text, err := client.Text()
-		if err != nil {
+		} else if text, err := client.Text(); err != nil {
 			fmt.Printf("[DEBUG] OCR failed on %s: %v\n", file, err)
-			continue
+		} else {
+			result.WriteString(text + "\n")
+			os.Remove(file) // cleanup
+		}
+		if onPage != nil {
+			onPage(i+1, len(files))
 		}
-		result.WriteString(text + "\n")
-		os.Remove(file) // cleanup
 	}
 
 	output := strings.TrimSpace(result.String())
@@ -148,7 +151,20 @@ func (s *Server) uploadTranscript(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	// 3) Extract text (try normal first; optionally OCR if enabled)
+	// 3) Extract, parse and store it in the background
+	return s.dispatchJob(c, payload.Username, jobKindTranscript, transcriptJobPayload{FilePath: path})
+}
+
+// transcriptJobPayload is the payload of a "transcript_upload" job.
+type transcriptJobPayload struct {
+	FilePath string `json:"file_path"`
+}
+
+// processTranscript extracts text from an uploaded PDF (OCR fallback),
+// parses its course records and stores the transcript. It runs as a
+// "transcript_upload" job.
+func (s *Server) processTranscript(ctx context.Context, username, path string) (fiber.Map, error) {
+	// 1) Extract text (try normal first; optionally OCR if enabled)
 	text, err := extractPDFText(path)
 	meta := map[string]any{
 		"ocr_used": false,
@@ -173,40 +189,68 @@ func (s *Server) uploadTranscript(c *fiber.Ctx) error {
 		}
 	}
 
+	if err == nil && strings.TrimSpace(text) != "" {
+		s.publishEvent(ctx, username, eventTranscriptTextExtracted, fiber.Map{"bytes": len(text), "ocr": false})
+	}
+
 	if err != nil || strings.TrimSpace(text) == "" {
 		// try OCR if allowed
 		if s.config.OCRFallbackEnabled {
-			txt, oerr := ocrPDFToText(path)
+			s.publishEvent(ctx, username, eventTranscriptOCRStarted, nil)
+			txt, oerr := ocrPDFToText(path, func(done, total int) {
+				s.publishEvent(ctx, username, eventTranscriptOCRPageDone, fiber.Map{"page": done, "pages": total})
+			})
 			if oerr == nil && strings.TrimSpace(txt) != "" {
 				text = txt
 				meta["ocr_used"] = true
+				s.publishEvent(ctx, username, eventTranscriptTextExtracted, fiber.Map{"bytes": len(text), "ocr": true})
 			}
 		}
 	}
 
-	// 4) Prepare DB payload
+	// 2) Parse course records (heuristic first, LLM fallback)
+	var parsed []parsedCourse
+	parser := ""
+	if strings.TrimSpace(text) != "" {
+		var perr error
+		parsed, parser, perr = s.parseTranscript(ctx, text)
+		if perr != nil {
+			log.Printf("[PARSE] Transcript course parsing failed for %s (%s): %v", username, filepath.Base(path), perr)
+		}
+		meta["parser"] = parser
+	}
+	meta["parsed_courses"] = len(parsed)
+	s.publishEvent(ctx, username, eventTranscriptParsed, fiber.Map{"courses": len(parsed), "parser": parser})
+
+	// 3) Prepare DB payload
 	metaJSON, _ := json.Marshal(meta)
 
-	created, err := s.store.CreateTranscript(c.Context(), db.CreateTranscriptParams{
-		UserUsername:  payload.Username,
+	created, err := s.store.CreateTranscript(ctx, db.CreateTranscriptParams{
+		UserUsername:  username,
 		FilePath:      path,
 		TextExtracted: sqlStringOrNull(text),
 		Meta:          metaJSON,
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return nil, err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"id":         created.ID,
-		"file_path":  created.FilePath,
-		"created_at": created.CreatedAt,
-		"text_bytes": len(text),
-		"ocr_used":   meta["ocr_used"],
-	})
+	// 4) Persist parsed course records
+	saved := s.saveTranscriptCourses(ctx, created.ID, parsed, parser)
+	s.publishEvent(ctx, username, eventTranscriptSaved, fiber.Map{"transcript_id": created.ID, "courses": len(saved)})
+
+	return fiber.Map{
+		"id":             created.ID,
+		"file_path":      created.FilePath,
+		"created_at":     created.CreatedAt,
+		"text_bytes":     len(text),
+		"ocr_used":       meta["ocr_used"],
+		"parsed_courses": len(saved),
+	}, nil
 }
 
 // GET /api/transcripts
+// GET /api/advisor/students/:username/transcripts
 func (s *Server) listTranscripts(c *fiber.Ctx) error {
 	// 0) Auth
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
@@ -214,7 +258,12 @@ func (s *Server) listTranscripts(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
-	items, err := s.store.ListTranscripts(c.Context(), payload.Username)
+	username := c.Params("username", payload.Username)
+	if err := s.checkReadAccess(c.Context(), payload, username); err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	items, err := s.store.ListTranscripts(c.Context(), username)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
@@ -244,9 +293,9 @@ func (s *Server) getTranscript(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	// 3) Ownership check
-	if tr.UserUsername != payload.Username {
-		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	// 3) Access check (owner, assigned advisor or admin)
+	if err := s.checkReadAccess(c.Context(), payload, tr.UserUsername); err != nil {
+		return statusErrorResponse(c, err)
 	}
 
 	// 4) Prepare a short preview without slicing a non-string type
@@ -265,3 +314,77 @@ func (s *Server) getTranscript(c *fiber.Ctx) error {
 		"text_preview": preview,
 	})
 }
+
+// transcriptCourseResponse is the API view of a parsed transcript course row.
+type transcriptCourseResponse struct {
+	ID          int64      `json:"id"`
+	CourseCode  string     `json:"course_code"`
+	CourseName  string     `json:"course_name"`
+	Credits     float64    `json:"credits"`
+	Grade       string     `json:"grade,omitempty"`
+	Term        string     `json:"term,omitempty"`
+	Status      string     `json:"status"`
+	CompletedOn *time.Time `json:"completed_on,omitempty"`
+	Parser      string     `json:"parser"`
+}
+
+func newTranscriptCourseResponse(row db.TranscriptCourse) transcriptCourseResponse {
+	resp := transcriptCourseResponse{
+		ID:         row.ID,
+		CourseCode: row.CourseCode,
+		CourseName: row.CourseName,
+		Credits:    row.Credits.Float64,
+		Grade:      row.Grade.String,
+		Term:       row.Term.String,
+		Status:     row.Status,
+		Parser:     row.Parser,
+	}
+	if row.CompletedOn.Valid {
+		resp.CompletedOn = &row.CompletedOn.Time
+	}
+	return resp
+}
+
+// GET /api/transcripts/:id/courses
+func (s *Server) listTranscriptCourses(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch transcript + access check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.checkReadAccess(c.Context(), payload, tr.UserUsername); err != nil {
+		return statusErrorResponse(c, err)
+	}
+
+	// 3) Fetch parsed rows
+	rows, err := s.store.ListTranscriptCourses(c.Context(), id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	courses := make([]transcriptCourseResponse, 0, len(rows))
+	for _, row := range rows {
+		courses = append(courses, newTranscriptCourseResponse(row))
+	}
+
+	return c.JSON(fiber.Map{
+		"transcript_id": tr.ID,
+		"count":         len(courses),
+		"courses":       courses,
+	})
+}
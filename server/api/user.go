@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/lib/pq"                                               // For Postgres-specific error handling
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc" // SQLC database package
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"      // Access and refresh tokens
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"       // Utility functions (e.g., password hashing)
 )
 
@@ -32,6 +34,7 @@ type userResponse struct {
 	Username          string    `json:"username"`            // Username of user
 	FullName          string    `json:"full_name"`           // Full name of user
 	Email             string    `json:"email"`               // Email of user
+	Role              string    `json:"role"`                // student, advisor or admin
 	PasswordChangedAt time.Time `json:"password_changed_at"` // Timestamp of last password change
 	CreatedAt         time.Time `json:"created_at"`          // Timestamp of user creation
 }
@@ -42,6 +45,7 @@ func newUserResponse(user db.User) userResponse {
 		Username:          user.Username,
 		FullName:          user.FullName,
 		Email:             user.Email,
+		Role:              user.Role,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
 	}
@@ -57,8 +61,12 @@ type loginUserRequest struct {
 // loginUserResponse represents the JSON response for login
 // @Description Login response payload
 type loginUserResponse struct {
-	AccessToken string       `json:"access_token"` // JWT/Paseto token
-	User        userResponse `json:"user"`         // User details
+	SessionID             uuid.UUID    `json:"session_id"`               // Session backing the refresh token
+	AccessToken           string       `json:"access_token"`             // JWT/Paseto token
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`  // Access token expiry
+	RefreshToken          string       `json:"refresh_token"`            // Token for POST /tokens/renew_access
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"` // Refresh token (session) expiry
+	User                  userResponse `json:"user"`                     // User details
 }
 
 // ---------------------------
@@ -128,7 +136,7 @@ func (server *Server) createUser(c *fiber.Ctx) error {
 
 // LoginUser godoc
 // @Summary      Log in a user
-// @Description  Authenticates user credentials and returns a JWT access token
+// @Description  Authenticates user credentials and returns an access token plus a refresh token bound to a new session
 // @Tags         Users
 // @Accept       json
 // @Produce      json
@@ -164,17 +172,40 @@ func (server *Server) loginUser(c *fiber.Ctx) error {
 	}
 
 	// 4. Create access token
-	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, token.TokenTypeAccess, server.config.AccessTokenDuration)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	// 5. Build response
+	// 5. Create refresh token and the session it belongs to
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, user.Role, token.TokenTypeRefresh, server.config.RefreshTokenDuration)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	session, err := server.store.CreateSession(c.Context(), db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		UserAgent:    string(c.Request().Header.UserAgent()),
+		ClientIp:     c.IP(),
+		IsBlocked:    false,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 6. Build response
 	resp := loginUserResponse{
-		AccessToken: accessToken,
-		User:        newUserResponse(user),
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
+		User:                  newUserResponse(user),
 	}
 
-	// 6. Return 200 OK with access token and user info
+	// 7. Return 200 OK with tokens and user info
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
@@ -176,7 +177,7 @@ func TestCreateUserAPI(t *testing.T) {
 			data, err := json.Marshal(tc.body)
 			require.NoError(t, err)
 
-			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(data))
+			req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewReader(data))
 			req.Header.Set("Content-Type", "application/json")
 
 			resp, err := server.app.Test(req, -1)
@@ -226,6 +227,15 @@ func TestLoginUserAPI(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Eq(user.Username)).
 					Times(1).
 					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateSessionParams) (db.Session, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.NotEmpty(t, arg.RefreshToken)
+						require.False(t, arg.IsBlocked)
+						return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken, ExpiresAt: arg.ExpiresAt}, nil
+					})
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -234,6 +244,30 @@ func TestLoginUserAPI(t *testing.T) {
 				require.NoError(t, err)
 				require.Equal(t, user.Username, resp.User.Username)
 				require.NotEmpty(t, resp.AccessToken)
+				require.NotEmpty(t, resp.RefreshToken)
+				require.NotEqual(t, uuid.Nil, resp.SessionID)
+			},
+		},
+		{
+			name: "CreateSessionError",
+			body: fiber.Map{
+				"username": user.Username,
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				hashedPassword, _ := util.HashPassword(password)
+				user.HashedPassword = hashedPassword
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 		{
@@ -316,7 +350,7 @@ func TestLoginUserAPI(t *testing.T) {
 			data, err := json.Marshal(tc.body)
 			require.NoError(t, err)
 
-			req := httptest.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+			req := httptest.NewRequest(http.MethodPost, "/api/users/login", bytes.NewReader(data))
 			req.Header.Set("Content-Type", "application/json")
 
 			resp, err := server.app.Test(req, -1)
@@ -24,3 +24,16 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	// If the field is not a string, validation fails
 	return false
 }
+
+// ---------------------------
+// Custom Course Code Validator
+// ---------------------------
+
+// validCourseCode checks that a string is a well-formed course code. It is
+// used in `validate:"required,coursecode"` tags.
+var validCourseCode validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if code, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.IsCourseCode(code)
+	}
+	return false
+}
@@ -0,0 +1,284 @@
+// server/api/websearch.go
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Search provider names accepted in WEB_SEARCH_PROVIDER. Several names
+// separated by commas fan out to all of them.
+const (
+	searchProviderBrave   = "brave"
+	searchProviderSearXNG = "searxng"
+	searchProviderJSON    = "json"
+	searchProviderFixture = "fixture"
+)
+
+const (
+	webSearchTimeout           = 15 * time.Second
+	defaultWebSearchMaxResults = 5
+)
+
+// WebResult defines a simplified search result structure returned to frontend
+type WebResult struct {
+	Title   string `json:"title"`
+	URL     string `json:"url"`
+	Snippet string `json:"snippet"`
+	Query   string `json:"query,omitempty"` // set when several queries are merged
+}
+
+// SearchProvider runs web searches.
+// Implementations must be safe for concurrent use.
+type SearchProvider interface {
+	// Name identifies the provider in logs.
+	Name() string
+
+	// Search returns at most limit results for the query, best first.
+	Search(ctx context.Context, query string, limit int) ([]WebResult, error)
+}
+
+// newSearchProvider builds the provider(s) selected by WEB_SEARCH_PROVIDER,
+// each behind the search cache and its daily quota (except the fixture).
+// It returns nil when WEB_SEARCH_ENABLED is false.
+func newSearchProvider(config util.Config, store db.Store) (SearchProvider, error) {
+	if !config.WebSearchEnabled {
+		return nil, nil
+	}
+
+	quotas, err := parseSearchQuotas(config.WebSearchDailyQuota)
+	if err != nil {
+		return nil, err
+	}
+
+	names := strings.Split(config.WebSearchProvider, ",")
+	if strings.TrimSpace(config.WebSearchProvider) == "" {
+		names = []string{searchProviderBrave}
+	}
+
+	var providers []SearchProvider
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		provider, err := newNamedSearchProvider(name, config)
+		if err != nil {
+			return nil, err
+		}
+		if name != searchProviderFixture && (config.WebSearchCacheTTL > 0 || quotas[name] > 0) {
+			provider = newCachedSearchProvider(provider, store, config.WebSearchCacheTTL, quotas[name])
+		}
+		providers = append(providers, provider)
+	}
+
+	switch len(providers) {
+	case 1:
+		return providers[0], nil
+	default:
+		return newFanOutSearchProvider(providers...), nil
+	}
+}
+
+func newNamedSearchProvider(name string, config util.Config) (SearchProvider, error) {
+	switch name {
+	case searchProviderBrave:
+		return newBraveProvider(config.BraveAPIKey, config.BraveAPIURL), nil
+	case searchProviderSearXNG:
+		if strings.TrimSpace(config.SearXNGURL) == "" {
+			return nil, fmt.Errorf("SEARXNG_URL is required for the %s search provider", searchProviderSearXNG)
+		}
+		return newSearXNGProvider(config.SearXNGURL), nil
+	case searchProviderJSON:
+		if strings.TrimSpace(config.WebSearchJSONURL) == "" {
+			return nil, fmt.Errorf("WEB_SEARCH_JSON_URL is required for the %s search provider", searchProviderJSON)
+		}
+		return newJSONSearchProvider(jsonSearchConfig{
+			URL:         config.WebSearchJSONURL,
+			APIKey:      config.WebSearchJSONAPIKey,
+			ResultsPath: config.WebSearchJSONResultsPath,
+			TitleKey:    config.WebSearchJSONTitleKey,
+			URLKey:      config.WebSearchJSONURLKey,
+			SnippetKey:  config.WebSearchJSONSnippetKey,
+		}), nil
+	case searchProviderFixture:
+		return newFixtureSearchProvider(config.WebSearchFixtureFile)
+	default:
+		return nil, fmt.Errorf("unsupported web search provider %q", name)
+	}
+}
+
+// webSearch runs the configured provider with WEB_SEARCH_MAX_RESULTS.
+func (s *Server) webSearch(ctx context.Context, query string) ([]WebResult, error) {
+	if s.search == nil {
+		return nil, errors.New("web search is disabled")
+	}
+	limit := s.config.WebSearchMaxResults
+	if limit <= 0 {
+		limit = defaultWebSearchMaxResults
+	}
+	return s.search.Search(ctx, query, limit)
+}
+
+// cleanWebResults trims the fields, drops results without a URL and stops
+// at limit (0 keeps all).
+func cleanWebResults(results []WebResult, limit int) []WebResult {
+	out := make([]WebResult, 0, len(results))
+	for _, r := range results {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		r.Title = strings.TrimSpace(r.Title)
+		r.URL = strings.TrimSpace(r.URL)
+		r.Snippet = strings.TrimSpace(r.Snippet)
+		if r.URL == "" {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// FAN-OUT
+// -----------------------------------------------------------------------------
+
+// fanOutSearchProvider queries every provider concurrently and merges the
+// results by rank (each provider's first result, then each one's second,
+// ...), keeping the first result per URL. It fails only if all providers do.
+type fanOutSearchProvider struct {
+	providers []SearchProvider
+}
+
+func newFanOutSearchProvider(providers ...SearchProvider) *fanOutSearchProvider {
+	return &fanOutSearchProvider{providers: providers}
+}
+
+func (p *fanOutSearchProvider) Name() string {
+	names := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, "+")
+}
+
+func (p *fanOutSearchProvider) Search(ctx context.Context, query string, limit int) ([]WebResult, error) {
+	lists := make([][]WebResult, len(p.providers))
+	errs := make([]error, len(p.providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range p.providers {
+		wg.Add(1)
+		go func(i int, provider SearchProvider) {
+			defer wg.Done()
+			lists[i], errs[i] = provider.Search(ctx, query, limit)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", provider.Name(), errs[i])
+				log.Printf("[WEB] %v", errs[i])
+			}
+		}(i, provider)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(p.providers) {
+		return nil, errors.Join(errs...)
+	}
+	return mergeWebResults(lists, limit), nil
+}
+
+// mergeWebResults interleaves the ranked lists and drops later results whose
+// URL (compared without scheme, "www.", fragment, tracking parameters or a
+// trailing slash) was already seen.
+func mergeWebResults(lists [][]WebResult, limit int) []WebResult {
+	out := []WebResult{}
+	seen := make(map[string]bool)
+	for rank := 0; ; rank++ {
+		more := false
+		for _, list := range lists {
+			if rank >= len(list) {
+				continue
+			}
+			more = true
+			r := list[rank]
+			key := strings.ToLower(strings.TrimSpace(r.URL))
+			if _, k, ok := canonicalScholarshipURL(r.URL); ok {
+				key = k
+			}
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, r)
+			if limit > 0 && len(out) == limit {
+				return out
+			}
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// HANDLER
+// -----------------------------------------------------------------------------
+
+// webSearchResponse is the body of GET /api/websearch.
+type webSearchResponse struct {
+	Results  []WebResult `json:"results"`
+	Provider string      `json:"provider"`
+	// Cached is true when no provider was asked, only the search cache.
+	Cached bool `json:"cached"`
+	// Quota is the number of requests left today per provider with a quota.
+	Quota map[string]int32 `json:"quota,omitempty"`
+}
+
+// handleLocalWebSearch runs the configured search provider(s)
+// GET /api/websearch?q=AI+scholarships
+func (s *Server) handleLocalWebSearch(c *fiber.Ctx) error {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing q parameter"})
+	}
+
+	if s.search == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "web search is disabled"})
+	}
+
+	ctx, info := withSearchInfo(c.Context())
+	results, err := s.webSearch(ctx, query)
+	if err != nil {
+		log.Printf("[WEB] %s search failed: %v", s.search.Name(), err)
+		status := fiber.StatusInternalServerError
+		if errors.Is(err, errSearchQuotaExhausted) {
+			status = fiber.StatusTooManyRequests
+		}
+		return c.Status(status).JSON(fiber.Map{"error": err.Error(), "quota": info.Remaining()})
+	}
+
+	return c.JSON(webSearchResponse{
+		Results:  results,
+		Provider: s.search.Name(),
+		Cached:   info.Cached(),
+		Quota:    info.Remaining(),
+	})
+}
@@ -3,60 +3,58 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/url"
-	"strings"
-	"time"
-
-	"github.com/gofiber/fiber/v2"
+	"strconv"
 )
 
-// WebResult defines a simplified search result structure returned to frontend
-type WebResult struct {
-	Title   string `json:"title"`
-	URL     string `json:"url"`
-	Snippet string `json:"snippet"`
-}
+const defaultBraveAPIURL = "https://api.search.brave.com/res/v1/web/search"
 
-// handleLocalWebSearch provides a local API endpoint proxying to Brave Search API
-// GET /api/websearch?q=AI+scholarships
-func (s *Server) handleLocalWebSearch(c *fiber.Ctx) error {
-	query := strings.TrimSpace(c.Query("q"))
-	if query == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "missing q parameter"})
-	}
+// braveProvider calls the Brave Search REST API.
+type braveProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
 
-	if s.config.BraveAPIKey == "" {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Brave API key not configured"})
+func newBraveProvider(apiKey, baseURL string) *braveProvider {
+	if baseURL == "" {
+		baseURL = defaultBraveAPIURL
 	}
-
-	results, err := fetchBraveResults(query, s.config.BraveAPIKey, s.config.BraveAPIURL, s.config.WebSearchMaxResults)
-	if err != nil {
-		log.Printf("[WEB] Brave search failed: %v", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	return &braveProvider{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: webSearchTimeout},
 	}
+}
 
-	return c.JSON(results)
+func (p *braveProvider) Name() string {
+	return searchProviderBrave
 }
 
-// fetchBraveResults calls the Brave Search REST API directly
-func fetchBraveResults(q, apiKey, baseURL string, maxResults int) ([]WebResult, error) {
-	if baseURL == "" {
-		baseURL = "https://api.search.brave.com/res/v1/web/search"
+func (p *braveProvider) Search(ctx context.Context, q string, limit int) ([]WebResult, error) {
+	if p.apiKey == "" {
+		return nil, errors.New("Brave API key not configured")
 	}
 
-	searchURL := fmt.Sprintf("%s?q=%s", baseURL, url.QueryEscape(q))
-
-	req, _ := http.NewRequest("GET", searchURL, nil)
+	params := url.Values{"q": {q}}
+	if limit > 0 {
+		params.Set("count", strconv.Itoa(limit))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Subscription-Token", apiKey) // ✅ Brave requires this header (not Authorization)
+	req.Header.Set("X-Subscription-Token", p.apiKey) // ✅ Brave requires this header (not Authorization)
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := p.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("brave request failed: %v", err)
 	}
@@ -83,16 +81,10 @@ func fetchBraveResults(q, apiKey, baseURL string, maxResults int) ([]WebResult,
 	}
 
 	results := make([]WebResult, 0, len(data.Web.Results))
-	for i, r := range data.Web.Results {
-		if maxResults > 0 && i >= maxResults {
-			break
-		}
-		results = append(results, WebResult{
-			Title:   strings.TrimSpace(r.Title),
-			URL:     strings.TrimSpace(r.URL),
-			Snippet: strings.TrimSpace(r.Description),
-		})
+	for _, r := range data.Web.Results {
+		results = append(results, WebResult{Title: r.Title, URL: r.URL, Snippet: r.Description})
 	}
+	results = cleanWebResults(results, limit)
 
 	if len(results) == 0 {
 		log.Printf("[WEB] Brave returned no results for query: %s", q)
@@ -0,0 +1,227 @@
+// server/api/websearch_cache.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// errSearchQuotaExhausted is returned when a provider's daily quota is used
+// up and nothing is cached for the query.
+var errSearchQuotaExhausted = errors.New("daily web search quota exhausted")
+
+// parseSearchQuotas reads WEB_SEARCH_DAILY_QUOTA ("brave=60,searxng=500").
+func parseSearchQuotas(s string) (map[string]int32, error) {
+	quotas := make(map[string]int32)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if !ok || err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid WEB_SEARCH_DAILY_QUOTA entry %q (want provider=count)", part)
+		}
+		quotas[strings.ToLower(strings.TrimSpace(name))] = int32(n)
+	}
+	return quotas, nil
+}
+
+// normalizeSearchQuery is the cache key of a query: lower-case, single spaces.
+func normalizeSearchQuery(q string) string {
+	return strings.ToLower(strings.Join(strings.Fields(q), " "))
+}
+
+// searchDay is the quota day of t (UTC).
+func searchDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// -----------------------------------------------------------------------------
+// SEARCH INFO
+// -----------------------------------------------------------------------------
+
+// searchInfo collects how a search was answered, across fan-out providers.
+type searchInfo struct {
+	mu     sync.Mutex
+	fresh  int
+	cached int
+	// quota is the number of requests left today per provider with a quota.
+	quota map[string]int32
+}
+
+type searchInfoContextKey struct{}
+
+// withSearchInfo returns a context whose searches report into the returned info.
+func withSearchInfo(ctx context.Context) (context.Context, *searchInfo) {
+	info := &searchInfo{quota: make(map[string]int32)}
+	return context.WithValue(ctx, searchInfoContextKey{}, info), info
+}
+
+func searchInfoFrom(ctx context.Context) *searchInfo {
+	info, _ := ctx.Value(searchInfoContextKey{}).(*searchInfo)
+	return info
+}
+
+func (i *searchInfo) answered(cached bool) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if cached {
+		i.cached++
+	} else {
+		i.fresh++
+	}
+}
+
+func (i *searchInfo) setRemaining(provider string, remaining int32) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.quota[provider] = max(remaining, 0)
+}
+
+// Cached reports whether every answering provider was served from the cache.
+func (i *searchInfo) Cached() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.cached > 0 && i.fresh == 0
+}
+
+// Remaining returns a copy of the quota left per provider.
+func (i *searchInfo) Remaining() map[string]int32 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := make(map[string]int32, len(i.quota))
+	for k, v := range i.quota {
+		out[k] = v
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// CACHING PROVIDER
+// -----------------------------------------------------------------------------
+
+// cachedSearchProvider wraps a provider with the web_search_cache table and
+// its daily quota. A fresh cache entry answers without a request; otherwise
+// one request is counted against the quota. With the quota used up, or when
+// the provider fails, an expired entry is still served if there is one.
+type cachedSearchProvider struct {
+	provider   SearchProvider
+	store      db.Store
+	ttl        time.Duration // 0 disables the cache
+	dailyQuota int32         // 0 means no limit
+}
+
+func newCachedSearchProvider(provider SearchProvider, store db.Store, ttl time.Duration, dailyQuota int32) *cachedSearchProvider {
+	return &cachedSearchProvider{provider: provider, store: store, ttl: ttl, dailyQuota: dailyQuota}
+}
+
+func (p *cachedSearchProvider) Name() string {
+	return p.provider.Name()
+}
+
+func (p *cachedSearchProvider) Search(ctx context.Context, q string, limit int) ([]WebResult, error) {
+	info := searchInfoFrom(ctx)
+	name := p.provider.Name()
+	key := normalizeSearchQuery(q)
+	now := time.Now()
+
+	var stale []WebResult
+	if p.ttl > 0 {
+		entry, err := p.store.GetWebSearchCache(ctx, db.GetWebSearchCacheParams{Provider: name, QueryKey: key})
+		switch {
+		case err == nil:
+			var results []WebResult
+			if err := json.Unmarshal(entry.Results, &results); err != nil {
+				log.Printf("[WEB] Invalid cached %s results for %q: %v", name, key, err)
+				break
+			}
+			if now.Before(entry.ExpiresAt) {
+				p.reportRemaining(ctx, info, now)
+				info.answered(true)
+				return cleanWebResults(results, limit), nil
+			}
+			stale = results
+		case !errors.Is(err, sql.ErrNoRows):
+			log.Printf("[WEB] Read %s search cache failed: %v", name, err)
+		}
+	}
+
+	if p.dailyQuota > 0 {
+		used, err := p.store.ClaimWebSearchQuota(ctx, db.ClaimWebSearchQuotaParams{
+			Provider:   name,
+			Day:        searchDay(now),
+			DailyLimit: p.dailyQuota,
+		})
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			info.setRemaining(name, 0)
+			if stale != nil {
+				log.Printf("[WEB] %s quota exhausted, serving expired cache for %q", name, key)
+				info.answered(true)
+				return cleanWebResults(stale, limit), nil
+			}
+			return nil, fmt.Errorf("%s: %w", name, errSearchQuotaExhausted)
+		case err != nil:
+			return nil, fmt.Errorf("claim %s search quota: %w", name, err)
+		}
+		info.setRemaining(name, p.dailyQuota-used)
+	}
+
+	results, err := p.provider.Search(ctx, q, limit)
+	if err != nil {
+		if stale != nil {
+			log.Printf("[WEB] %s search failed, serving expired cache for %q: %v", name, key, err)
+			info.answered(true)
+			return cleanWebResults(stale, limit), nil
+		}
+		return nil, err
+	}
+
+	if p.ttl > 0 {
+		data, _ := json.Marshal(results)
+		if _, err := p.store.UpsertWebSearchCache(ctx, db.UpsertWebSearchCacheParams{
+			Provider:  name,
+			QueryKey:  key,
+			Query:     q,
+			Results:   data,
+			ExpiresAt: now.Add(p.ttl),
+		}); err != nil {
+			log.Printf("[WEB] Save %s search cache failed: %v", name, err)
+		}
+	}
+	info.answered(false)
+	return results, nil
+}
+
+// reportRemaining records the quota left today when the answer used none.
+func (p *cachedSearchProvider) reportRemaining(ctx context.Context, info *searchInfo, now time.Time) {
+	if info == nil || p.dailyQuota == 0 {
+		return
+	}
+	used, err := p.store.GetWebSearchUsage(ctx, db.GetWebSearchUsageParams{Provider: p.provider.Name(), Day: searchDay(now)})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[WEB] Read %s search usage failed: %v", p.provider.Name(), err)
+		return
+	}
+	info.setRemaining(p.provider.Name(), p.dailyQuota-used)
+}
@@ -0,0 +1,196 @@
+// server/api/websearch_cache_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchQuotas(t *testing.T) {
+	quotas, err := parseSearchQuotas(" Brave=60, searxng=500 ,")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int32{"brave": 60, "searxng": 500}, quotas)
+
+	quotas, err = parseSearchQuotas("")
+	require.NoError(t, err)
+	require.Empty(t, quotas)
+
+	for _, bad := range []string{"brave", "brave=x", "brave=-1"} {
+		_, err = parseSearchQuotas(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func cachedResults(t *testing.T, results ...WebResult) json.RawMessage {
+	data, err := json.Marshal(results)
+	require.NoError(t, err)
+	return data
+}
+
+func TestCachedSearchProvider(t *testing.T) {
+	fresh := []WebResult{{Title: "Fresh", URL: "https://fresh.example.org"}}
+	old := WebResult{Title: "Old", URL: "https://old.example.org"}
+	cacheKey := db.GetWebSearchCacheParams{Provider: "brave", QueryKey: "ai scholarships"}
+
+	testCases := []struct {
+		name        string
+		providerErr error
+		buildStubs  func(store *mockdb.MockStore)
+		check       func(t *testing.T, results []WebResult, err error, info *searchInfo)
+	}{
+		{
+			name: "FreshCacheHit",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Eq(cacheKey)).Times(1).
+					Return(db.WebSearchCache{Results: cachedResults(t, old), ExpiresAt: time.Now().Add(time.Hour)}, nil)
+				store.EXPECT().GetWebSearchUsage(gomock.Any(), gomock.Any()).Times(1).Return(int32(4), nil)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().UpsertWebSearchCache(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.NoError(t, err)
+				require.Equal(t, []WebResult{old}, results)
+				require.True(t, info.Cached())
+				require.Equal(t, map[string]int32{"brave": 6}, info.Remaining())
+			},
+		},
+		{
+			name: "Miss",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).Return(db.WebSearchCache{}, sql.ErrNoRows)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.ClaimWebSearchQuotaParams) (int32, error) {
+						require.Equal(t, "brave", arg.Provider)
+						require.Equal(t, int32(10), arg.DailyLimit)
+						require.Equal(t, searchDay(time.Now()), arg.Day)
+						return 3, nil
+					})
+				store.EXPECT().UpsertWebSearchCache(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.UpsertWebSearchCacheParams) (db.WebSearchCache, error) {
+						require.Equal(t, "ai scholarships", arg.QueryKey)
+						require.Equal(t, "AI  Scholarships", arg.Query)
+						require.JSONEq(t, string(cachedResults(t, fresh...)), string(arg.Results))
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Minute)
+						return db.WebSearchCache{}, nil
+					})
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.NoError(t, err)
+				require.Equal(t, fresh, results)
+				require.False(t, info.Cached())
+				require.Equal(t, map[string]int32{"brave": 7}, info.Remaining())
+			},
+		},
+		{
+			name: "QuotaExhaustedServesExpired",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.WebSearchCache{Results: cachedResults(t, old), ExpiresAt: time.Now().Add(-time.Hour)}, nil)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).Return(int32(0), sql.ErrNoRows)
+				store.EXPECT().UpsertWebSearchCache(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.NoError(t, err)
+				require.Equal(t, []WebResult{old}, results)
+				require.True(t, info.Cached())
+				require.Equal(t, map[string]int32{"brave": 0}, info.Remaining())
+			},
+		},
+		{
+			name: "QuotaExhaustedNothingCached",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).Return(db.WebSearchCache{}, sql.ErrNoRows)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).Return(int32(0), sql.ErrNoRows)
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.ErrorIs(t, err, errSearchQuotaExhausted)
+				require.Nil(t, results)
+			},
+		},
+		{
+			name:        "ProviderErrorServesExpired",
+			providerErr: errors.New("brave returned 503"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.WebSearchCache{Results: cachedResults(t, old), ExpiresAt: time.Now().Add(-time.Hour)}, nil)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).Return(int32(1), nil)
+				store.EXPECT().UpsertWebSearchCache(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.NoError(t, err)
+				require.Equal(t, []WebResult{old}, results)
+			},
+		},
+		{
+			name:        "ProviderError",
+			providerErr: errors.New("brave returned 503"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).Return(db.WebSearchCache{}, sql.ErrNoRows)
+				store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).Return(int32(1), nil)
+			},
+			check: func(t *testing.T, results []WebResult, err error, info *searchInfo) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			provider := newCachedSearchProvider(stubSearchProvider{name: "brave", results: fresh, err: tc.providerErr}, store, time.Hour, 10)
+			ctx, info := withSearchInfo(context.Background())
+			results, err := provider.Search(ctx, "AI  Scholarships", 5)
+			tc.check(t, results, err, info)
+		})
+	}
+}
+
+func TestWebSearchAPICached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).
+		Return(db.WebSearchCache{Results: cachedResults(t, WebResult{Title: "A", URL: "https://a.example.org"}), ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	store.EXPECT().GetWebSearchUsage(gomock.Any(), gomock.Any()).Times(1).Return(int32(0), sql.ErrNoRows)
+
+	server := newFiberTestServer(t, store)
+	server.search = newCachedSearchProvider(stubSearchProvider{name: "brave"}, store, time.Hour, 60)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/websearch?q=grants", nil)
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var got webSearchResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.True(t, got.Cached)
+	require.Len(t, got.Results, 1)
+	require.Equal(t, map[string]int32{"brave": 60}, got.Quota)
+
+	// Quota used up and nothing cached: 429
+	store.EXPECT().GetWebSearchCache(gomock.Any(), gomock.Any()).Times(1).Return(db.WebSearchCache{}, sql.ErrNoRows)
+	store.EXPECT().ClaimWebSearchQuota(gomock.Any(), gomock.Any()).Times(1).Return(int32(0), sql.ErrNoRows)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/websearch?q=other", nil)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+}
@@ -0,0 +1,75 @@
+// server/api/websearch_fixture.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fixtureSearchProvider answers from a JSON file, for offline development
+// and tests. The file is either an array of results returned for every
+// query, or
+//
+//	{"queries": {"<query>": [...]}, "default": [...]}
+//
+// where queries are matched case-insensitively and "default" answers the rest.
+type fixtureSearchProvider struct {
+	queries  map[string][]WebResult
+	fallback []WebResult
+}
+
+func newFixtureSearchProvider(path string) (*fixtureSearchProvider, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("WEB_SEARCH_FIXTURE_FILE is required for the %s search provider", searchProviderFixture)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read search fixture: %w", err)
+	}
+	return parseSearchFixture(data)
+}
+
+func parseSearchFixture(data []byte) (*fixtureSearchProvider, error) {
+	p := &fixtureSearchProvider{queries: make(map[string][]WebResult)}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &p.fallback); err != nil {
+			return nil, fmt.Errorf("invalid search fixture: %w", err)
+		}
+		return p, nil
+	}
+
+	var file struct {
+		Queries map[string][]WebResult `json:"queries"`
+		Default []WebResult            `json:"default"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid search fixture: %w", err)
+	}
+	for q, results := range file.Queries {
+		p.queries[fixtureQueryKey(q)] = results
+	}
+	p.fallback = file.Default
+	return p, nil
+}
+
+func fixtureQueryKey(q string) string {
+	return strings.ToLower(strings.Join(strings.Fields(q), " "))
+}
+
+func (p *fixtureSearchProvider) Name() string {
+	return searchProviderFixture
+}
+
+func (p *fixtureSearchProvider) Search(_ context.Context, q string, limit int) ([]WebResult, error) {
+	results, ok := p.queries[fixtureQueryKey(q)]
+	if !ok {
+		results = p.fallback
+	}
+	return cleanWebResults(results, limit), nil
+}
@@ -0,0 +1,133 @@
+// server/api/websearch_json.go
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// jsonSearchConfig describes a generic JSON-over-HTTP search API.
+type jsonSearchConfig struct {
+	// URL is requested with GET. "{query}" and "{limit}" are replaced with
+	// the escaped query and the limit; without "{query}", q=<query> is added.
+	URL string
+	// APIKey, when set, is sent as "Authorization: Bearer <key>".
+	APIKey string
+	// ResultsPath is the dot path of the results array ("data.items").
+	// Empty means the response is the array itself.
+	ResultsPath string
+	// Keys (dot paths) of the fields inside each result.
+	TitleKey   string
+	URLKey     string
+	SnippetKey string
+}
+
+// jsonSearchProvider adapts any search API that answers with JSON.
+type jsonSearchProvider struct {
+	config     jsonSearchConfig
+	httpClient *http.Client
+}
+
+func newJSONSearchProvider(config jsonSearchConfig) *jsonSearchProvider {
+	if config.TitleKey == "" {
+		config.TitleKey = "title"
+	}
+	if config.URLKey == "" {
+		config.URLKey = "url"
+	}
+	if config.SnippetKey == "" {
+		config.SnippetKey = "snippet"
+	}
+	return &jsonSearchProvider{
+		config:     config,
+		httpClient: &http.Client{Timeout: webSearchTimeout},
+	}
+}
+
+func (p *jsonSearchProvider) Name() string {
+	return searchProviderJSON
+}
+
+func (p *jsonSearchProvider) Search(ctx context.Context, q string, limit int) ([]WebResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.requestURL(q, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.APIKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("json search request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return nil, fmt.Errorf("json search returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data any
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid search JSON: %v", err)
+	}
+
+	items, ok := jsonPath(data, p.config.ResultsPath).([]any)
+	if !ok {
+		return nil, fmt.Errorf("no results array at %q", p.config.ResultsPath)
+	}
+
+	results := make([]WebResult, 0, len(items))
+	for _, item := range items {
+		results = append(results, WebResult{
+			Title:   jsonString(jsonPath(item, p.config.TitleKey)),
+			URL:     jsonString(jsonPath(item, p.config.URLKey)),
+			Snippet: jsonString(jsonPath(item, p.config.SnippetKey)),
+		})
+	}
+	return cleanWebResults(results, limit), nil
+}
+
+func (p *jsonSearchProvider) requestURL(q string, limit int) string {
+	u := p.config.URL
+	if strings.Contains(u, "{query}") {
+		u = strings.ReplaceAll(u, "{query}", url.QueryEscape(q))
+	} else {
+		sep := "?"
+		if strings.Contains(u, "?") {
+			sep = "&"
+		}
+		u += sep + "q=" + url.QueryEscape(q)
+	}
+	return strings.ReplaceAll(u, "{limit}", strconv.Itoa(limit))
+}
+
+// jsonPath walks a decoded JSON value along a dot path of object keys.
+// An empty path returns v.
+func jsonPath(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+	return v
+}
+
+func jsonString(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,69 @@
+// server/api/websearch_searxng.go
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// searxngProvider queries a SearXNG instance through its JSON API
+// (GET /search?format=json). The instance must have "json" among its
+// enabled search.formats.
+type searxngProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newSearXNGProvider(baseURL string) *searxngProvider {
+	return &searxngProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: webSearchTimeout},
+	}
+}
+
+func (p *searxngProvider) Name() string {
+	return searchProviderSearXNG
+}
+
+func (p *searxngProvider) Search(ctx context.Context, q string, limit int) ([]WebResult, error) {
+	params := url.Values{"q": {q}, "format": {"json"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("searxng request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return nil, fmt.Errorf("searxng returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var data struct {
+		Results []struct {
+			Title   string `json:"title"`
+			URL     string `json:"url"`
+			Content string `json:"content"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid searxng JSON: %v", err)
+	}
+
+	results := make([]WebResult, 0, len(data.Results))
+	for _, r := range data.Results {
+		results = append(results, WebResult{Title: r.Title, URL: r.URL, Snippet: r.Content})
+	}
+	return cleanWebResults(results, limit), nil
+}
@@ -0,0 +1,240 @@
+// server/api/websearch_test.go
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSearchProvider returns fixed results or an error.
+type stubSearchProvider struct {
+	name    string
+	results []WebResult
+	err     error
+}
+
+func (p stubSearchProvider) Name() string { return p.name }
+
+func (p stubSearchProvider) Search(_ context.Context, _ string, limit int) ([]WebResult, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return cleanWebResults(p.results, limit), nil
+}
+
+func writeSearchFixture(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "search.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestNewSearchProvider(t *testing.T) {
+	fixture := writeSearchFixture(t, `[]`)
+
+	testCases := []struct {
+		name     string
+		config   util.Config
+		wantName string
+		wantErr  bool
+	}{
+		{name: "Disabled", config: util.Config{WebSearchProvider: "brave"}},
+		{name: "DefaultBrave", config: util.Config{WebSearchEnabled: true}, wantName: "brave"},
+		{name: "SearXNG", config: util.Config{WebSearchEnabled: true, WebSearchProvider: "SearXNG", SearXNGURL: "http://localhost:8888"}, wantName: "searxng"},
+		{name: "SearXNGWithoutURL", config: util.Config{WebSearchEnabled: true, WebSearchProvider: "searxng"}, wantErr: true},
+		{name: "JSONWithoutURL", config: util.Config{WebSearchEnabled: true, WebSearchProvider: "json"}, wantErr: true},
+		{name: "FixtureMissingFile", config: util.Config{WebSearchEnabled: true, WebSearchProvider: "fixture", WebSearchFixtureFile: "/nonexistent.json"}, wantErr: true},
+		{
+			name:     "FanOut",
+			config:   util.Config{WebSearchEnabled: true, WebSearchProvider: "brave, fixture, brave", WebSearchFixtureFile: fixture},
+			wantName: "brave+fixture",
+		},
+		{name: "Unknown", config: util.Config{WebSearchEnabled: true, WebSearchProvider: "altavista"}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := newSearchProvider(tc.config, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.wantName == "" {
+				require.Nil(t, provider)
+				return
+			}
+			require.Equal(t, tc.wantName, provider.Name())
+		})
+	}
+}
+
+func TestBraveProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "secret", r.Header.Get("X-Subscription-Token"))
+		require.Equal(t, "ai scholarships", r.URL.Query().Get("q"))
+		w.Write([]byte(`{"web": {"results": [
+			{"title": " A ", "url": "https://a.example.org", "description": "first"},
+			{"title": "No URL", "url": ""},
+			{"title": "B", "url": "https://b.example.org", "description": "second"},
+			{"title": "C", "url": "https://c.example.org"}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	results, err := newBraveProvider("secret", srv.URL).Search(context.Background(), "ai scholarships", 2)
+	require.NoError(t, err)
+	require.Equal(t, []WebResult{
+		{Title: "A", URL: "https://a.example.org", Snippet: "first"},
+		{Title: "B", URL: "https://b.example.org", Snippet: "second"},
+	}, results)
+
+	_, err = newBraveProvider("", srv.URL).Search(context.Background(), "ai scholarships", 2)
+	require.Error(t, err)
+}
+
+func TestSearXNGProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/search", r.URL.Path)
+		require.Equal(t, "json", r.URL.Query().Get("format"))
+		w.Write([]byte(`{"results": [{"title": "A", "url": "https://a.example.org", "content": "about a"}]}`))
+	}))
+	defer srv.Close()
+
+	results, err := newSearXNGProvider(srv.URL+"/").Search(context.Background(), "grants", 5)
+	require.NoError(t, err)
+	require.Equal(t, []WebResult{{Title: "A", URL: "https://a.example.org", Snippet: "about a"}}, results)
+}
+
+func TestJSONSearchProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		require.Equal(t, "phd grants", r.URL.Query().Get("query"))
+		require.Equal(t, "3", r.URL.Query().Get("n"))
+		w.Write([]byte(`{"data": {"items": [
+			{"name": "A", "link": {"href": "https://a.example.org"}, "summary": "about a"},
+			{"name": "B", "link": {"href": "https://b.example.org"}}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	provider := newJSONSearchProvider(jsonSearchConfig{
+		URL:         srv.URL + "/search?query={query}&n={limit}",
+		APIKey:      "key",
+		ResultsPath: "data.items",
+		TitleKey:    "name",
+		URLKey:      "link.href",
+		SnippetKey:  "summary",
+	})
+	results, err := provider.Search(context.Background(), "phd grants", 3)
+	require.NoError(t, err)
+	require.Equal(t, []WebResult{
+		{Title: "A", URL: "https://a.example.org", Snippet: "about a"},
+		{Title: "B", URL: "https://b.example.org"},
+	}, results)
+
+	provider.config.ResultsPath = "missing"
+	_, err = provider.Search(context.Background(), "phd grants", 3)
+	require.Error(t, err)
+
+	require.Equal(t, "http://x/s?a=1&q=a+b", newJSONSearchProvider(jsonSearchConfig{URL: "http://x/s?a=1"}).requestURL("a b", 5))
+}
+
+func TestFixtureSearchProvider(t *testing.T) {
+	provider, err := newFixtureSearchProvider(writeSearchFixture(t, `{
+		"queries": {"AI  Scholarships": [{"title": "A", "url": "https://a.example.org"}]},
+		"default": [{"title": "D", "url": "https://d.example.org"}]
+	}`))
+	require.NoError(t, err)
+
+	results, err := provider.Search(context.Background(), "ai scholarships", 5)
+	require.NoError(t, err)
+	require.Equal(t, "A", results[0].Title)
+
+	results, err = provider.Search(context.Background(), "something else", 5)
+	require.NoError(t, err)
+	require.Equal(t, "D", results[0].Title)
+
+	provider, err = parseSearchFixture([]byte(`[{"title": "X", "url": "https://x.example.org"}, {"title": "Y", "url": "https://y.example.org"}]`))
+	require.NoError(t, err)
+	results, err = provider.Search(context.Background(), "anything", 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	_, err = parseSearchFixture([]byte(`{"queries": [`))
+	require.Error(t, err)
+}
+
+func TestFanOutSearchProvider(t *testing.T) {
+	brave := stubSearchProvider{name: "brave", results: []WebResult{
+		{Title: "A", URL: "https://www.a.example.org/grants/"},
+		{Title: "B", URL: "https://b.example.org"},
+		{Title: "C", URL: "https://c.example.org"},
+	}}
+	searxng := stubSearchProvider{name: "searxng", results: []WebResult{
+		{Title: "A copy", URL: "http://a.example.org/grants?utm_source=x"},
+		{Title: "D", URL: "https://d.example.org"},
+	}}
+	broken := stubSearchProvider{name: "json", err: errors.New("timeout")}
+
+	results, err := newFanOutSearchProvider(brave, searxng, broken).Search(context.Background(), "grants", 0)
+	require.NoError(t, err)
+	titles := make([]string, 0, len(results))
+	for _, r := range results {
+		titles = append(titles, r.Title)
+	}
+	require.Equal(t, []string{"A", "B", "D", "C"}, titles)
+
+	results, err = newFanOutSearchProvider(brave, searxng).Search(context.Background(), "grants", 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	_, err = newFanOutSearchProvider(broken, broken).Search(context.Background(), "grants", 5)
+	require.Error(t, err)
+}
+
+func TestWebSearchAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newFiberTestServer(t, mockdb.NewMockStore(ctrl))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/websearch?q=grants", nil)
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	server.search = stubSearchProvider{name: "stub", results: []WebResult{{Title: "A", URL: "https://a.example.org"}}}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/websearch?q=grants", nil)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var got webSearchResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+	require.Len(t, got.Results, 1)
+	require.Equal(t, "stub", got.Provider)
+	require.False(t, got.Cached)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/websearch", nil)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	server.search = stubSearchProvider{name: "stub", err: errors.New("down")}
+	req = httptest.NewRequest(http.MethodGet, "/api/websearch?q=grants", nil)
+	resp, err = server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+}
@@ -0,0 +1,323 @@
+// server/catalog/catalog.go
+
+// Package catalog reads course catalog files (CSV, JSON, NDJSON and Sisu
+// style exports) into rows for db.Store.ImportCoursesTx.
+package catalog
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Supported file formats.
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// columnAliases maps normalized header / JSON keys to courses columns.
+var columnAliases = map[string]string{
+	"code":                "code",
+	"course_code":         "code",
+	"coursecode":          "code",
+	"name":                "name",
+	"course_name":         "name",
+	"title":               "name",
+	"language":            "language",
+	"languages":           "language",
+	"teaching_language":   "language",
+	"grading_scale":       "grading_scale",
+	"grading":             "grading_scale",
+	"gradescale":          "grading_scale",
+	"organiser":           "organiser",
+	"organizer":           "organiser",
+	"organisation":        "organiser",
+	"faculty":             "organiser",
+	"learning_outcomes":   "learning_outcomes",
+	"outcomes":            "learning_outcomes",
+	"learningoutcomes":    "learning_outcomes",
+	"prerequisites":       "prerequisites",
+	"prerequisite":        "prerequisites",
+	"teacher_name":        "teacher_name",
+	"teacher":             "teacher_name",
+	"responsible_teacher": "teacher_name",
+	"teacher_email":       "teacher_email",
+	"email":               "teacher_email",
+	"course_link":         "course_link",
+	"link":                "course_link",
+	"url":                 "course_link",
+}
+
+// Preferred languages of localized Sisu values ({"en": ..., "fi": ...}).
+var localePreference = []string{"en", "fi", "sv"}
+
+// DetectFormat picks the format from an explicit value, else from the file
+// extension.
+func DetectFormat(format, filename string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			format = FormatCSV
+		case ".json":
+			format = FormatJSON
+		case ".ndjson", ".jsonl":
+			format = FormatNDJSON
+		}
+	}
+	switch format {
+	case FormatCSV, FormatJSON, FormatNDJSON:
+		return format, nil
+	case "":
+		return "", fmt.Errorf("cannot detect the format of %q: use csv, json or ndjson", filename)
+	}
+	return "", fmt.Errorf("unsupported format %q: use csv, json or ndjson", format)
+}
+
+// Parse reads a catalog file and returns one validated row per course.
+// Codes are upper-cased; duplicate codes and invalid rows are errors that
+// name the offending record.
+func Parse(r io.Reader, format string) ([]db.UpsertCourseParams, error) {
+	var records []map[string]string
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatJSON:
+		records, err = readJSON(r)
+	case FormatNDJSON:
+		records, err = readNDJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("file contains no courses")
+	}
+
+	courses := make([]db.UpsertCourseParams, 0, len(records))
+	seen := make(map[string]int, len(records))
+	for i, record := range records {
+		course, err := newCourse(record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if first, ok := seen[course.Code]; ok {
+			return nil, fmt.Errorf("record %d: duplicate code %s (first in record %d)", i+1, course.Code, first)
+		}
+		seen[course.Code] = i + 1
+		courses = append(courses, course)
+	}
+	return courses, nil
+}
+
+func newCourse(record map[string]string) (db.UpsertCourseParams, error) {
+	course := db.UpsertCourseParams{
+		Code:             strings.ToUpper(record["code"]),
+		Name:             record["name"],
+		Language:         nullString(record["language"]),
+		GradingScale:     nullString(record["grading_scale"]),
+		Organiser:        nullString(record["organiser"]),
+		LearningOutcomes: nullString(record["learning_outcomes"]),
+		Prerequisites:    nullString(record["prerequisites"]),
+		TeacherName:      nullString(record["teacher_name"]),
+		TeacherEmail:     nullString(record["teacher_email"]),
+		CourseLink:       nullString(record["course_link"]),
+	}
+
+	switch {
+	case !util.IsCourseCode(course.Code):
+		return course, fmt.Errorf("invalid course code %q", record["code"])
+	case course.Name == "":
+		return course, fmt.Errorf("course %s has no name", course.Code)
+	}
+	if course.TeacherEmail.Valid {
+		if _, err := mail.ParseAddress(course.TeacherEmail.String); err != nil {
+			return course, fmt.Errorf("course %s: invalid teacher_email %q", course.Code, course.TeacherEmail.String)
+		}
+	}
+	if course.CourseLink.Valid {
+		if u, err := url.ParseRequestURI(course.CourseLink.String); err != nil || u.Host == "" {
+			return course, fmt.Errorf("course %s: invalid course_link %q", course.Code, course.CourseLink.String)
+		}
+	}
+	return course, nil
+}
+
+// ---------------------------
+// Readers
+// ---------------------------
+
+func readCSV(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = columnAliases[normalizeKey(name)]
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		record := make(map[string]string)
+		for i, value := range row {
+			if i < len(columns) && columns[i] != "" {
+				record[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// readJSON accepts an array of course objects or an object wrapping one
+// under "courses", "items" or "data".
+func readJSON(r io.Reader) ([]map[string]string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimSpace(body)
+
+	var objects []map[string]json.RawMessage
+	if len(body) > 0 && body[0] == '{' {
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(body, &wrapper); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		for _, key := range []string{"courses", "items", "data"} {
+			if list, ok := wrapper[key]; ok {
+				body = list
+				break
+			}
+		}
+	}
+	if err := json.Unmarshal(body, &objects); err != nil {
+		return nil, fmt.Errorf("parse json: expected an array of courses: %w", err)
+	}
+
+	records := make([]map[string]string, 0, len(objects))
+	for _, object := range objects {
+		records = append(records, jsonRecord(object))
+	}
+	return records, nil
+}
+
+func readNDJSON(r io.Reader) ([]map[string]string, error) {
+	var records []map[string]string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(text), &object); err != nil {
+			return nil, fmt.Errorf("parse ndjson line %d: %w", line, err)
+		}
+		records = append(records, jsonRecord(object))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func jsonRecord(object map[string]json.RawMessage) map[string]string {
+	record := make(map[string]string)
+	for key, raw := range object {
+		if column := columnAliases[normalizeKey(key)]; column != "" {
+			record[column] = jsonText(raw)
+		}
+	}
+	return record
+}
+
+// jsonText flattens a JSON value to text: strings and numbers as-is,
+// localized objects to the preferred locale, arrays joined with ", ".
+func jsonText(raw json.RawMessage) string {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(flatten(value))
+}
+
+func flatten(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := strings.TrimSpace(flatten(item)); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		for _, locale := range localePreference {
+			if text := flatten(v[locale]); text != "" {
+				return text
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if text := flatten(v[key]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(key, "\ufeff")))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+}
+
+func nullString(v string) sql.NullString {
+	v = strings.TrimSpace(v)
+	return sql.NullString{String: v, Valid: v != ""}
+}
@@ -0,0 +1,141 @@
+// server/catalog/catalog_test.go
+
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		format   string
+		filename string
+		want     string
+		wantErr  bool
+	}{
+		{filename: "courses.csv", want: FormatCSV},
+		{filename: "COURSES.JSON", want: FormatJSON},
+		{filename: "export.jsonl", want: FormatNDJSON},
+		{format: "NDJSON", filename: "upload.txt", want: FormatNDJSON},
+		{filename: "courses.xlsx", wantErr: true},
+		{format: "xml", filename: "courses.csv", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := DetectFormat(tc.format, tc.filename)
+		if tc.wantErr {
+			require.Error(t, err, tc.filename)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.want, got)
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	file := "Course Code,Title,Teacher,Email,Unknown\n" +
+		"tjts5012,Research Methods,Jukka,jukka@example.com,ignored\n" +
+		"TIES454, \"Agent Technologies, for Developers\",,,\n"
+
+	courses, err := Parse(strings.NewReader(file), FormatCSV)
+	require.NoError(t, err)
+	require.Len(t, courses, 2)
+
+	require.Equal(t, "TJTS5012", courses[0].Code)
+	require.Equal(t, "Research Methods", courses[0].Name)
+	require.Equal(t, "Jukka", courses[0].TeacherName.String)
+	require.Equal(t, "jukka@example.com", courses[0].TeacherEmail.String)
+
+	require.Equal(t, "Agent Technologies, for Developers", courses[1].Name)
+	require.False(t, courses[1].TeacherName.Valid)
+}
+
+func TestParseJSON(t *testing.T) {
+	// Sisu-style export: localized values and list fields
+	file := `{"courses": [
+		{"code": "KOGS1000", "name": {"fi": "Johdatus", "en": "Introduction"},
+		 "languages": ["English", "Finnish"], "outcomes": {"fi": "Osaa"},
+		 "url": "https://sisu.jyu.fi/student/courseunit/otm-1/brochure"}
+	]}`
+
+	courses, err := Parse(strings.NewReader(file), FormatJSON)
+	require.NoError(t, err)
+	require.Len(t, courses, 1)
+	require.Equal(t, "Introduction", courses[0].Name)
+	require.Equal(t, "English, Finnish", courses[0].Language.String)
+	require.Equal(t, "Osaa", courses[0].LearningOutcomes.String)
+	require.Equal(t, "https://sisu.jyu.fi/student/courseunit/otm-1/brochure", courses[0].CourseLink.String)
+}
+
+func TestParseNDJSON(t *testing.T) {
+	file := `{"code": "KOGS1001", "name": "Cognition"}
+
+{"code": "KOGS1002", "name": "Perception", "prerequisites": "KOGS1001"}
+`
+	courses, err := Parse(strings.NewReader(file), FormatNDJSON)
+	require.NoError(t, err)
+	require.Len(t, courses, 2)
+	require.Equal(t, "KOGS1001", courses[1].Prerequisites.String)
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  string
+		file    string
+		wantErr string
+	}{
+		{
+			name:    "InvalidCode",
+			format:  FormatNDJSON,
+			file:    `{"code": "not a code", "name": "x"}`,
+			wantErr: "record 1: invalid course code",
+		},
+		{
+			name:    "MissingName",
+			format:  FormatCSV,
+			file:    "code,name\nKOGS1000,\n",
+			wantErr: "has no name",
+		},
+		{
+			name:    "Duplicate",
+			format:  FormatCSV,
+			file:    "code,name\nKOGS1000,A\nkogs1000,B\n",
+			wantErr: "record 2: duplicate code KOGS1000 (first in record 1)",
+		},
+		{
+			name:    "InvalidEmail",
+			format:  FormatJSON,
+			file:    `[{"code": "KOGS1000", "name": "A", "teacher_email": "nobody"}]`,
+			wantErr: "invalid teacher_email",
+		},
+		{
+			name:    "InvalidLink",
+			format:  FormatJSON,
+			file:    `[{"code": "KOGS1000", "name": "A", "course_link": "sisu brochure"}]`,
+			wantErr: "invalid course_link",
+		},
+		{
+			name:    "Empty",
+			format:  FormatJSON,
+			file:    `[]`,
+			wantErr: "file contains no courses",
+		},
+		{
+			name:    "BadJSON",
+			format:  FormatNDJSON,
+			file:    "{\"code\": \"KOGS1000\"}\n{oops",
+			wantErr: "parse ndjson line 2",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.file), tc.format)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
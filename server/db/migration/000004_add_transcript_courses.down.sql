@@ -0,0 +1,3 @@
+-- db/migration/000004_add_transcript_courses.down.sql
+
+DROP TABLE IF EXISTS transcript_courses;
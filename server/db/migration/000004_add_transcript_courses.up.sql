@@ -0,0 +1,17 @@
+-- db/migration/000004_add_transcript_courses.up.sql
+-- Parsed course records (one row per course line found in a transcript)
+CREATE TABLE transcript_courses (
+  id BIGSERIAL PRIMARY KEY,
+  transcript_id BIGINT NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+  course_code VARCHAR NOT NULL,
+  course_name VARCHAR NOT NULL,
+  credits DOUBLE PRECISION,                    -- ECTS / op
+  grade VARCHAR,                               -- raw grade as printed: "5", "pass", "hyv." ...
+  term VARCHAR,                                -- e.g. "Autumn 2023"
+  status VARCHAR NOT NULL DEFAULT 'completed', -- completed | failed | in_progress
+  completed_on DATE,
+  parser VARCHAR NOT NULL,                     -- heuristic | llm
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON transcript_courses (transcript_id);
@@ -0,0 +1,3 @@
+-- db/migration/000005_add_course_prerequisites.down.sql
+
+DROP TABLE IF EXISTS course_prerequisites;
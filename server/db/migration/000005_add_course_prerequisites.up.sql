@@ -0,0 +1,10 @@
+-- db/migration/000005_add_course_prerequisites.up.sql
+-- Parsed prerequisite expressions (AND/OR of course codes), one row per course.
+-- source_text is the courses.prerequisites value the expression was parsed from,
+-- so stale rows can be detected and re-parsed.
+CREATE TABLE course_prerequisites (
+  course_id BIGINT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  expression JSONB NOT NULL,
+  source_text TEXT NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+-- db/migration/000006_add_study_plans.down.sql
+
+DROP TABLE IF EXISTS study_plans;
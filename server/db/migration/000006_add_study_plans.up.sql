@@ -0,0 +1,15 @@
+-- db/migration/000006_add_study_plans.up.sql
+-- Multi-term study plans. target holds the request parameters (credit goal or
+-- goal courses, per-term cap, language); plan holds the generated terms.
+CREATE TABLE study_plans (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  transcript_id BIGINT REFERENCES transcripts(id) ON DELETE SET NULL,
+  title VARCHAR NOT NULL,
+  target JSONB NOT NULL,
+  plan JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON study_plans (user_username);
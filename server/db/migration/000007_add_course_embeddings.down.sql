@@ -0,0 +1,3 @@
+-- db/migration/000007_add_course_embeddings.down.sql
+
+DROP TABLE IF EXISTS course_embeddings;
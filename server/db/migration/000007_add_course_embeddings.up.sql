@@ -0,0 +1,14 @@
+-- db/migration/000007_add_course_embeddings.up.sql
+-- Course embedding vectors for semantic retrieval, stored as little-endian
+-- float32 bytes and searched in-process (no pgvector dependency).
+-- embedder names the model that produced the vector and content_hash the
+-- course text it was computed from, so stale rows can be re-embedded.
+CREATE TABLE course_embeddings (
+  course_id BIGINT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  embedder VARCHAR NOT NULL,
+  content_hash VARCHAR NOT NULL,
+  vector BYTEA NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON course_embeddings (embedder);
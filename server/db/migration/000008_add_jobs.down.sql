@@ -0,0 +1,3 @@
+-- db/migration/000008_add_jobs.down.sql
+
+DROP TABLE IF EXISTS jobs;
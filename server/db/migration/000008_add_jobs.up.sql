@@ -0,0 +1,24 @@
+-- db/migration/000008_add_jobs.up.sql
+-- Background jobs (transcript OCR, recommendations, scholarships, summaries).
+-- Workers claim queued rows with FOR UPDATE SKIP LOCKED; failed attempts are
+-- re-queued with a later run_at until max_attempts is reached.
+-- status: queued | running | succeeded | failed
+CREATE TABLE jobs (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  kind VARCHAR NOT NULL,
+  status VARCHAR NOT NULL DEFAULT 'queued',
+  payload JSONB NOT NULL DEFAULT '{}'::jsonb,
+  result JSONB NOT NULL DEFAULT '{}'::jsonb,
+  error TEXT,
+  attempts INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL DEFAULT 3,
+  run_at TIMESTAMP NOT NULL DEFAULT now(),
+  started_at TIMESTAMP,
+  finished_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON jobs (status, run_at);
+CREATE INDEX ON jobs (user_username);
@@ -0,0 +1,3 @@
+-- db/migration/000009_add_sessions.down.sql
+
+DROP TABLE IF EXISTS sessions;
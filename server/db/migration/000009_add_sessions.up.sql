@@ -0,0 +1,16 @@
+-- db/migration/000009_add_sessions.up.sql
+-- Login sessions backing refresh tokens. id is the refresh token's
+-- Payload.ID; blocking a session (logout, revoke) stops it from renewing
+-- access tokens.
+CREATE TABLE sessions (
+  id UUID PRIMARY KEY,
+  username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  refresh_token VARCHAR NOT NULL,
+  user_agent VARCHAR NOT NULL,
+  client_ip VARCHAR NOT NULL,
+  is_blocked BOOLEAN NOT NULL DEFAULT false,
+  expires_at TIMESTAMP NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON sessions (username);
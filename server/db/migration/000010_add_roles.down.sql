@@ -0,0 +1,5 @@
+-- db/migration/000010_add_roles.down.sql
+
+DROP TABLE IF EXISTS advisor_students;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
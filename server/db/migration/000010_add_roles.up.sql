@@ -0,0 +1,13 @@
+-- db/migration/000010_add_roles.up.sql
+-- User roles (student, advisor, admin) and the students each advisor may
+-- read transcripts, recommendations and summaries of.
+ALTER TABLE users ADD COLUMN role VARCHAR NOT NULL DEFAULT 'student';
+
+CREATE TABLE advisor_students (
+  advisor_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  student_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (advisor_username, student_username)
+);
+
+CREATE INDEX ON advisor_students (student_username);
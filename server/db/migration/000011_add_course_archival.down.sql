@@ -0,0 +1,4 @@
+-- db/migration/000011_add_course_archival.down.sql
+
+ALTER TABLE courses DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE courses DROP COLUMN IF EXISTS archived_at;
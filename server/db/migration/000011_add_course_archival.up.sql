@@ -0,0 +1,6 @@
+-- db/migration/000011_add_course_archival.up.sql
+-- Admin-managed catalog. Courses are archived instead of deleted so saved
+-- recommendations, plans and transcripts that reference them still render;
+-- archived courses are left out of new recommendations.
+ALTER TABLE courses ADD COLUMN archived_at TIMESTAMP;
+ALTER TABLE courses ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT now();
@@ -0,0 +1,21 @@
+-- db/migration/000012_add_scholarship_catalog.down.sql
+
+CREATE TABLE scholarships (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  title VARCHAR NOT NULL,
+  description TEXT,
+  match_score DOUBLE PRECISION,
+  link TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON scholarships (user_username);
+
+INSERT INTO scholarships (user_username, title, description, match_score, link, created_at)
+SELECT m.user_username, c.title, c.description, m.match_score, c.canonical_url, m.updated_at
+FROM user_scholarship_matches m
+JOIN scholarship_catalog c ON c.id = m.scholarship_id;
+
+DROP TABLE IF EXISTS user_scholarship_matches;
+DROP TABLE IF EXISTS scholarship_catalog;
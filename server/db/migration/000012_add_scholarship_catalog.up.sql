@@ -0,0 +1,69 @@
+-- db/migration/000012_add_scholarship_catalog.up.sql
+-- Global scholarship catalog, deduplicated by the hash of the canonical URL,
+-- plus per-user match scores. Replaces the per-user scholarships table,
+-- which got a new copy of every result on each search.
+CREATE TABLE scholarship_catalog (
+  id BIGSERIAL PRIMARY KEY,
+  url_hash VARCHAR NOT NULL UNIQUE,
+  canonical_url TEXT NOT NULL,
+  title VARCHAR NOT NULL,
+  description TEXT,
+  provider VARCHAR,
+  amount DOUBLE PRECISION,
+  currency VARCHAR(3),
+  deadline DATE,
+  eligible_nationalities TEXT[] NOT NULL DEFAULT '{}',
+  degree_levels TEXT[] NOT NULL DEFAULT '{}',
+  fields_of_study TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON scholarship_catalog (deadline);
+
+CREATE TABLE user_scholarship_matches (
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  scholarship_id BIGINT NOT NULL REFERENCES scholarship_catalog(id) ON DELETE CASCADE,
+  match_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_username, scholarship_id)
+);
+
+CREATE INDEX ON user_scholarship_matches (scholarship_id);
+
+-- Carry saved scholarships over, one catalog row per link. The key follows
+-- canonicalScholarshipURL (api/scholarship_catalog.go): no scheme, "www."
+-- or fragment, no trailing slash on the path, lower case. Go also drops
+-- tracking parameters, sorts and re-encodes the query and decodes the path,
+-- which SQL does not; a backfilled link with tracking parameters, unsorted
+-- query parameters or percent-escapes in its path may therefore get a
+-- second catalog row when it is found again by a new search.
+ALTER TABLE scholarships ADD COLUMN url_hash VARCHAR;
+
+WITH keys AS (
+  SELECT id, regexp_replace(lower(split_part(trim(link), '#', 1)), '^[a-z][a-z0-9+.-]*://(www\.)?', '') AS k
+  FROM scholarships
+  WHERE COALESCE(trim(link), '') <> ''
+)
+UPDATE scholarships s
+SET url_hash = encode(sha256(convert_to(
+      regexp_replace(split_part(keys.k, '?', 1), '/+$', '')
+      || COALESCE('?' || NULLIF(substring(keys.k FROM '\?(.*)$'), ''), ''),
+      'UTF8')), 'hex')
+FROM keys
+WHERE keys.id = s.id;
+
+INSERT INTO scholarship_catalog (url_hash, canonical_url, title, description, created_at, updated_at)
+SELECT DISTINCT ON (url_hash) url_hash, trim(link), title, description, created_at, created_at
+FROM scholarships
+WHERE url_hash IS NOT NULL
+ORDER BY url_hash, created_at DESC;
+
+INSERT INTO user_scholarship_matches (user_username, scholarship_id, match_score, created_at, updated_at)
+SELECT s.user_username, c.id, max(COALESCE(s.match_score, 0)), min(s.created_at), max(s.created_at)
+FROM scholarships s
+JOIN scholarship_catalog c ON c.url_hash = s.url_hash
+GROUP BY s.user_username, c.id;
+
+DROP TABLE scholarships;
@@ -0,0 +1,5 @@
+-- db/migration/000013_add_notifications.down.sql
+
+DROP TABLE IF EXISTS scholarship_reminders;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,31 @@
+-- db/migration/000013_add_notifications.up.sql
+-- In-app notifications, per-user reminder preferences and the deadline
+-- reminders already sent (one per user, scholarship and deadline).
+CREATE TABLE notifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  kind VARCHAR NOT NULL,
+  title VARCHAR NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  link TEXT,
+  read_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON notifications (user_username, created_at DESC);
+
+CREATE TABLE notification_preferences (
+  user_username VARCHAR PRIMARY KEY REFERENCES users(username) ON DELETE CASCADE,
+  deadline_reminders BOOLEAN NOT NULL DEFAULT true,
+  remind_days_before INT NOT NULL DEFAULT 7,
+  channels TEXT[] NOT NULL DEFAULT '{in_app}',
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE TABLE scholarship_reminders (
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  scholarship_id BIGINT NOT NULL REFERENCES scholarship_catalog(id) ON DELETE CASCADE,
+  deadline DATE NOT NULL,
+  sent_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_username, scholarship_id, deadline)
+);
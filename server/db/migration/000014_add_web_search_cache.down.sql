@@ -0,0 +1,4 @@
+-- db/migration/000014_add_web_search_cache.down.sql
+
+DROP TABLE IF EXISTS web_search_daily_usage;
+DROP TABLE IF EXISTS web_search_cache;
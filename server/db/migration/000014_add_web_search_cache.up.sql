@@ -0,0 +1,19 @@
+-- db/migration/000014_add_web_search_cache.up.sql
+-- Cached web search results per provider and normalized query, and the
+-- number of requests sent to each provider per (UTC) day.
+CREATE TABLE web_search_cache (
+  provider VARCHAR NOT NULL,
+  query_key TEXT NOT NULL,
+  query TEXT NOT NULL,
+  results JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  expires_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (provider, query_key)
+);
+
+CREATE TABLE web_search_daily_usage (
+  provider VARCHAR NOT NULL,
+  day DATE NOT NULL,
+  used INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (provider, day)
+);
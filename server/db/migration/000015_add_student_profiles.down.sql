@@ -0,0 +1,4 @@
+-- db/migration/000015_add_student_profiles.down.sql
+
+ALTER TABLE user_scholarship_matches DROP COLUMN IF EXISTS search_query;
+DROP TABLE IF EXISTS student_profiles;
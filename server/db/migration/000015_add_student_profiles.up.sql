@@ -0,0 +1,15 @@
+-- db/migration/000015_add_student_profiles.up.sql
+-- Student profile used to build scholarship search queries, and the query
+-- that surfaced each of a user's scholarship matches.
+CREATE TABLE student_profiles (
+  user_username VARCHAR PRIMARY KEY REFERENCES users(username) ON DELETE CASCADE,
+  nationality VARCHAR,
+  degree_level VARCHAR,
+  field_of_study VARCHAR,
+  target_countries TEXT[] NOT NULL DEFAULT '{}',
+  languages TEXT[] NOT NULL DEFAULT '{}',
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE user_scholarship_matches ADD COLUMN search_query TEXT;
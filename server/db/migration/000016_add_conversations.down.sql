@@ -0,0 +1,4 @@
+-- db/migration/000016_add_conversations.down.sql
+
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS conversations;
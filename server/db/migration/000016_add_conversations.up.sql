@@ -0,0 +1,26 @@
+-- db/migration/000016_add_conversations.up.sql
+-- Saved chat conversations, optionally pinned to a recommendation, and
+-- their messages. Messages up to summarized_until are folded into summary.
+CREATE TABLE conversations (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  recommendation_id BIGINT REFERENCES recommendations(id) ON DELETE SET NULL,
+  title VARCHAR NOT NULL DEFAULT '',
+  summary TEXT,
+  summarized_until BIGINT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON conversations (user_username, updated_at DESC);
+
+CREATE TABLE chat_messages (
+  id BIGSERIAL PRIMARY KEY,
+  conversation_id BIGINT NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+  role VARCHAR NOT NULL CHECK (role IN ('user', 'assistant')),
+  content TEXT NOT NULL,
+  tokens INT NOT NULL DEFAULT 0,    -- estimated prompt tokens
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON chat_messages (conversation_id, id);
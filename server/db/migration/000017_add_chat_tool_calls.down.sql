@@ -0,0 +1,3 @@
+-- db/migration/000017_add_chat_tool_calls.down.sql
+
+DROP TABLE IF EXISTS chat_tool_calls;
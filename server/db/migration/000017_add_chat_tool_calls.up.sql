@@ -0,0 +1,17 @@
+-- db/migration/000017_add_chat_tool_calls.up.sql
+-- Audit log of the tools the chat assistant ran on a user's behalf.
+CREATE TABLE chat_tool_calls (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  conversation_id BIGINT REFERENCES conversations(id) ON DELETE SET NULL,
+  tool_call_id VARCHAR NOT NULL DEFAULT '',   -- the model's call ID
+  tool_name VARCHAR NOT NULL,
+  arguments JSONB NOT NULL DEFAULT '{}',
+  result JSONB,
+  status VARCHAR NOT NULL CHECK (status IN ('ok', 'error')),
+  error TEXT,
+  duration_ms INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON chat_tool_calls (user_username, id DESC);
@@ -0,0 +1,3 @@
+-- db/migration/000018_add_llm_usage.down.sql
+
+DROP TABLE IF EXISTS llm_usage;
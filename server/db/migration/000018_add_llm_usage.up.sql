@@ -0,0 +1,20 @@
+-- db/migration/000018_add_llm_usage.up.sql
+-- One row per LLM provider call: tokens, cost and latency, by feature and user.
+CREATE TABLE llm_usage (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR REFERENCES users(username) ON DELETE CASCADE,   -- NULL for calls outside a user request (warmup)
+  feature VARCHAR NOT NULL,          -- chat, recommendation, scholarship, summary, transcript, ...
+  provider VARCHAR NOT NULL,
+  model VARCHAR NOT NULL DEFAULT '',
+  prompt_tokens INT NOT NULL DEFAULT 0,
+  completion_tokens INT NOT NULL DEFAULT 0,
+  total_tokens INT NOT NULL DEFAULT 0,
+  estimated BOOLEAN NOT NULL DEFAULT false,   -- the provider reported no usage
+  cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0,
+  latency_ms INT NOT NULL DEFAULT 0,
+  status VARCHAR NOT NULL CHECK (status IN ('ok', 'error')),
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON llm_usage (user_username, created_at);
+CREATE INDEX ON llm_usage (created_at);
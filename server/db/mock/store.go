@@ -8,9 +8,11 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
 )
 
@@ -37,6 +39,155 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ArchiveCourse mocks base method.
+func (m *MockStore) ArchiveCourse(arg0 context.Context, arg1 int64) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveCourse indicates an expected call of ArchiveCourse.
+func (mr *MockStoreMockRecorder) ArchiveCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveCourse", reflect.TypeOf((*MockStore)(nil).ArchiveCourse), arg0, arg1)
+}
+
+// AssignAdvisorStudent mocks base method.
+func (m *MockStore) AssignAdvisorStudent(arg0 context.Context, arg1 db.AssignAdvisorStudentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignAdvisorStudent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignAdvisorStudent indicates an expected call of AssignAdvisorStudent.
+func (mr *MockStoreMockRecorder) AssignAdvisorStudent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignAdvisorStudent", reflect.TypeOf((*MockStore)(nil).AssignAdvisorStudent), arg0, arg1)
+}
+
+// BlockSession mocks base method.
+func (m *MockStore) BlockSession(arg0 context.Context, arg1 db.BlockSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+}
+
+// ClaimJob mocks base method.
+func (m *MockStore) ClaimJob(arg0 context.Context) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJob", arg0)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimJob indicates an expected call of ClaimJob.
+func (mr *MockStoreMockRecorder) ClaimJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJob", reflect.TypeOf((*MockStore)(nil).ClaimJob), arg0)
+}
+
+// ClaimWebSearchQuota mocks base method.
+func (m *MockStore) ClaimWebSearchQuota(arg0 context.Context, arg1 db.ClaimWebSearchQuotaParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebSearchQuota", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebSearchQuota indicates an expected call of ClaimWebSearchQuota.
+func (mr *MockStoreMockRecorder) ClaimWebSearchQuota(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebSearchQuota", reflect.TypeOf((*MockStore)(nil).ClaimWebSearchQuota), arg0, arg1)
+}
+
+// CompleteJob mocks base method.
+func (m *MockStore) CompleteJob(arg0 context.Context, arg1 db.CompleteJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockStoreMockRecorder) CompleteJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockStore)(nil).CompleteJob), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CreateChatMessage mocks base method.
+func (m *MockStore) CreateChatMessage(arg0 context.Context, arg1 db.CreateChatMessageParams) (db.ChatMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChatMessage", arg0, arg1)
+	ret0, _ := ret[0].(db.ChatMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChatMessage indicates an expected call of CreateChatMessage.
+func (mr *MockStoreMockRecorder) CreateChatMessage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChatMessage", reflect.TypeOf((*MockStore)(nil).CreateChatMessage), arg0, arg1)
+}
+
+// CreateChatToolCall mocks base method.
+func (m *MockStore) CreateChatToolCall(arg0 context.Context, arg1 db.CreateChatToolCallParams) (db.ChatToolCall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChatToolCall", arg0, arg1)
+	ret0, _ := ret[0].(db.ChatToolCall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChatToolCall indicates an expected call of CreateChatToolCall.
+func (mr *MockStoreMockRecorder) CreateChatToolCall(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChatToolCall", reflect.TypeOf((*MockStore)(nil).CreateChatToolCall), arg0, arg1)
+}
+
+// CreateConversation mocks base method.
+func (m *MockStore) CreateConversation(arg0 context.Context, arg1 db.CreateConversationParams) (db.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConversation", arg0, arg1)
+	ret0, _ := ret[0].(db.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConversation indicates an expected call of CreateConversation.
+func (mr *MockStoreMockRecorder) CreateConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConversation", reflect.TypeOf((*MockStore)(nil).CreateConversation), arg0, arg1)
+}
+
 // CreateCourse mocks base method.
 func (m *MockStore) CreateCourse(arg0 context.Context, arg1 db.CreateCourseParams) (db.Course, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +203,51 @@ func (mr *MockStoreMockRecorder) CreateCourse(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourse", reflect.TypeOf((*MockStore)(nil).CreateCourse), arg0, arg1)
 }
 
+// CreateJob mocks base method.
+func (m *MockStore) CreateJob(arg0 context.Context, arg1 db.CreateJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockStoreMockRecorder) CreateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockStore)(nil).CreateJob), arg0, arg1)
+}
+
+// CreateLLMUsage mocks base method.
+func (m *MockStore) CreateLLMUsage(arg0 context.Context, arg1 db.CreateLLMUsageParams) (db.LlmUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLLMUsage", arg0, arg1)
+	ret0, _ := ret[0].(db.LlmUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLLMUsage indicates an expected call of CreateLLMUsage.
+func (mr *MockStoreMockRecorder) CreateLLMUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLLMUsage", reflect.TypeOf((*MockStore)(nil).CreateLLMUsage), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
 // CreateRecommendation mocks base method.
 func (m *MockStore) CreateRecommendation(arg0 context.Context, arg1 db.CreateRecommendationParams) (db.Recommendation, error) {
 	m.ctrl.T.Helper()
@@ -67,19 +263,34 @@ func (mr *MockStoreMockRecorder) CreateRecommendation(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecommendation", reflect.TypeOf((*MockStore)(nil).CreateRecommendation), arg0, arg1)
 }
 
-// CreateScholarship mocks base method.
-func (m *MockStore) CreateScholarship(arg0 context.Context, arg1 db.CreateScholarshipParams) (db.Scholarship, error) {
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateStudyPlan mocks base method.
+func (m *MockStore) CreateStudyPlan(arg0 context.Context, arg1 db.CreateStudyPlanParams) (db.StudyPlan, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateScholarship", arg0, arg1)
-	ret0, _ := ret[0].(db.Scholarship)
+	ret := m.ctrl.Call(m, "CreateStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateScholarship indicates an expected call of CreateScholarship.
-func (mr *MockStoreMockRecorder) CreateScholarship(arg0, arg1 interface{}) *gomock.Call {
+// CreateStudyPlan indicates an expected call of CreateStudyPlan.
+func (mr *MockStoreMockRecorder) CreateStudyPlan(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScholarship", reflect.TypeOf((*MockStore)(nil).CreateScholarship), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStudyPlan", reflect.TypeOf((*MockStore)(nil).CreateStudyPlan), arg0, arg1)
 }
 
 // CreateSummary mocks base method.
@@ -112,6 +323,21 @@ func (mr *MockStoreMockRecorder) CreateTranscript(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscript", reflect.TypeOf((*MockStore)(nil).CreateTranscript), arg0, arg1)
 }
 
+// CreateTranscriptCourse mocks base method.
+func (m *MockStore) CreateTranscriptCourse(arg0 context.Context, arg1 db.CreateTranscriptCourseParams) (db.TranscriptCourse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTranscriptCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.TranscriptCourse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTranscriptCourse indicates an expected call of CreateTranscriptCourse.
+func (mr *MockStoreMockRecorder) CreateTranscriptCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscriptCourse", reflect.TypeOf((*MockStore)(nil).CreateTranscriptCourse), arg0, arg1)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -127,18 +353,47 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
-// DeleteScholarship mocks base method.
-func (m *MockStore) DeleteScholarship(arg0 context.Context, arg1 db.DeleteScholarshipParams) error {
+// DeleteConversation mocks base method.
+func (m *MockStore) DeleteConversation(arg0 context.Context, arg1 db.DeleteConversationParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConversation", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteConversation indicates an expected call of DeleteConversation.
+func (mr *MockStoreMockRecorder) DeleteConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConversation", reflect.TypeOf((*MockStore)(nil).DeleteConversation), arg0, arg1)
+}
+
+// DeleteScholarshipReminder mocks base method.
+func (m *MockStore) DeleteScholarshipReminder(arg0 context.Context, arg1 db.DeleteScholarshipReminderParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScholarshipReminder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteScholarshipReminder indicates an expected call of DeleteScholarshipReminder.
+func (mr *MockStoreMockRecorder) DeleteScholarshipReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScholarshipReminder", reflect.TypeOf((*MockStore)(nil).DeleteScholarshipReminder), arg0, arg1)
+}
+
+// DeleteStudyPlan mocks base method.
+func (m *MockStore) DeleteStudyPlan(arg0 context.Context, arg1 db.DeleteStudyPlanParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteScholarship", arg0, arg1)
+	ret := m.ctrl.Call(m, "DeleteStudyPlan", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteScholarship indicates an expected call of DeleteScholarship.
-func (mr *MockStoreMockRecorder) DeleteScholarship(arg0, arg1 interface{}) *gomock.Call {
+// DeleteStudyPlan indicates an expected call of DeleteStudyPlan.
+func (mr *MockStoreMockRecorder) DeleteStudyPlan(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScholarship", reflect.TypeOf((*MockStore)(nil).DeleteScholarship), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStudyPlan", reflect.TypeOf((*MockStore)(nil).DeleteStudyPlan), arg0, arg1)
 }
 
 // DeleteSummary mocks base method.
@@ -155,6 +410,110 @@ func (mr *MockStoreMockRecorder) DeleteSummary(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSummary", reflect.TypeOf((*MockStore)(nil).DeleteSummary), arg0, arg1)
 }
 
+// DeleteUserScholarshipMatch mocks base method.
+func (m *MockStore) DeleteUserScholarshipMatch(arg0 context.Context, arg1 db.DeleteUserScholarshipMatchParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserScholarshipMatch", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserScholarshipMatch indicates an expected call of DeleteUserScholarshipMatch.
+func (mr *MockStoreMockRecorder) DeleteUserScholarshipMatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserScholarshipMatch", reflect.TypeOf((*MockStore)(nil).DeleteUserScholarshipMatch), arg0, arg1)
+}
+
+// FailJob mocks base method.
+func (m *MockStore) FailJob(arg0 context.Context, arg1 db.FailJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailJob indicates an expected call of FailJob.
+func (mr *MockStoreMockRecorder) FailJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailJob", reflect.TypeOf((*MockStore)(nil).FailJob), arg0, arg1)
+}
+
+// FailStaleJobs mocks base method.
+func (m *MockStore) FailStaleJobs(arg0 context.Context, arg1 sql.NullTime) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailStaleJobs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailStaleJobs indicates an expected call of FailStaleJobs.
+func (mr *MockStoreMockRecorder) FailStaleJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailStaleJobs", reflect.TypeOf((*MockStore)(nil).FailStaleJobs), arg0, arg1)
+}
+
+// GetConversation mocks base method.
+func (m *MockStore) GetConversation(arg0 context.Context, arg1 int64) (db.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversation", arg0, arg1)
+	ret0, _ := ret[0].(db.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversation indicates an expected call of GetConversation.
+func (mr *MockStoreMockRecorder) GetConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversation", reflect.TypeOf((*MockStore)(nil).GetConversation), arg0, arg1)
+}
+
+// GetCourseByCode mocks base method.
+func (m *MockStore) GetCourseByCode(arg0 context.Context, arg1 string) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourseByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourseByCode indicates an expected call of GetCourseByCode.
+func (mr *MockStoreMockRecorder) GetCourseByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseByCode", reflect.TypeOf((*MockStore)(nil).GetCourseByCode), arg0, arg1)
+}
+
+// GetJob mocks base method.
+func (m *MockStore) GetJob(arg0 context.Context, arg1 int64) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockStoreMockRecorder) GetJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockStore)(nil).GetJob), arg0, arg1)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockStore) GetNotificationPreferences(arg0 context.Context, arg1 string) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferences), arg0, arg1)
+}
+
 // GetRecommendation mocks base method.
 func (m *MockStore) GetRecommendation(arg0 context.Context, arg1 int64) (db.Recommendation, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +529,51 @@ func (mr *MockStoreMockRecorder) GetRecommendation(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendation", reflect.TypeOf((*MockStore)(nil).GetRecommendation), arg0, arg1)
 }
 
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetStudentProfile mocks base method.
+func (m *MockStore) GetStudentProfile(arg0 context.Context, arg1 string) (db.StudentProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudentProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.StudentProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudentProfile indicates an expected call of GetStudentProfile.
+func (mr *MockStoreMockRecorder) GetStudentProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudentProfile", reflect.TypeOf((*MockStore)(nil).GetStudentProfile), arg0, arg1)
+}
+
+// GetStudyPlan mocks base method.
+func (m *MockStore) GetStudyPlan(arg0 context.Context, arg1 int64) (db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudyPlan indicates an expected call of GetStudyPlan.
+func (mr *MockStoreMockRecorder) GetStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudyPlan", reflect.TypeOf((*MockStore)(nil).GetStudyPlan), arg0, arg1)
+}
+
 // GetSummary mocks base method.
 func (m *MockStore) GetSummary(arg0 context.Context, arg1 db.GetSummaryParams) (db.Summary, error) {
 	m.ctrl.T.Helper()
@@ -215,107 +619,705 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
-// ListAllCourses mocks base method.
-func (m *MockStore) ListAllCourses(arg0 context.Context) ([]db.Course, error) {
+// GetWebSearchCache mocks base method.
+func (m *MockStore) GetWebSearchCache(arg0 context.Context, arg1 db.GetWebSearchCacheParams) (db.WebSearchCache, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAllCourses", arg0)
-	ret0, _ := ret[0].([]db.Course)
+	ret := m.ctrl.Call(m, "GetWebSearchCache", arg0, arg1)
+	ret0, _ := ret[0].(db.WebSearchCache)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAllCourses indicates an expected call of ListAllCourses.
-func (mr *MockStoreMockRecorder) ListAllCourses(arg0 interface{}) *gomock.Call {
+// GetWebSearchCache indicates an expected call of GetWebSearchCache.
+func (mr *MockStoreMockRecorder) GetWebSearchCache(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCourses", reflect.TypeOf((*MockStore)(nil).ListAllCourses), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebSearchCache", reflect.TypeOf((*MockStore)(nil).GetWebSearchCache), arg0, arg1)
 }
 
-// ListCourses mocks base method.
-func (m *MockStore) ListCourses(arg0 context.Context, arg1 int64) ([]db.Course, error) {
+// GetWebSearchUsage mocks base method.
+func (m *MockStore) GetWebSearchUsage(arg0 context.Context, arg1 db.GetWebSearchUsageParams) (int32, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCourses", arg0, arg1)
-	ret0, _ := ret[0].([]db.Course)
+	ret := m.ctrl.Call(m, "GetWebSearchUsage", arg0, arg1)
+	ret0, _ := ret[0].(int32)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCourses indicates an expected call of ListCourses.
-func (mr *MockStoreMockRecorder) ListCourses(arg0, arg1 interface{}) *gomock.Call {
+// GetWebSearchUsage indicates an expected call of GetWebSearchUsage.
+func (mr *MockStoreMockRecorder) GetWebSearchUsage(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourses", reflect.TypeOf((*MockStore)(nil).ListCourses), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebSearchUsage", reflect.TypeOf((*MockStore)(nil).GetWebSearchUsage), arg0, arg1)
 }
 
-// ListRecentScholarshipsByUser mocks base method.
-func (m *MockStore) ListRecentScholarshipsByUser(arg0 context.Context, arg1 db.ListRecentScholarshipsByUserParams) ([]db.Scholarship, error) {
+// ImportCoursesTx mocks base method.
+func (m *MockStore) ImportCoursesTx(arg0 context.Context, arg1 db.ImportCoursesTxParams) (db.ImportCoursesTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRecentScholarshipsByUser", arg0, arg1)
-	ret0, _ := ret[0].([]db.Scholarship)
+	ret := m.ctrl.Call(m, "ImportCoursesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportCoursesTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListRecentScholarshipsByUser indicates an expected call of ListRecentScholarshipsByUser.
-func (mr *MockStoreMockRecorder) ListRecentScholarshipsByUser(arg0, arg1 interface{}) *gomock.Call {
+// ImportCoursesTx indicates an expected call of ImportCoursesTx.
+func (mr *MockStoreMockRecorder) ImportCoursesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecentScholarshipsByUser", reflect.TypeOf((*MockStore)(nil).ListRecentScholarshipsByUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCoursesTx", reflect.TypeOf((*MockStore)(nil).ImportCoursesTx), arg0, arg1)
 }
 
-// ListRecommendations mocks base method.
-func (m *MockStore) ListRecommendations(arg0 context.Context, arg1 string) ([]db.ListRecommendationsRow, error) {
+// IsAdvisorOf mocks base method.
+func (m *MockStore) IsAdvisorOf(arg0 context.Context, arg1 db.IsAdvisorOfParams) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListRecommendations", arg0, arg1)
-	ret0, _ := ret[0].([]db.ListRecommendationsRow)
+	ret := m.ctrl.Call(m, "IsAdvisorOf", arg0, arg1)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListRecommendations indicates an expected call of ListRecommendations.
-func (mr *MockStoreMockRecorder) ListRecommendations(arg0, arg1 interface{}) *gomock.Call {
+// IsAdvisorOf indicates an expected call of IsAdvisorOf.
+func (mr *MockStoreMockRecorder) IsAdvisorOf(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecommendations", reflect.TypeOf((*MockStore)(nil).ListRecommendations), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsAdvisorOf", reflect.TypeOf((*MockStore)(nil).IsAdvisorOf), arg0, arg1)
 }
 
-// ListScholarshipsByUser mocks base method.
-func (m *MockStore) ListScholarshipsByUser(arg0 context.Context, arg1 string) ([]db.Scholarship, error) {
+// ListActiveCourses mocks base method.
+func (m *MockStore) ListActiveCourses(arg0 context.Context) ([]db.Course, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListScholarshipsByUser", arg0, arg1)
-	ret0, _ := ret[0].([]db.Scholarship)
+	ret := m.ctrl.Call(m, "ListActiveCourses", arg0)
+	ret0, _ := ret[0].([]db.Course)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListScholarshipsByUser indicates an expected call of ListScholarshipsByUser.
-func (mr *MockStoreMockRecorder) ListScholarshipsByUser(arg0, arg1 interface{}) *gomock.Call {
+// ListActiveCourses indicates an expected call of ListActiveCourses.
+func (mr *MockStoreMockRecorder) ListActiveCourses(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScholarshipsByUser", reflect.TypeOf((*MockStore)(nil).ListScholarshipsByUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveCourses", reflect.TypeOf((*MockStore)(nil).ListActiveCourses), arg0)
 }
 
-// ListSummaries mocks base method.
-func (m *MockStore) ListSummaries(arg0 context.Context, arg1 string) ([]db.Summary, error) {
+// ListActiveSessions mocks base method.
+func (m *MockStore) ListActiveSessions(arg0 context.Context, arg1 string) ([]db.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListSummaries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Summary)
+	ret := m.ctrl.Call(m, "ListActiveSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListSummaries indicates an expected call of ListSummaries.
-func (mr *MockStoreMockRecorder) ListSummaries(arg0, arg1 interface{}) *gomock.Call {
+// ListActiveSessions indicates an expected call of ListActiveSessions.
+func (mr *MockStoreMockRecorder) ListActiveSessions(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockStore)(nil).ListSummaries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSessions", reflect.TypeOf((*MockStore)(nil).ListActiveSessions), arg0, arg1)
 }
 
-// ListTranscripts mocks base method.
-func (m *MockStore) ListTranscripts(arg0 context.Context, arg1 string) ([]db.ListTranscriptsRow, error) {
+// ListAdvisorStudents mocks base method.
+func (m *MockStore) ListAdvisorStudents(arg0 context.Context, arg1 string) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTranscripts", arg0, arg1)
-	ret0, _ := ret[0].([]db.ListTranscriptsRow)
+	ret := m.ctrl.Call(m, "ListAdvisorStudents", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTranscripts indicates an expected call of ListTranscripts.
-func (mr *MockStoreMockRecorder) ListTranscripts(arg0, arg1 interface{}) *gomock.Call {
+// ListAdvisorStudents indicates an expected call of ListAdvisorStudents.
+func (mr *MockStoreMockRecorder) ListAdvisorStudents(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscripts", reflect.TypeOf((*MockStore)(nil).ListTranscripts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdvisorStudents", reflect.TypeOf((*MockStore)(nil).ListAdvisorStudents), arg0, arg1)
+}
+
+// ListAllCourses mocks base method.
+func (m *MockStore) ListAllCourses(arg0 context.Context) ([]db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllCourses", arg0)
+	ret0, _ := ret[0].([]db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllCourses indicates an expected call of ListAllCourses.
+func (mr *MockStoreMockRecorder) ListAllCourses(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCourses", reflect.TypeOf((*MockStore)(nil).ListAllCourses), arg0)
+}
+
+// ListChatMessages mocks base method.
+func (m *MockStore) ListChatMessages(arg0 context.Context, arg1 db.ListChatMessagesParams) ([]db.ChatMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChatMessages", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChatMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChatMessages indicates an expected call of ListChatMessages.
+func (mr *MockStoreMockRecorder) ListChatMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChatMessages", reflect.TypeOf((*MockStore)(nil).ListChatMessages), arg0, arg1)
+}
+
+// ListChatToolCalls mocks base method.
+func (m *MockStore) ListChatToolCalls(arg0 context.Context, arg1 db.ListChatToolCallsParams) ([]db.ChatToolCall, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChatToolCalls", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChatToolCall)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChatToolCalls indicates an expected call of ListChatToolCalls.
+func (mr *MockStoreMockRecorder) ListChatToolCalls(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChatToolCalls", reflect.TypeOf((*MockStore)(nil).ListChatToolCalls), arg0, arg1)
+}
+
+// ListConversations mocks base method.
+func (m *MockStore) ListConversations(arg0 context.Context, arg1 db.ListConversationsParams) ([]db.Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListConversations", arg0, arg1)
+	ret0, _ := ret[0].([]db.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListConversations indicates an expected call of ListConversations.
+func (mr *MockStoreMockRecorder) ListConversations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListConversations", reflect.TypeOf((*MockStore)(nil).ListConversations), arg0, arg1)
+}
+
+// ListCourseEmbeddings mocks base method.
+func (m *MockStore) ListCourseEmbeddings(arg0 context.Context, arg1 string) ([]db.CourseEmbedding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCourseEmbeddings", arg0, arg1)
+	ret0, _ := ret[0].([]db.CourseEmbedding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCourseEmbeddings indicates an expected call of ListCourseEmbeddings.
+func (mr *MockStoreMockRecorder) ListCourseEmbeddings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourseEmbeddings", reflect.TypeOf((*MockStore)(nil).ListCourseEmbeddings), arg0, arg1)
+}
+
+// ListCoursePrerequisites mocks base method.
+func (m *MockStore) ListCoursePrerequisites(arg0 context.Context) ([]db.CoursePrerequisite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoursePrerequisites", arg0)
+	ret0, _ := ret[0].([]db.CoursePrerequisite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoursePrerequisites indicates an expected call of ListCoursePrerequisites.
+func (mr *MockStoreMockRecorder) ListCoursePrerequisites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoursePrerequisites", reflect.TypeOf((*MockStore)(nil).ListCoursePrerequisites), arg0)
+}
+
+// ListCourses mocks base method.
+func (m *MockStore) ListCourses(arg0 context.Context, arg1 int64) ([]db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCourses", arg0, arg1)
+	ret0, _ := ret[0].([]db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCourses indicates an expected call of ListCourses.
+func (mr *MockStoreMockRecorder) ListCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourses", reflect.TypeOf((*MockStore)(nil).ListCourses), arg0, arg1)
+}
+
+// ListCoursesPage mocks base method.
+func (m *MockStore) ListCoursesPage(arg0 context.Context, arg1 db.ListCoursesPageParams) ([]db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoursesPage", arg0, arg1)
+	ret0, _ := ret[0].([]db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoursesPage indicates an expected call of ListCoursesPage.
+func (mr *MockStoreMockRecorder) ListCoursesPage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoursesPage", reflect.TypeOf((*MockStore)(nil).ListCoursesPage), arg0, arg1)
+}
+
+// ListDueScholarshipReminders mocks base method.
+func (m *MockStore) ListDueScholarshipReminders(arg0 context.Context, arg1 int64) ([]db.ListDueScholarshipRemindersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueScholarshipReminders", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDueScholarshipRemindersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueScholarshipReminders indicates an expected call of ListDueScholarshipReminders.
+func (mr *MockStoreMockRecorder) ListDueScholarshipReminders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueScholarshipReminders", reflect.TypeOf((*MockStore)(nil).ListDueScholarshipReminders), arg0, arg1)
+}
+
+// ListLatestChatMessages mocks base method.
+func (m *MockStore) ListLatestChatMessages(arg0 context.Context, arg1 db.ListLatestChatMessagesParams) ([]db.ChatMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLatestChatMessages", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChatMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLatestChatMessages indicates an expected call of ListLatestChatMessages.
+func (mr *MockStoreMockRecorder) ListLatestChatMessages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLatestChatMessages", reflect.TypeOf((*MockStore)(nil).ListLatestChatMessages), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListRecommendations mocks base method.
+func (m *MockStore) ListRecommendations(arg0 context.Context, arg1 string) ([]db.ListRecommendationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecommendations", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRecommendationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecommendations indicates an expected call of ListRecommendations.
+func (mr *MockStoreMockRecorder) ListRecommendations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecommendations", reflect.TypeOf((*MockStore)(nil).ListRecommendations), arg0, arg1)
+}
+
+// ListStudyPlans mocks base method.
+func (m *MockStore) ListStudyPlans(arg0 context.Context, arg1 string) ([]db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStudyPlans", arg0, arg1)
+	ret0, _ := ret[0].([]db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStudyPlans indicates an expected call of ListStudyPlans.
+func (mr *MockStoreMockRecorder) ListStudyPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStudyPlans", reflect.TypeOf((*MockStore)(nil).ListStudyPlans), arg0, arg1)
+}
+
+// ListSummaries mocks base method.
+func (m *MockStore) ListSummaries(arg0 context.Context, arg1 string) ([]db.Summary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSummaries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Summary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSummaries indicates an expected call of ListSummaries.
+func (mr *MockStoreMockRecorder) ListSummaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockStore)(nil).ListSummaries), arg0, arg1)
+}
+
+// ListTranscriptCourses mocks base method.
+func (m *MockStore) ListTranscriptCourses(arg0 context.Context, arg1 int64) ([]db.TranscriptCourse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranscriptCourses", arg0, arg1)
+	ret0, _ := ret[0].([]db.TranscriptCourse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranscriptCourses indicates an expected call of ListTranscriptCourses.
+func (mr *MockStoreMockRecorder) ListTranscriptCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscriptCourses", reflect.TypeOf((*MockStore)(nil).ListTranscriptCourses), arg0, arg1)
+}
+
+// ListTranscripts mocks base method.
+func (m *MockStore) ListTranscripts(arg0 context.Context, arg1 string) ([]db.ListTranscriptsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranscripts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListTranscriptsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranscripts indicates an expected call of ListTranscripts.
+func (mr *MockStoreMockRecorder) ListTranscripts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscripts", reflect.TypeOf((*MockStore)(nil).ListTranscripts), arg0, arg1)
+}
+
+// ListUnreadNotifications mocks base method.
+func (m *MockStore) ListUnreadNotifications(arg0 context.Context, arg1 db.ListUnreadNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUnreadNotifications indicates an expected call of ListUnreadNotifications.
+func (mr *MockStoreMockRecorder) ListUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUnreadNotifications", reflect.TypeOf((*MockStore)(nil).ListUnreadNotifications), arg0, arg1)
+}
+
+// ListUserScholarships mocks base method.
+func (m *MockStore) ListUserScholarships(arg0 context.Context, arg1 db.ListUserScholarshipsParams) ([]db.ListUserScholarshipsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserScholarships", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUserScholarshipsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserScholarships indicates an expected call of ListUserScholarships.
+func (mr *MockStoreMockRecorder) ListUserScholarships(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserScholarships", reflect.TypeOf((*MockStore)(nil).ListUserScholarships), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// RecordScholarshipReminder mocks base method.
+func (m *MockStore) RecordScholarshipReminder(arg0 context.Context, arg1 db.RecordScholarshipReminderParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordScholarshipReminder", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordScholarshipReminder indicates an expected call of RecordScholarshipReminder.
+func (mr *MockStoreMockRecorder) RecordScholarshipReminder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordScholarshipReminder", reflect.TypeOf((*MockStore)(nil).RecordScholarshipReminder), arg0, arg1)
+}
+
+// ReportLLMUsage mocks base method.
+func (m *MockStore) ReportLLMUsage(arg0 context.Context, arg1 db.ReportLLMUsageParams) ([]db.ReportLLMUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportLLMUsage", arg0, arg1)
+	ret0, _ := ret[0].([]db.ReportLLMUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportLLMUsage indicates an expected call of ReportLLMUsage.
+func (mr *MockStoreMockRecorder) ReportLLMUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportLLMUsage", reflect.TypeOf((*MockStore)(nil).ReportLLMUsage), arg0, arg1)
+}
+
+// RequeueStaleJobs mocks base method.
+func (m *MockStore) RequeueStaleJobs(arg0 context.Context, arg1 sql.NullTime) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStaleJobs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueStaleJobs indicates an expected call of RequeueStaleJobs.
+func (mr *MockStoreMockRecorder) RequeueStaleJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStaleJobs", reflect.TypeOf((*MockStore)(nil).RequeueStaleJobs), arg0, arg1)
+}
+
+// RetryJob mocks base method.
+func (m *MockStore) RetryJob(arg0 context.Context, arg1 db.RetryJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryJob indicates an expected call of RetryJob.
+func (mr *MockStoreMockRecorder) RetryJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryJob", reflect.TypeOf((*MockStore)(nil).RetryJob), arg0, arg1)
+}
+
+// SumUserLLMTokens mocks base method.
+func (m *MockStore) SumUserLLMTokens(arg0 context.Context, arg1 db.SumUserLLMTokensParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumUserLLMTokens", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumUserLLMTokens indicates an expected call of SumUserLLMTokens.
+func (mr *MockStoreMockRecorder) SumUserLLMTokens(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUserLLMTokens", reflect.TypeOf((*MockStore)(nil).SumUserLLMTokens), arg0, arg1)
+}
+
+// TouchConversation mocks base method.
+func (m *MockStore) TouchConversation(arg0 context.Context, arg1 db.TouchConversationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchConversation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchConversation indicates an expected call of TouchConversation.
+func (mr *MockStoreMockRecorder) TouchConversation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchConversation", reflect.TypeOf((*MockStore)(nil).TouchConversation), arg0, arg1)
+}
+
+// UnassignAdvisorStudent mocks base method.
+func (m *MockStore) UnassignAdvisorStudent(arg0 context.Context, arg1 db.UnassignAdvisorStudentParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignAdvisorStudent", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassignAdvisorStudent indicates an expected call of UnassignAdvisorStudent.
+func (mr *MockStoreMockRecorder) UnassignAdvisorStudent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignAdvisorStudent", reflect.TypeOf((*MockStore)(nil).UnassignAdvisorStudent), arg0, arg1)
+}
+
+// UpdateConversationSummary mocks base method.
+func (m *MockStore) UpdateConversationSummary(arg0 context.Context, arg1 db.UpdateConversationSummaryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConversationSummary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConversationSummary indicates an expected call of UpdateConversationSummary.
+func (mr *MockStoreMockRecorder) UpdateConversationSummary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConversationSummary", reflect.TypeOf((*MockStore)(nil).UpdateConversationSummary), arg0, arg1)
+}
+
+// UpdateCourse mocks base method.
+func (m *MockStore) UpdateCourse(arg0 context.Context, arg1 db.UpdateCourseParams) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourse indicates an expected call of UpdateCourse.
+func (mr *MockStoreMockRecorder) UpdateCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourse", reflect.TypeOf((*MockStore)(nil).UpdateCourse), arg0, arg1)
+}
+
+// UpdateRecommendationPayload mocks base method.
+func (m *MockStore) UpdateRecommendationPayload(arg0 context.Context, arg1 db.UpdateRecommendationPayloadParams) (db.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecommendationPayload", arg0, arg1)
+	ret0, _ := ret[0].(db.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecommendationPayload indicates an expected call of UpdateRecommendationPayload.
+func (mr *MockStoreMockRecorder) UpdateRecommendationPayload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecommendationPayload", reflect.TypeOf((*MockStore)(nil).UpdateRecommendationPayload), arg0, arg1)
+}
+
+// UpdateStudyPlan mocks base method.
+func (m *MockStore) UpdateStudyPlan(arg0 context.Context, arg1 db.UpdateStudyPlanParams) (db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStudyPlan indicates an expected call of UpdateStudyPlan.
+func (mr *MockStoreMockRecorder) UpdateStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStudyPlan", reflect.TypeOf((*MockStore)(nil).UpdateStudyPlan), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpsertCatalogScholarship mocks base method.
+func (m *MockStore) UpsertCatalogScholarship(arg0 context.Context, arg1 db.UpsertCatalogScholarshipParams) (db.ScholarshipCatalog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCatalogScholarship", arg0, arg1)
+	ret0, _ := ret[0].(db.ScholarshipCatalog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCatalogScholarship indicates an expected call of UpsertCatalogScholarship.
+func (mr *MockStoreMockRecorder) UpsertCatalogScholarship(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCatalogScholarship", reflect.TypeOf((*MockStore)(nil).UpsertCatalogScholarship), arg0, arg1)
+}
+
+// UpsertCourse mocks base method.
+func (m *MockStore) UpsertCourse(arg0 context.Context, arg1 db.UpsertCourseParams) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCourse indicates an expected call of UpsertCourse.
+func (mr *MockStoreMockRecorder) UpsertCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCourse", reflect.TypeOf((*MockStore)(nil).UpsertCourse), arg0, arg1)
+}
+
+// UpsertCourseEmbedding mocks base method.
+func (m *MockStore) UpsertCourseEmbedding(arg0 context.Context, arg1 db.UpsertCourseEmbeddingParams) (db.CourseEmbedding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCourseEmbedding", arg0, arg1)
+	ret0, _ := ret[0].(db.CourseEmbedding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCourseEmbedding indicates an expected call of UpsertCourseEmbedding.
+func (mr *MockStoreMockRecorder) UpsertCourseEmbedding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCourseEmbedding", reflect.TypeOf((*MockStore)(nil).UpsertCourseEmbedding), arg0, arg1)
+}
+
+// UpsertCoursePrerequisite mocks base method.
+func (m *MockStore) UpsertCoursePrerequisite(arg0 context.Context, arg1 db.UpsertCoursePrerequisiteParams) (db.CoursePrerequisite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCoursePrerequisite", arg0, arg1)
+	ret0, _ := ret[0].(db.CoursePrerequisite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCoursePrerequisite indicates an expected call of UpsertCoursePrerequisite.
+func (mr *MockStoreMockRecorder) UpsertCoursePrerequisite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCoursePrerequisite", reflect.TypeOf((*MockStore)(nil).UpsertCoursePrerequisite), arg0, arg1)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockStore) UpsertNotificationPreferences(arg0 context.Context, arg1 db.UpsertNotificationPreferencesParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreferences), arg0, arg1)
+}
+
+// UpsertStudentProfile mocks base method.
+func (m *MockStore) UpsertStudentProfile(arg0 context.Context, arg1 db.UpsertStudentProfileParams) (db.StudentProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertStudentProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.StudentProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertStudentProfile indicates an expected call of UpsertStudentProfile.
+func (mr *MockStoreMockRecorder) UpsertStudentProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertStudentProfile", reflect.TypeOf((*MockStore)(nil).UpsertStudentProfile), arg0, arg1)
+}
+
+// UpsertUserScholarshipMatch mocks base method.
+func (m *MockStore) UpsertUserScholarshipMatch(arg0 context.Context, arg1 db.UpsertUserScholarshipMatchParams) (db.UserScholarshipMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserScholarshipMatch", arg0, arg1)
+	ret0, _ := ret[0].(db.UserScholarshipMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserScholarshipMatch indicates an expected call of UpsertUserScholarshipMatch.
+func (mr *MockStoreMockRecorder) UpsertUserScholarshipMatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserScholarshipMatch", reflect.TypeOf((*MockStore)(nil).UpsertUserScholarshipMatch), arg0, arg1)
+}
+
+// UpsertWebSearchCache mocks base method.
+func (m *MockStore) UpsertWebSearchCache(arg0 context.Context, arg1 db.UpsertWebSearchCacheParams) (db.WebSearchCache, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWebSearchCache", arg0, arg1)
+	ret0, _ := ret[0].(db.WebSearchCache)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWebSearchCache indicates an expected call of UpsertWebSearchCache.
+func (mr *MockStoreMockRecorder) UpsertWebSearchCache(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWebSearchCache", reflect.TypeOf((*MockStore)(nil).UpsertWebSearchCache), arg0, arg1)
 }
@@ -0,0 +1,24 @@
+-- db/query/advisor_student.sql
+-- name: AssignAdvisorStudent :exec
+INSERT INTO advisor_students (
+  advisor_username, student_username
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (advisor_username, student_username) DO NOTHING;
+
+-- name: UnassignAdvisorStudent :execrows
+DELETE FROM advisor_students
+WHERE advisor_username = $1 AND student_username = $2;
+
+-- name: ListAdvisorStudents :many
+SELECT users.* FROM users
+JOIN advisor_students ON advisor_students.student_username = users.username
+WHERE advisor_students.advisor_username = $1
+ORDER BY users.username;
+
+-- name: IsAdvisorOf :one
+SELECT EXISTS (
+  SELECT 1 FROM advisor_students
+  WHERE advisor_username = $1 AND student_username = $2
+);
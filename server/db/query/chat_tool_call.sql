@@ -0,0 +1,16 @@
+-- db/query/chat_tool_call.sql
+-- name: CreateChatToolCall :one
+INSERT INTO chat_tool_calls (
+  user_username, conversation_id, tool_call_id, tool_name,
+  arguments, result, status, error, duration_ms
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: ListChatToolCalls :many
+-- Newest first; an empty username lists every user's calls.
+SELECT * FROM chat_tool_calls
+WHERE sqlc.arg(username)::text = '' OR user_username = sqlc.arg(username)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_calls);
@@ -0,0 +1,58 @@
+-- db/query/conversation.sql
+-- name: CreateConversation :one
+INSERT INTO conversations (
+  user_username, recommendation_id, title
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetConversation :one
+SELECT * FROM conversations
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListConversations :many
+SELECT * FROM conversations
+WHERE user_username = $1
+ORDER BY updated_at DESC, id DESC
+LIMIT $2;
+
+-- name: TouchConversation :exec
+-- Bumps updated_at and sets the title if it is still empty.
+UPDATE conversations
+SET updated_at = now(),
+    title = COALESCE(NULLIF(title, ''), sqlc.arg(title)::text)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateConversationSummary :exec
+UPDATE conversations
+SET summary = $2,
+    summarized_until = $3
+WHERE id = $1;
+
+-- name: DeleteConversation :execrows
+DELETE FROM conversations
+WHERE id = $1 AND user_username = $2;
+
+-- name: CreateChatMessage :one
+INSERT INTO chat_messages (
+  conversation_id, role, content, tokens
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListChatMessages :many
+-- Messages after after_id, oldest first.
+SELECT * FROM chat_messages
+WHERE conversation_id = sqlc.arg(conversation_id) AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_messages);
+
+-- name: ListLatestChatMessages :many
+-- The newest messages after after_id, newest first.
+SELECT * FROM chat_messages
+WHERE conversation_id = sqlc.arg(conversation_id) AND id > sqlc.arg(after_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_messages);
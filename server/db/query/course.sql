@@ -15,5 +15,67 @@ SELECT * FROM courses
 ORDER BY id
 LIMIT $1;
 
+-- name: ListCoursesPage :many
+SELECT * FROM courses
+WHERE archived_at IS NULL
+  AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: GetCourseByCode :one
+SELECT * FROM courses
+WHERE code = $1
+LIMIT 1;
+
 -- name: ListAllCourses :many
 SELECT * FROM courses ORDER BY id ASC;
+
+-- name: ListActiveCourses :many
+SELECT * FROM courses
+WHERE archived_at IS NULL
+ORDER BY id ASC;
+
+-- name: UpsertCourse :one
+INSERT INTO courses (
+  code, name, language, grading_scale, organiser,
+  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link
+) VALUES (
+  $1,   $2,   $3,       $4,            $5,
+  $6,               $7,           $8,          $9,           $10
+)
+ON CONFLICT (code) DO UPDATE SET
+  name = EXCLUDED.name,
+  language = EXCLUDED.language,
+  grading_scale = EXCLUDED.grading_scale,
+  organiser = EXCLUDED.organiser,
+  learning_outcomes = EXCLUDED.learning_outcomes,
+  prerequisites = EXCLUDED.prerequisites,
+  teacher_name = EXCLUDED.teacher_name,
+  teacher_email = EXCLUDED.teacher_email,
+  course_link = EXCLUDED.course_link,
+  archived_at = NULL,
+  updated_at = now()
+RETURNING *;
+
+-- name: UpdateCourse :one
+UPDATE courses
+SET code = $2,
+    name = $3,
+    language = $4,
+    grading_scale = $5,
+    organiser = $6,
+    learning_outcomes = $7,
+    prerequisites = $8,
+    teacher_name = $9,
+    teacher_email = $10,
+    course_link = $11,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ArchiveCourse :one
+UPDATE courses
+SET archived_at = COALESCE(archived_at, now()),
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,18 @@
+-- db/query/course_embedding.sql
+-- name: UpsertCourseEmbedding :one
+INSERT INTO course_embeddings (
+  course_id, embedder, content_hash, vector
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (course_id) DO UPDATE
+SET embedder = EXCLUDED.embedder,
+    content_hash = EXCLUDED.content_hash,
+    vector = EXCLUDED.vector,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListCourseEmbeddings :many
+SELECT * FROM course_embeddings
+WHERE embedder = $1
+ORDER BY course_id;
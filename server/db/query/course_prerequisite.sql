@@ -0,0 +1,16 @@
+-- db/query/course_prerequisite.sql
+-- name: UpsertCoursePrerequisite :one
+INSERT INTO course_prerequisites (
+  course_id, expression, source_text
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT (course_id) DO UPDATE
+SET expression = EXCLUDED.expression,
+    source_text = EXCLUDED.source_text,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListCoursePrerequisites :many
+SELECT * FROM course_prerequisites
+ORDER BY course_id;
@@ -0,0 +1,75 @@
+-- db/query/job.sql
+-- name: CreateJob :one
+INSERT INTO jobs (
+  user_username, kind, payload, max_attempts
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetJob :one
+SELECT * FROM jobs
+WHERE id = $1
+LIMIT 1;
+
+-- name: ClaimJob :one
+UPDATE jobs
+SET status = 'running',
+    attempts = attempts + 1,
+    started_at = now(),
+    updated_at = now()
+WHERE id = (
+  SELECT id FROM jobs
+  WHERE status = 'queued' AND run_at <= now()
+  ORDER BY run_at, id
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING *;
+
+-- name: CompleteJob :one
+UPDATE jobs
+SET status = 'succeeded',
+    result = $2,
+    error = NULL,
+    finished_at = now(),
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: RetryJob :one
+UPDATE jobs
+SET status = 'queued',
+    error = $2,
+    run_at = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: FailJob :one
+UPDATE jobs
+SET status = 'failed',
+    error = $2,
+    finished_at = now(),
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: RequeueStaleJobs :execrows
+UPDATE jobs
+SET status = 'queued',
+    run_at = now(),
+    updated_at = now()
+WHERE status = 'running' AND started_at < $1
+  AND attempts < max_attempts;
+
+-- name: FailStaleJobs :execrows
+-- Stale running jobs that used all their attempts, e.g. because they crash
+-- the worker, are failed instead of re-queued.
+UPDATE jobs
+SET status = 'failed',
+    error = 'worker stopped during the last attempt',
+    finished_at = now(),
+    updated_at = now()
+WHERE status = 'running' AND started_at < $1
+  AND attempts >= max_attempts;
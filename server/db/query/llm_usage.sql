@@ -0,0 +1,36 @@
+-- db/query/llm_usage.sql
+-- name: CreateLLMUsage :one
+INSERT INTO llm_usage (
+  user_username, feature, provider, model, prompt_tokens, completion_tokens,
+  total_tokens, estimated, cost_usd, latency_ms, status
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: SumUserLLMTokens :one
+-- Tokens the user's LLM calls used since the given time.
+SELECT COALESCE(SUM(total_tokens), 0)::bigint AS total_tokens
+FROM llm_usage
+WHERE user_username = sqlc.arg(username)::varchar AND created_at >= sqlc.arg(since);
+
+-- name: ReportLLMUsage :many
+-- Usage between from_time and to_time by day, feature and user, newest day
+-- first. Empty username or feature matches all.
+SELECT
+  created_at::date AS day,
+  feature,
+  COALESCE(user_username, '')::text AS username,
+  COUNT(*) AS calls,
+  COUNT(*) FILTER (WHERE status = 'error') AS errors,
+  SUM(prompt_tokens)::bigint AS prompt_tokens,
+  SUM(completion_tokens)::bigint AS completion_tokens,
+  SUM(total_tokens)::bigint AS total_tokens,
+  SUM(cost_usd)::float8 AS cost_usd,
+  AVG(latency_ms)::float8 AS avg_latency_ms
+FROM llm_usage
+WHERE created_at >= sqlc.arg(from_time) AND created_at < sqlc.arg(to_time)
+  AND (sqlc.arg(username)::text = '' OR user_username = sqlc.arg(username))
+  AND (sqlc.arg(feature)::text = '' OR feature = sqlc.arg(feature))
+GROUP BY day, feature, username
+ORDER BY day DESC, total_tokens DESC;
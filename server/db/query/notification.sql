@@ -0,0 +1,52 @@
+-- db/query/notification.sql
+-- name: CreateNotification :one
+INSERT INTO notifications (
+  user_username, kind, title, body, link
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_username = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: ListUnreadNotifications :many
+SELECT * FROM notifications
+WHERE user_username = $1 AND read_at IS NULL
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications
+WHERE user_username = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND user_username = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE user_username = $1 AND read_at IS NULL;
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE user_username = $1
+LIMIT 1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (
+  user_username, deadline_reminders, remind_days_before, channels
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_username) DO UPDATE SET
+  deadline_reminders = EXCLUDED.deadline_reminders,
+  remind_days_before = EXCLUDED.remind_days_before,
+  channels = EXCLUDED.channels,
+  updated_at = now()
+RETURNING *;
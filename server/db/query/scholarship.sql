@@ -1,23 +1,48 @@
 -- db/query/scholarship.sql
--- name: CreateScholarship :one
-INSERT INTO scholarships (
-  user_username, title, description, match_score, link
+-- name: UpsertCatalogScholarship :one
+INSERT INTO scholarship_catalog (
+  url_hash, canonical_url, title, description, provider, amount, currency,
+  deadline, eligible_nationalities, degree_levels, fields_of_study
 ) VALUES (
-  $1, $2, $3, $4, $5
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
+ON CONFLICT (url_hash) DO UPDATE SET
+  title = EXCLUDED.title,
+  description = COALESCE(EXCLUDED.description, scholarship_catalog.description),
+  provider = COALESCE(EXCLUDED.provider, scholarship_catalog.provider),
+  amount = COALESCE(EXCLUDED.amount, scholarship_catalog.amount),
+  currency = COALESCE(EXCLUDED.currency, scholarship_catalog.currency),
+  deadline = COALESCE(EXCLUDED.deadline, scholarship_catalog.deadline),
+  eligible_nationalities = CASE WHEN cardinality(EXCLUDED.eligible_nationalities) > 0
+    THEN EXCLUDED.eligible_nationalities ELSE scholarship_catalog.eligible_nationalities END,
+  degree_levels = CASE WHEN cardinality(EXCLUDED.degree_levels) > 0
+    THEN EXCLUDED.degree_levels ELSE scholarship_catalog.degree_levels END,
+  fields_of_study = CASE WHEN cardinality(EXCLUDED.fields_of_study) > 0
+    THEN EXCLUDED.fields_of_study ELSE scholarship_catalog.fields_of_study END,
+  updated_at = now()
 RETURNING *;
 
--- name: ListScholarshipsByUser :many
-SELECT * FROM scholarships
-WHERE user_username = $1
-ORDER BY match_score DESC;
+-- name: UpsertUserScholarshipMatch :one
+INSERT INTO user_scholarship_matches (
+  user_username, scholarship_id, match_score, search_query
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_username, scholarship_id) DO UPDATE SET
+  match_score = EXCLUDED.match_score,
+  search_query = COALESCE(EXCLUDED.search_query, user_scholarship_matches.search_query),
+  updated_at = now()
+RETURNING *;
 
--- name: ListRecentScholarshipsByUser :many
-SELECT * FROM scholarships
-WHERE user_username = $1
-ORDER BY created_at DESC
+-- name: ListUserScholarships :many
+SELECT c.*, m.match_score, m.search_query, m.updated_at AS matched_at
+FROM user_scholarship_matches m
+JOIN scholarship_catalog c ON c.id = m.scholarship_id
+WHERE m.user_username = $1
+  AND (c.deadline IS NULL OR c.deadline >= CURRENT_DATE)
+ORDER BY m.match_score DESC, c.id
 LIMIT $2;
 
--- name: DeleteScholarship :exec
-DELETE FROM scholarships
-WHERE id = $1 AND user_username = $2;
+-- name: DeleteUserScholarshipMatch :exec
+DELETE FROM user_scholarship_matches
+WHERE user_username = $1 AND scholarship_id = $2;
@@ -0,0 +1,30 @@
+-- db/query/scholarship_reminder.sql
+-- name: ListDueScholarshipReminders :many
+-- Matched scholarships whose deadline falls within the user's reminder
+-- window (7 days without saved preferences) and were not reminded of yet.
+SELECT m.user_username, u.email, u.full_name, c.id AS scholarship_id, c.title,
+       c.canonical_url, c.deadline, m.match_score,
+       COALESCE(p.channels, '{in_app}')::text[] AS channels
+FROM user_scholarship_matches m
+JOIN scholarship_catalog c ON c.id = m.scholarship_id
+JOIN users u ON u.username = m.user_username
+LEFT JOIN notification_preferences p ON p.user_username = m.user_username
+LEFT JOIN scholarship_reminders r
+  ON r.user_username = m.user_username AND r.scholarship_id = c.id AND r.deadline = c.deadline
+WHERE c.deadline BETWEEN CURRENT_DATE AND CURRENT_DATE + COALESCE(p.remind_days_before, 7)
+  AND COALESCE(p.deadline_reminders, true)
+  AND r.user_username IS NULL
+ORDER BY c.deadline, m.user_username
+LIMIT $1;
+
+-- name: RecordScholarshipReminder :execrows
+INSERT INTO scholarship_reminders (
+  user_username, scholarship_id, deadline
+) VALUES (
+  $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteScholarshipReminder :exec
+DELETE FROM scholarship_reminders
+WHERE user_username = $1 AND scholarship_id = $2 AND deadline = $3;
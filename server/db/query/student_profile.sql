@@ -0,0 +1,20 @@
+-- db/query/student_profile.sql
+-- name: GetStudentProfile :one
+SELECT * FROM student_profiles
+WHERE user_username = $1
+LIMIT 1;
+
+-- name: UpsertStudentProfile :one
+INSERT INTO student_profiles (
+  user_username, nationality, degree_level, field_of_study, target_countries, languages
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (user_username) DO UPDATE SET
+  nationality = EXCLUDED.nationality,
+  degree_level = EXCLUDED.degree_level,
+  field_of_study = EXCLUDED.field_of_study,
+  target_countries = EXCLUDED.target_countries,
+  languages = EXCLUDED.languages,
+  updated_at = now()
+RETURNING *;
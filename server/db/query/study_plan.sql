@@ -0,0 +1,28 @@
+-- db/query/study_plan.sql
+-- name: CreateStudyPlan :one
+INSERT INTO study_plans (
+  user_username, transcript_id, title, target, plan
+) VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListStudyPlans :many
+SELECT * FROM study_plans
+WHERE user_username = $1
+ORDER BY id DESC;
+
+-- name: GetStudyPlan :one
+SELECT * FROM study_plans WHERE id = $1 LIMIT 1;
+
+-- name: UpdateStudyPlan :one
+UPDATE study_plans
+SET title = $1,
+    transcript_id = $2,
+    target = $3,
+    plan = $4,
+    updated_at = now()
+WHERE id = $5 AND user_username = $6
+RETURNING *;
+
+-- name: DeleteStudyPlan :exec
+DELETE FROM study_plans
+WHERE id = $1 AND user_username = $2;
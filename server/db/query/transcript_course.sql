@@ -0,0 +1,13 @@
+-- db/query/transcript_course.sql
+-- name: CreateTranscriptCourse :one
+INSERT INTO transcript_courses (
+  transcript_id, course_code, course_name, credits, grade, term, status, completed_on, parser
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: ListTranscriptCourses :many
+SELECT * FROM transcript_courses
+WHERE transcript_id = $1
+ORDER BY completed_on ASC NULLS LAST, id ASC;
@@ -0,0 +1,35 @@
+-- db/query/web_search.sql
+-- name: GetWebSearchCache :one
+SELECT * FROM web_search_cache
+WHERE provider = $1 AND query_key = $2
+LIMIT 1;
+
+-- name: UpsertWebSearchCache :one
+INSERT INTO web_search_cache (
+  provider, query_key, query, results, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (provider, query_key) DO UPDATE SET
+  query = EXCLUDED.query,
+  results = EXCLUDED.results,
+  created_at = now(),
+  expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: ClaimWebSearchQuota :one
+-- Counts one request against the provider's daily quota. No row comes back
+-- once daily_limit requests were counted for that day.
+INSERT INTO web_search_daily_usage (
+  provider, day, used
+) VALUES (
+  $1, $2, 1
+)
+ON CONFLICT (provider, day) DO UPDATE SET
+  used = web_search_daily_usage.used + 1
+WHERE web_search_daily_usage.used < sqlc.arg(daily_limit)
+RETURNING used;
+
+-- name: GetWebSearchUsage :one
+SELECT used FROM web_search_daily_usage
+WHERE provider = $1 AND day = $2;
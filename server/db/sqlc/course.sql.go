@@ -10,6 +10,36 @@ import (
 	"database/sql"
 )
 
+const archiveCourse = `-- name: ArchiveCourse :one
+UPDATE courses
+SET archived_at = COALESCE(archived_at, now()),
+    updated_at = now()
+WHERE id = $1
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at
+`
+
+func (q *Queries) ArchiveCourse(ctx context.Context, id int64) (Course, error) {
+	row := q.db.QueryRowContext(ctx, archiveCourse, id)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createCourse = `-- name: CreateCourse :one
 
 INSERT INTO courses (
@@ -19,7 +49,7 @@ INSERT INTO courses (
   $1,   $2,   $3,       $4,            $5,
   $6,               $7,           $8,          $9,           $10
 )
-RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at
 `
 
 type CreateCourseParams struct {
@@ -63,12 +93,86 @@ func (q *Queries) CreateCourse(ctx context.Context, arg CreateCourseParams) (Cou
 		&i.TeacherEmail,
 		&i.CourseLink,
 		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCourseByCode = `-- name: GetCourseByCode :one
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at FROM courses
+WHERE code = $1
+LIMIT 1
+`
+
+func (q *Queries) GetCourseByCode(ctx context.Context, code string) (Course, error) {
+	row := q.db.QueryRowContext(ctx, getCourseByCode, code)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const listActiveCourses = `-- name: ListActiveCourses :many
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at FROM courses
+WHERE archived_at IS NULL
+ORDER BY id ASC
+`
+
+func (q *Queries) ListActiveCourses(ctx context.Context) ([]Course, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveCourses)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Course{}
+	for rows.Next() {
+		var i Course
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.Language,
+			&i.GradingScale,
+			&i.Organiser,
+			&i.LearningOutcomes,
+			&i.Prerequisites,
+			&i.TeacherName,
+			&i.TeacherEmail,
+			&i.CourseLink,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAllCourses = `-- name: ListAllCourses :many
-SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at FROM courses ORDER BY id ASC
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at FROM courses ORDER BY id ASC
 `
 
 func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
@@ -93,6 +197,8 @@ func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
 			&i.TeacherEmail,
 			&i.CourseLink,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -108,7 +214,7 @@ func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
 }
 
 const listCourses = `-- name: ListCourses :many
-SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at FROM courses
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at FROM courses
 ORDER BY id
 LIMIT $1
 `
@@ -135,6 +241,59 @@ func (q *Queries) ListCourses(ctx context.Context, limit int64) ([]Course, error
 			&i.TeacherEmail,
 			&i.CourseLink,
 			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCoursesPage = `-- name: ListCoursesPage :many
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at FROM courses
+WHERE archived_at IS NULL
+  AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListCoursesPageParams struct {
+	ID    int64 `json:"id"`
+	Limit int64 `json:"limit"`
+}
+
+func (q *Queries) ListCoursesPage(ctx context.Context, arg ListCoursesPageParams) ([]Course, error) {
+	rows, err := q.db.QueryContext(ctx, listCoursesPage, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Course{}
+	for rows.Next() {
+		var i Course
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.Language,
+			&i.GradingScale,
+			&i.Organiser,
+			&i.LearningOutcomes,
+			&i.Prerequisites,
+			&i.TeacherName,
+			&i.TeacherEmail,
+			&i.CourseLink,
+			&i.CreatedAt,
+			&i.ArchivedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -148,3 +307,137 @@ func (q *Queries) ListCourses(ctx context.Context, limit int64) ([]Course, error
 	}
 	return items, nil
 }
+
+const updateCourse = `-- name: UpdateCourse :one
+UPDATE courses
+SET code = $2,
+    name = $3,
+    language = $4,
+    grading_scale = $5,
+    organiser = $6,
+    learning_outcomes = $7,
+    prerequisites = $8,
+    teacher_name = $9,
+    teacher_email = $10,
+    course_link = $11,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at
+`
+
+type UpdateCourseParams struct {
+	ID               int64          `json:"id"`
+	Code             string         `json:"code"`
+	Name             string         `json:"name"`
+	Language         sql.NullString `json:"language"`
+	GradingScale     sql.NullString `json:"grading_scale"`
+	Organiser        sql.NullString `json:"organiser"`
+	LearningOutcomes sql.NullString `json:"learning_outcomes"`
+	Prerequisites    sql.NullString `json:"prerequisites"`
+	TeacherName      sql.NullString `json:"teacher_name"`
+	TeacherEmail     sql.NullString `json:"teacher_email"`
+	CourseLink       sql.NullString `json:"course_link"`
+}
+
+func (q *Queries) UpdateCourse(ctx context.Context, arg UpdateCourseParams) (Course, error) {
+	row := q.db.QueryRowContext(ctx, updateCourse,
+		arg.ID,
+		arg.Code,
+		arg.Name,
+		arg.Language,
+		arg.GradingScale,
+		arg.Organiser,
+		arg.LearningOutcomes,
+		arg.Prerequisites,
+		arg.TeacherName,
+		arg.TeacherEmail,
+		arg.CourseLink,
+	)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertCourse = `-- name: UpsertCourse :one
+INSERT INTO courses (
+  code, name, language, grading_scale, organiser,
+  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link
+) VALUES (
+  $1,   $2,   $3,       $4,            $5,
+  $6,               $7,           $8,          $9,           $10
+)
+ON CONFLICT (code) DO UPDATE SET
+  name = EXCLUDED.name,
+  language = EXCLUDED.language,
+  grading_scale = EXCLUDED.grading_scale,
+  organiser = EXCLUDED.organiser,
+  learning_outcomes = EXCLUDED.learning_outcomes,
+  prerequisites = EXCLUDED.prerequisites,
+  teacher_name = EXCLUDED.teacher_name,
+  teacher_email = EXCLUDED.teacher_email,
+  course_link = EXCLUDED.course_link,
+  archived_at = NULL,
+  updated_at = now()
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, archived_at, updated_at
+`
+
+type UpsertCourseParams struct {
+	Code             string         `json:"code"`
+	Name             string         `json:"name"`
+	Language         sql.NullString `json:"language"`
+	GradingScale     sql.NullString `json:"grading_scale"`
+	Organiser        sql.NullString `json:"organiser"`
+	LearningOutcomes sql.NullString `json:"learning_outcomes"`
+	Prerequisites    sql.NullString `json:"prerequisites"`
+	TeacherName      sql.NullString `json:"teacher_name"`
+	TeacherEmail     sql.NullString `json:"teacher_email"`
+	CourseLink       sql.NullString `json:"course_link"`
+}
+
+func (q *Queries) UpsertCourse(ctx context.Context, arg UpsertCourseParams) (Course, error) {
+	row := q.db.QueryRowContext(ctx, upsertCourse,
+		arg.Code,
+		arg.Name,
+		arg.Language,
+		arg.GradingScale,
+		arg.Organiser,
+		arg.LearningOutcomes,
+		arg.Prerequisites,
+		arg.TeacherName,
+		arg.TeacherEmail,
+		arg.CourseLink,
+	)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.ArchivedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
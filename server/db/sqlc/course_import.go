@@ -0,0 +1,149 @@
+// db/sqlc/course_import.go
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ImportCoursesTxParams contains the input of a catalog import.
+type ImportCoursesTxParams struct {
+	Courses []UpsertCourseParams `json:"courses"`
+	// DryRun only reports the diff; nothing is written.
+	DryRun bool `json:"dry_run"`
+	// ArchiveMissing archives active courses that are not in Courses.
+	ArchiveMissing bool `json:"archive_missing"`
+}
+
+// CourseChange names the columns of an existing course an import rewrites.
+type CourseChange struct {
+	Code   string   `json:"code"`
+	Fields []string `json:"fields"`
+}
+
+// ImportCoursesTxResult is the diff of an import against the catalog.
+// Removed lists active courses missing from the import; they are only
+// archived when ArchiveMissing is set.
+type ImportCoursesTxResult struct {
+	Added     []string       `json:"added"`
+	Changed   []CourseChange `json:"changed"`
+	Unchanged []string       `json:"unchanged"`
+	Removed   []string       `json:"removed"`
+	Applied   bool           `json:"applied"`
+}
+
+// ImportCoursesTx diffs the courses against the catalog and, unless DryRun,
+// upserts the added and changed ones (and archives the removed ones when
+// ArchiveMissing) in one transaction.
+func (store *SQLStore) ImportCoursesTx(ctx context.Context, arg ImportCoursesTxParams) (ImportCoursesTxResult, error) {
+	var result ImportCoursesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		existing, err := q.ListAllCourses(ctx)
+		if err != nil {
+			return err
+		}
+
+		var upserts []UpsertCourseParams
+		var archives []int64
+		result, upserts, archives = DiffCourses(existing, arg.Courses)
+		if arg.DryRun {
+			return nil
+		}
+
+		for _, course := range upserts {
+			if _, err := q.UpsertCourse(ctx, course); err != nil {
+				return err
+			}
+		}
+		if arg.ArchiveMissing {
+			for _, id := range archives {
+				if _, err := q.ArchiveCourse(ctx, id); err != nil {
+					return err
+				}
+			}
+		}
+		result.Applied = true
+		return nil
+	})
+
+	return result, err
+}
+
+// DiffCourses compares incoming courses with the existing catalog by code.
+// It returns the diff, the courses to upsert and the IDs of active courses
+// missing from incoming. Re-importing an archived course counts as a change.
+func DiffCourses(existing []Course, incoming []UpsertCourseParams) (ImportCoursesTxResult, []UpsertCourseParams, []int64) {
+	result := ImportCoursesTxResult{
+		Added:     []string{},
+		Changed:   []CourseChange{},
+		Unchanged: []string{},
+		Removed:   []string{},
+	}
+
+	byCode := make(map[string]Course, len(existing))
+	for _, course := range existing {
+		byCode[course.Code] = course
+	}
+
+	var upserts []UpsertCourseParams
+	seen := make(map[string]bool, len(incoming))
+	for _, course := range incoming {
+		seen[course.Code] = true
+
+		current, ok := byCode[course.Code]
+		if !ok {
+			result.Added = append(result.Added, course.Code)
+			upserts = append(upserts, course)
+			continue
+		}
+
+		fields := changedCourseFields(current, course)
+		if len(fields) == 0 {
+			result.Unchanged = append(result.Unchanged, course.Code)
+			continue
+		}
+		result.Changed = append(result.Changed, CourseChange{Code: course.Code, Fields: fields})
+		upserts = append(upserts, course)
+	}
+
+	var archives []int64
+	for _, course := range existing {
+		if !seen[course.Code] && !course.ArchivedAt.Valid {
+			result.Removed = append(result.Removed, course.Code)
+			archives = append(archives, course.ID)
+		}
+	}
+	return result, upserts, archives
+}
+
+func changedCourseFields(current Course, next UpsertCourseParams) []string {
+	var fields []string
+	if current.Name != next.Name {
+		fields = append(fields, "name")
+	}
+	columns := []struct {
+		name      string
+		old, next sql.NullString
+	}{
+		{"language", current.Language, next.Language},
+		{"grading_scale", current.GradingScale, next.GradingScale},
+		{"organiser", current.Organiser, next.Organiser},
+		{"learning_outcomes", current.LearningOutcomes, next.LearningOutcomes},
+		{"prerequisites", current.Prerequisites, next.Prerequisites},
+		{"teacher_name", current.TeacherName, next.TeacherName},
+		{"teacher_email", current.TeacherEmail, next.TeacherEmail},
+		{"course_link", current.CourseLink, next.CourseLink},
+	}
+	for _, col := range columns {
+		// NULL and "" are the same value to an import file
+		if col.old.String != col.next.String {
+			fields = append(fields, col.name)
+		}
+	}
+	if current.ArchivedAt.Valid {
+		fields = append(fields, "archived_at")
+	}
+	return fields
+}
@@ -0,0 +1,42 @@
+// db/sqlc/course_import_test.go
+
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffCourses(t *testing.T) {
+	existing := []Course{
+		{ID: 1, Code: "KOGS1000", Name: "Intro"},
+		{ID: 2, Code: "KOGS1001", Name: "Cognition", TeacherName: sql.NullString{String: "Aino", Valid: true}},
+		{ID: 3, Code: "KOGS1002", Name: "Perception"},
+		{ID: 4, Code: "KOGS1003", Name: "Retired", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+		{ID: 5, Code: "KOGS1004", Name: "Old", ArchivedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+	}
+	incoming := []UpsertCourseParams{
+		{Code: "KOGS1000", Name: "Intro"},
+		{Code: "KOGS1001", Name: "Cognition 2", TeacherName: sql.NullString{String: "Eero", Valid: true}},
+		{Code: "KOGS1003", Name: "Retired"},
+		{Code: "KOGS2000", Name: "New"},
+	}
+
+	result, upserts, archives := DiffCourses(existing, incoming)
+
+	require.Equal(t, []string{"KOGS2000"}, result.Added)
+	require.Equal(t, []CourseChange{
+		{Code: "KOGS1001", Fields: []string{"name", "teacher_name"}},
+		{Code: "KOGS1003", Fields: []string{"archived_at"}},
+	}, result.Changed)
+	require.Equal(t, []string{"KOGS1000"}, result.Unchanged)
+	// Already archived courses are not reported again
+	require.Equal(t, []string{"KOGS1002"}, result.Removed)
+	require.False(t, result.Applied)
+
+	require.Len(t, upserts, 3)
+	require.Equal(t, []int64{3}, archives)
+}
@@ -8,8 +8,50 @@ import (
 	"database/sql"
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type AdvisorStudent struct {
+	AdvisorUsername string    `json:"advisor_username"`
+	StudentUsername string    `json:"student_username"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type ChatMessage struct {
+	ID             int64     `json:"id"`
+	ConversationID int64     `json:"conversation_id"`
+	Role           string    `json:"role"`
+	Content        string    `json:"content"`
+	Tokens         int32     `json:"tokens"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ChatToolCall struct {
+	ID             int64           `json:"id"`
+	UserUsername   string          `json:"user_username"`
+	ConversationID sql.NullInt64   `json:"conversation_id"`
+	ToolCallID     string          `json:"tool_call_id"`
+	ToolName       string          `json:"tool_name"`
+	Arguments      json.RawMessage `json:"arguments"`
+	Result         json.RawMessage `json:"result"`
+	Status         string          `json:"status"`
+	Error          sql.NullString  `json:"error"`
+	DurationMs     int32           `json:"duration_ms"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type Conversation struct {
+	ID               int64          `json:"id"`
+	UserUsername     string         `json:"user_username"`
+	RecommendationID sql.NullInt64  `json:"recommendation_id"`
+	Title            string         `json:"title"`
+	Summary          sql.NullString `json:"summary"`
+	SummarizedUntil  int64          `json:"summarized_until"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
 type Course struct {
 	ID               int64          `json:"id"`
 	Code             string         `json:"code"`
@@ -23,6 +65,75 @@ type Course struct {
 	TeacherEmail     sql.NullString `json:"teacher_email"`
 	CourseLink       sql.NullString `json:"course_link"`
 	CreatedAt        time.Time      `json:"created_at"`
+	ArchivedAt       sql.NullTime   `json:"archived_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type CourseEmbedding struct {
+	CourseID    int64     `json:"course_id"`
+	Embedder    string    `json:"embedder"`
+	ContentHash string    `json:"content_hash"`
+	Vector      []byte    `json:"vector"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CoursePrerequisite struct {
+	CourseID   int64           `json:"course_id"`
+	Expression json.RawMessage `json:"expression"`
+	SourceText string          `json:"source_text"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+type Job struct {
+	ID           int64           `json:"id"`
+	UserUsername string          `json:"user_username"`
+	Kind         string          `json:"kind"`
+	Status       string          `json:"status"`
+	Payload      json.RawMessage `json:"payload"`
+	Result       json.RawMessage `json:"result"`
+	Error        sql.NullString  `json:"error"`
+	Attempts     int32           `json:"attempts"`
+	MaxAttempts  int32           `json:"max_attempts"`
+	RunAt        time.Time       `json:"run_at"`
+	StartedAt    sql.NullTime    `json:"started_at"`
+	FinishedAt   sql.NullTime    `json:"finished_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+type LlmUsage struct {
+	ID               int64          `json:"id"`
+	UserUsername     sql.NullString `json:"user_username"`
+	Feature          string         `json:"feature"`
+	Provider         string         `json:"provider"`
+	Model            string         `json:"model"`
+	PromptTokens     int32          `json:"prompt_tokens"`
+	CompletionTokens int32          `json:"completion_tokens"`
+	TotalTokens      int32          `json:"total_tokens"`
+	Estimated        bool           `json:"estimated"`
+	CostUsd          float64        `json:"cost_usd"`
+	LatencyMs        int32          `json:"latency_ms"`
+	Status           string         `json:"status"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+type Notification struct {
+	ID           int64          `json:"id"`
+	UserUsername string         `json:"user_username"`
+	Kind         string         `json:"kind"`
+	Title        string         `json:"title"`
+	Body         string         `json:"body"`
+	Link         sql.NullString `json:"link"`
+	ReadAt       sql.NullTime   `json:"read_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	UserUsername      string    `json:"user_username"`
+	DeadlineReminders bool      `json:"deadline_reminders"`
+	RemindDaysBefore  int32     `json:"remind_days_before"`
+	Channels          []string  `json:"channels"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 type Recommendation struct {
@@ -34,14 +145,61 @@ type Recommendation struct {
 	CreatedAt    time.Time       `json:"created_at"`
 }
 
-type Scholarship struct {
+type ScholarshipCatalog struct {
+	ID                    int64           `json:"id"`
+	UrlHash               string          `json:"url_hash"`
+	CanonicalUrl          string          `json:"canonical_url"`
+	Title                 string          `json:"title"`
+	Description           sql.NullString  `json:"description"`
+	Provider              sql.NullString  `json:"provider"`
+	Amount                sql.NullFloat64 `json:"amount"`
+	Currency              sql.NullString  `json:"currency"`
+	Deadline              sql.NullTime    `json:"deadline"`
+	EligibleNationalities []string        `json:"eligible_nationalities"`
+	DegreeLevels          []string        `json:"degree_levels"`
+	FieldsOfStudy         []string        `json:"fields_of_study"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+}
+
+type ScholarshipReminder struct {
+	UserUsername  string    `json:"user_username"`
+	ScholarshipID int64     `json:"scholarship_id"`
+	Deadline      time.Time `json:"deadline"`
+	SentAt        time.Time `json:"sent_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	RefreshToken string    `json:"refresh_token"`
+	UserAgent    string    `json:"user_agent"`
+	ClientIp     string    `json:"client_ip"`
+	IsBlocked    bool      `json:"is_blocked"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type StudentProfile struct {
+	UserUsername    string         `json:"user_username"`
+	Nationality     sql.NullString `json:"nationality"`
+	DegreeLevel     sql.NullString `json:"degree_level"`
+	FieldOfStudy    sql.NullString `json:"field_of_study"`
+	TargetCountries []string       `json:"target_countries"`
+	Languages       []string       `json:"languages"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+type StudyPlan struct {
 	ID           int64           `json:"id"`
 	UserUsername string          `json:"user_username"`
+	TranscriptID sql.NullInt64   `json:"transcript_id"`
 	Title        string          `json:"title"`
-	Description  sql.NullString  `json:"description"`
-	MatchScore   sql.NullFloat64 `json:"match_score"`
-	Link         sql.NullString  `json:"link"`
+	Target       json.RawMessage `json:"target"`
+	Plan         json.RawMessage `json:"plan"`
 	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
 type Summary struct {
@@ -62,6 +220,20 @@ type Transcript struct {
 	CreatedAt     time.Time             `json:"created_at"`
 }
 
+type TranscriptCourse struct {
+	ID           int64           `json:"id"`
+	TranscriptID int64           `json:"transcript_id"`
+	CourseCode   string          `json:"course_code"`
+	CourseName   string          `json:"course_name"`
+	Credits      sql.NullFloat64 `json:"credits"`
+	Grade        sql.NullString  `json:"grade"`
+	Term         sql.NullString  `json:"term"`
+	Status       string          `json:"status"`
+	CompletedOn  sql.NullTime    `json:"completed_on"`
+	Parser       string          `json:"parser"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
 type User struct {
 	Username          string    `json:"username"`
 	HashedPassword    string    `json:"hashed_password"`
@@ -69,4 +241,29 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
+}
+
+type UserScholarshipMatch struct {
+	UserUsername  string         `json:"user_username"`
+	ScholarshipID int64          `json:"scholarship_id"`
+	MatchScore    float64        `json:"match_score"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	SearchQuery   sql.NullString `json:"search_query"`
+}
+
+type WebSearchCache struct {
+	Provider  string          `json:"provider"`
+	QueryKey  string          `json:"query_key"`
+	Query     string          `json:"query"`
+	Results   json.RawMessage `json:"results"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+type WebSearchDailyUsage struct {
+	Provider string    `json:"provider"`
+	Day      time.Time `json:"day"`
+	Used     int32     `json:"used"`
 }
@@ -6,35 +6,130 @@ package db
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
+	ArchiveCourse(ctx context.Context, id int64) (Course, error)
+	// db/query/advisor_student.sql
+	AssignAdvisorStudent(ctx context.Context, arg AssignAdvisorStudentParams) error
+	BlockSession(ctx context.Context, arg BlockSessionParams) (Session, error)
+	ClaimJob(ctx context.Context) (Job, error)
+	// Counts one request against the provider's daily quota. No row comes back
+	// once daily_limit requests were counted for that day.
+	ClaimWebSearchQuota(ctx context.Context, arg ClaimWebSearchQuotaParams) (int32, error)
+	CompleteJob(ctx context.Context, arg CompleteJobParams) (Job, error)
+	CountUnreadNotifications(ctx context.Context, userUsername string) (int64, error)
+	CreateChatMessage(ctx context.Context, arg CreateChatMessageParams) (ChatMessage, error)
+	// db/query/chat_tool_call.sql
+	CreateChatToolCall(ctx context.Context, arg CreateChatToolCallParams) (ChatToolCall, error)
+	// db/query/conversation.sql
+	CreateConversation(ctx context.Context, arg CreateConversationParams) (Conversation, error)
 	// server/db/query/course.sql
 	CreateCourse(ctx context.Context, arg CreateCourseParams) (Course, error)
+	// db/query/job.sql
+	CreateJob(ctx context.Context, arg CreateJobParams) (Job, error)
+	// db/query/llm_usage.sql
+	CreateLLMUsage(ctx context.Context, arg CreateLLMUsageParams) (LlmUsage, error)
+	// db/query/notification.sql
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
 	// db/query/recommendation.sql
 	CreateRecommendation(ctx context.Context, arg CreateRecommendationParams) (Recommendation, error)
-	// db/query/scholarship.sql
-	CreateScholarship(ctx context.Context, arg CreateScholarshipParams) (Scholarship, error)
+	// db/query/session.sql
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	// db/query/study_plan.sql
+	CreateStudyPlan(ctx context.Context, arg CreateStudyPlanParams) (StudyPlan, error)
 	// db/query/summary.sql
 	CreateSummary(ctx context.Context, arg CreateSummaryParams) (Summary, error)
 	// db/query/transcript.sql
 	CreateTranscript(ctx context.Context, arg CreateTranscriptParams) (Transcript, error)
+	// db/query/transcript_course.sql
+	CreateTranscriptCourse(ctx context.Context, arg CreateTranscriptCourseParams) (TranscriptCourse, error)
 	// db/query/user.sql
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteScholarship(ctx context.Context, arg DeleteScholarshipParams) error
+	DeleteConversation(ctx context.Context, arg DeleteConversationParams) (int64, error)
+	DeleteScholarshipReminder(ctx context.Context, arg DeleteScholarshipReminderParams) error
+	DeleteStudyPlan(ctx context.Context, arg DeleteStudyPlanParams) error
 	DeleteSummary(ctx context.Context, arg DeleteSummaryParams) error
+	DeleteUserScholarshipMatch(ctx context.Context, arg DeleteUserScholarshipMatchParams) error
+	FailJob(ctx context.Context, arg FailJobParams) (Job, error)
+	// Stale running jobs that used all their attempts, e.g. because they crash
+	// the worker, are failed instead of re-queued.
+	FailStaleJobs(ctx context.Context, startedAt sql.NullTime) (int64, error)
+	GetConversation(ctx context.Context, id int64) (Conversation, error)
+	GetCourseByCode(ctx context.Context, code string) (Course, error)
+	GetJob(ctx context.Context, id int64) (Job, error)
+	GetNotificationPreferences(ctx context.Context, userUsername string) (NotificationPreference, error)
 	GetRecommendation(ctx context.Context, id int64) (Recommendation, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// db/query/student_profile.sql
+	GetStudentProfile(ctx context.Context, userUsername string) (StudentProfile, error)
+	GetStudyPlan(ctx context.Context, id int64) (StudyPlan, error)
 	GetSummary(ctx context.Context, arg GetSummaryParams) (Summary, error)
 	GetTranscript(ctx context.Context, id int64) (Transcript, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	// db/query/web_search.sql
+	GetWebSearchCache(ctx context.Context, arg GetWebSearchCacheParams) (WebSearchCache, error)
+	GetWebSearchUsage(ctx context.Context, arg GetWebSearchUsageParams) (int32, error)
+	IsAdvisorOf(ctx context.Context, arg IsAdvisorOfParams) (bool, error)
+	ListActiveSessions(ctx context.Context, username string) ([]Session, error)
+	ListActiveCourses(ctx context.Context) ([]Course, error)
+	ListAdvisorStudents(ctx context.Context, advisorUsername string) ([]User, error)
 	ListAllCourses(ctx context.Context) ([]Course, error)
+	// Messages after after_id, oldest first.
+	ListChatMessages(ctx context.Context, arg ListChatMessagesParams) ([]ChatMessage, error)
+	// Newest first; an empty username lists every user's calls.
+	ListChatToolCalls(ctx context.Context, arg ListChatToolCallsParams) ([]ChatToolCall, error)
+	ListConversations(ctx context.Context, arg ListConversationsParams) ([]Conversation, error)
+	ListCourseEmbeddings(ctx context.Context, embedder string) ([]CourseEmbedding, error)
+	ListCoursePrerequisites(ctx context.Context) ([]CoursePrerequisite, error)
 	ListCourses(ctx context.Context, limit int64) ([]Course, error)
-	ListRecentScholarshipsByUser(ctx context.Context, arg ListRecentScholarshipsByUserParams) ([]Scholarship, error)
+	ListCoursesPage(ctx context.Context, arg ListCoursesPageParams) ([]Course, error)
+	// db/query/scholarship_reminder.sql
+	// Matched scholarships whose deadline falls within the user's reminder
+	// window (7 days without saved preferences) and were not reminded of yet.
+	ListDueScholarshipReminders(ctx context.Context, limit int64) ([]ListDueScholarshipRemindersRow, error)
+	// The newest messages after after_id, newest first.
+	ListLatestChatMessages(ctx context.Context, arg ListLatestChatMessagesParams) ([]ChatMessage, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
 	ListRecommendations(ctx context.Context, userUsername string) ([]ListRecommendationsRow, error)
-	ListScholarshipsByUser(ctx context.Context, userUsername string) ([]Scholarship, error)
+	ListStudyPlans(ctx context.Context, userUsername string) ([]StudyPlan, error)
 	ListSummaries(ctx context.Context, userUsername string) ([]Summary, error)
+	ListTranscriptCourses(ctx context.Context, transcriptID int64) ([]TranscriptCourse, error)
 	ListTranscripts(ctx context.Context, userUsername string) ([]ListTranscriptsRow, error)
+	ListUnreadNotifications(ctx context.Context, arg ListUnreadNotificationsParams) ([]Notification, error)
+	ListUserScholarships(ctx context.Context, arg ListUserScholarshipsParams) ([]ListUserScholarshipsRow, error)
+	MarkAllNotificationsRead(ctx context.Context, userUsername string) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	RecordScholarshipReminder(ctx context.Context, arg RecordScholarshipReminderParams) (int64, error)
+	// Usage between from_time and to_time by day, feature and user, newest day
+	// first. Empty username or feature matches all.
+	ReportLLMUsage(ctx context.Context, arg ReportLLMUsageParams) ([]ReportLLMUsageRow, error)
+	RequeueStaleJobs(ctx context.Context, startedAt sql.NullTime) (int64, error)
+	RetryJob(ctx context.Context, arg RetryJobParams) (Job, error)
+	// Tokens the user's LLM calls used since the given time.
+	SumUserLLMTokens(ctx context.Context, arg SumUserLLMTokensParams) (int64, error)
+	// Bumps updated_at and sets the title if it is still empty.
+	TouchConversation(ctx context.Context, arg TouchConversationParams) error
+	UnassignAdvisorStudent(ctx context.Context, arg UnassignAdvisorStudentParams) (int64, error)
+	UpdateConversationSummary(ctx context.Context, arg UpdateConversationSummaryParams) error
+	UpdateCourse(ctx context.Context, arg UpdateCourseParams) (Course, error)
 	UpdateRecommendationPayload(ctx context.Context, arg UpdateRecommendationPayloadParams) (Recommendation, error)
+	UpdateStudyPlan(ctx context.Context, arg UpdateStudyPlanParams) (StudyPlan, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	// db/query/scholarship.sql
+	UpsertCatalogScholarship(ctx context.Context, arg UpsertCatalogScholarshipParams) (ScholarshipCatalog, error)
+	UpsertCourse(ctx context.Context, arg UpsertCourseParams) (Course, error)
+	// db/query/course_embedding.sql
+	UpsertCourseEmbedding(ctx context.Context, arg UpsertCourseEmbeddingParams) (CourseEmbedding, error)
+	// db/query/course_prerequisite.sql
+	UpsertCoursePrerequisite(ctx context.Context, arg UpsertCoursePrerequisiteParams) (CoursePrerequisite, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error)
+	UpsertStudentProfile(ctx context.Context, arg UpsertStudentProfileParams) (StudentProfile, error)
+	UpsertUserScholarshipMatch(ctx context.Context, arg UpsertUserScholarshipMatchParams) (UserScholarshipMatch, error)
+	UpsertWebSearchCache(ctx context.Context, arg UpsertWebSearchCacheParams) (WebSearchCache, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -8,91 +8,88 @@ package db
 import (
 	"context"
 	"database/sql"
-)
+	"time"
 
-const createScholarship = `-- name: CreateScholarship :one
-INSERT INTO scholarships (
-  user_username, title, description, match_score, link
-) VALUES (
-  $1, $2, $3, $4, $5
+	"github.com/lib/pq"
 )
-RETURNING id, user_username, title, description, match_score, link, created_at
-`
 
-type CreateScholarshipParams struct {
-	UserUsername string          `json:"user_username"`
-	Title        string          `json:"title"`
-	Description  sql.NullString  `json:"description"`
-	MatchScore   sql.NullFloat64 `json:"match_score"`
-	Link         sql.NullString  `json:"link"`
-}
-
-// db/query/scholarship.sql
-func (q *Queries) CreateScholarship(ctx context.Context, arg CreateScholarshipParams) (Scholarship, error) {
-	row := q.db.QueryRowContext(ctx, createScholarship,
-		arg.UserUsername,
-		arg.Title,
-		arg.Description,
-		arg.MatchScore,
-		arg.Link,
-	)
-	var i Scholarship
-	err := row.Scan(
-		&i.ID,
-		&i.UserUsername,
-		&i.Title,
-		&i.Description,
-		&i.MatchScore,
-		&i.Link,
-		&i.CreatedAt,
-	)
-	return i, err
-}
-
-const deleteScholarship = `-- name: DeleteScholarship :exec
-DELETE FROM scholarships
-WHERE id = $1 AND user_username = $2
+const deleteUserScholarshipMatch = `-- name: DeleteUserScholarshipMatch :exec
+DELETE FROM user_scholarship_matches
+WHERE user_username = $1 AND scholarship_id = $2
 `
 
-type DeleteScholarshipParams struct {
-	ID           int64  `json:"id"`
-	UserUsername string `json:"user_username"`
+type DeleteUserScholarshipMatchParams struct {
+	UserUsername  string `json:"user_username"`
+	ScholarshipID int64  `json:"scholarship_id"`
 }
 
-func (q *Queries) DeleteScholarship(ctx context.Context, arg DeleteScholarshipParams) error {
-	_, err := q.db.ExecContext(ctx, deleteScholarship, arg.ID, arg.UserUsername)
+func (q *Queries) DeleteUserScholarshipMatch(ctx context.Context, arg DeleteUserScholarshipMatchParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserScholarshipMatch, arg.UserUsername, arg.ScholarshipID)
 	return err
 }
 
-const listRecentScholarshipsByUser = `-- name: ListRecentScholarshipsByUser :many
-SELECT id, user_username, title, description, match_score, link, created_at FROM scholarships
-WHERE user_username = $1
-ORDER BY created_at DESC
+const listUserScholarships = `-- name: ListUserScholarships :many
+SELECT c.id, c.url_hash, c.canonical_url, c.title, c.description, c.provider, c.amount, c.currency, c.deadline, c.eligible_nationalities, c.degree_levels, c.fields_of_study, c.created_at, c.updated_at, m.match_score, m.search_query, m.updated_at AS matched_at
+FROM user_scholarship_matches m
+JOIN scholarship_catalog c ON c.id = m.scholarship_id
+WHERE m.user_username = $1
+  AND (c.deadline IS NULL OR c.deadline >= CURRENT_DATE)
+ORDER BY m.match_score DESC, c.id
 LIMIT $2
 `
 
-type ListRecentScholarshipsByUserParams struct {
+type ListUserScholarshipsParams struct {
 	UserUsername string `json:"user_username"`
 	Limit        int64  `json:"limit"`
 }
 
-func (q *Queries) ListRecentScholarshipsByUser(ctx context.Context, arg ListRecentScholarshipsByUserParams) ([]Scholarship, error) {
-	rows, err := q.db.QueryContext(ctx, listRecentScholarshipsByUser, arg.UserUsername, arg.Limit)
+type ListUserScholarshipsRow struct {
+	ID                    int64           `json:"id"`
+	UrlHash               string          `json:"url_hash"`
+	CanonicalUrl          string          `json:"canonical_url"`
+	Title                 string          `json:"title"`
+	Description           sql.NullString  `json:"description"`
+	Provider              sql.NullString  `json:"provider"`
+	Amount                sql.NullFloat64 `json:"amount"`
+	Currency              sql.NullString  `json:"currency"`
+	Deadline              sql.NullTime    `json:"deadline"`
+	EligibleNationalities []string        `json:"eligible_nationalities"`
+	DegreeLevels          []string        `json:"degree_levels"`
+	FieldsOfStudy         []string        `json:"fields_of_study"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+	MatchScore            float64         `json:"match_score"`
+	SearchQuery           sql.NullString  `json:"search_query"`
+	MatchedAt             time.Time       `json:"matched_at"`
+}
+
+func (q *Queries) ListUserScholarships(ctx context.Context, arg ListUserScholarshipsParams) ([]ListUserScholarshipsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserScholarships, arg.UserUsername, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Scholarship{}
+	items := []ListUserScholarshipsRow{}
 	for rows.Next() {
-		var i Scholarship
+		var i ListUserScholarshipsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserUsername,
+			&i.UrlHash,
+			&i.CanonicalUrl,
 			&i.Title,
 			&i.Description,
-			&i.MatchScore,
-			&i.Link,
+			&i.Provider,
+			&i.Amount,
+			&i.Currency,
+			&i.Deadline,
+			pq.Array(&i.EligibleNationalities),
+			pq.Array(&i.DegreeLevels),
+			pq.Array(&i.FieldsOfStudy),
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MatchScore,
+			&i.SearchQuery,
+			&i.MatchedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -107,39 +104,114 @@ func (q *Queries) ListRecentScholarshipsByUser(ctx context.Context, arg ListRece
 	return items, nil
 }
 
-const listScholarshipsByUser = `-- name: ListScholarshipsByUser :many
-SELECT id, user_username, title, description, match_score, link, created_at FROM scholarships
-WHERE user_username = $1
-ORDER BY match_score DESC
+const upsertCatalogScholarship = `-- name: UpsertCatalogScholarship :one
+INSERT INTO scholarship_catalog (
+  url_hash, canonical_url, title, description, provider, amount, currency,
+  deadline, eligible_nationalities, degree_levels, fields_of_study
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+ON CONFLICT (url_hash) DO UPDATE SET
+  title = EXCLUDED.title,
+  description = COALESCE(EXCLUDED.description, scholarship_catalog.description),
+  provider = COALESCE(EXCLUDED.provider, scholarship_catalog.provider),
+  amount = COALESCE(EXCLUDED.amount, scholarship_catalog.amount),
+  currency = COALESCE(EXCLUDED.currency, scholarship_catalog.currency),
+  deadline = COALESCE(EXCLUDED.deadline, scholarship_catalog.deadline),
+  eligible_nationalities = CASE WHEN cardinality(EXCLUDED.eligible_nationalities) > 0
+    THEN EXCLUDED.eligible_nationalities ELSE scholarship_catalog.eligible_nationalities END,
+  degree_levels = CASE WHEN cardinality(EXCLUDED.degree_levels) > 0
+    THEN EXCLUDED.degree_levels ELSE scholarship_catalog.degree_levels END,
+  fields_of_study = CASE WHEN cardinality(EXCLUDED.fields_of_study) > 0
+    THEN EXCLUDED.fields_of_study ELSE scholarship_catalog.fields_of_study END,
+  updated_at = now()
+RETURNING id, url_hash, canonical_url, title, description, provider, amount, currency, deadline, eligible_nationalities, degree_levels, fields_of_study, created_at, updated_at
 `
 
-func (q *Queries) ListScholarshipsByUser(ctx context.Context, userUsername string) ([]Scholarship, error) {
-	rows, err := q.db.QueryContext(ctx, listScholarshipsByUser, userUsername)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Scholarship{}
-	for rows.Next() {
-		var i Scholarship
-		if err := rows.Scan(
-			&i.ID,
-			&i.UserUsername,
-			&i.Title,
-			&i.Description,
-			&i.MatchScore,
-			&i.Link,
-			&i.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type UpsertCatalogScholarshipParams struct {
+	UrlHash               string          `json:"url_hash"`
+	CanonicalUrl          string          `json:"canonical_url"`
+	Title                 string          `json:"title"`
+	Description           sql.NullString  `json:"description"`
+	Provider              sql.NullString  `json:"provider"`
+	Amount                sql.NullFloat64 `json:"amount"`
+	Currency              sql.NullString  `json:"currency"`
+	Deadline              sql.NullTime    `json:"deadline"`
+	EligibleNationalities []string        `json:"eligible_nationalities"`
+	DegreeLevels          []string        `json:"degree_levels"`
+	FieldsOfStudy         []string        `json:"fields_of_study"`
+}
+
+// db/query/scholarship.sql
+func (q *Queries) UpsertCatalogScholarship(ctx context.Context, arg UpsertCatalogScholarshipParams) (ScholarshipCatalog, error) {
+	row := q.db.QueryRowContext(ctx, upsertCatalogScholarship,
+		arg.UrlHash,
+		arg.CanonicalUrl,
+		arg.Title,
+		arg.Description,
+		arg.Provider,
+		arg.Amount,
+		arg.Currency,
+		arg.Deadline,
+		pq.Array(arg.EligibleNationalities),
+		pq.Array(arg.DegreeLevels),
+		pq.Array(arg.FieldsOfStudy),
+	)
+	var i ScholarshipCatalog
+	err := row.Scan(
+		&i.ID,
+		&i.UrlHash,
+		&i.CanonicalUrl,
+		&i.Title,
+		&i.Description,
+		&i.Provider,
+		&i.Amount,
+		&i.Currency,
+		&i.Deadline,
+		pq.Array(&i.EligibleNationalities),
+		pq.Array(&i.DegreeLevels),
+		pq.Array(&i.FieldsOfStudy),
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserScholarshipMatch = `-- name: UpsertUserScholarshipMatch :one
+INSERT INTO user_scholarship_matches (
+  user_username, scholarship_id, match_score, search_query
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (user_username, scholarship_id) DO UPDATE SET
+  match_score = EXCLUDED.match_score,
+  search_query = COALESCE(EXCLUDED.search_query, user_scholarship_matches.search_query),
+  updated_at = now()
+RETURNING user_username, scholarship_id, match_score, created_at, updated_at, search_query
+`
+
+type UpsertUserScholarshipMatchParams struct {
+	UserUsername  string         `json:"user_username"`
+	ScholarshipID int64          `json:"scholarship_id"`
+	MatchScore    float64        `json:"match_score"`
+	SearchQuery   sql.NullString `json:"search_query"`
+}
+
+func (q *Queries) UpsertUserScholarshipMatch(ctx context.Context, arg UpsertUserScholarshipMatchParams) (UserScholarshipMatch, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserScholarshipMatch,
+		arg.UserUsername,
+		arg.ScholarshipID,
+		arg.MatchScore,
+		arg.SearchQuery,
+	)
+	var i UserScholarshipMatch
+	err := row.Scan(
+		&i.UserUsername,
+		&i.ScholarshipID,
+		&i.MatchScore,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchQuery,
+	)
+	return i, err
 }
@@ -10,6 +10,7 @@ import (
 // Store defines all database methods we use in EduSphere.
 type Store interface {
 	Querier
+	ImportCoursesTx(ctx context.Context, arg ImportCoursesTxParams) (ImportCoursesTxResult, error)
 }
 
 // SQLStore provides all functions to execute DB queries and transactions.
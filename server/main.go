@@ -14,14 +14,20 @@
 package main
 
 import (
-	"database/sql" // For database connectivity
-	"log"          // For logging errors
+	"context"       // For the import-courses subcommand
+	"database/sql"  // For database connectivity
+	"encoding/json" // For printing the import diff
+	"flag"          // For subcommand flags
+	"fmt"
+	"log" // For logging errors
+	"os"
 
 	// Database driver for PostgreSQL
 	_ "github.com/lib/pq"
 
 	// Import our own packages
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/api"        // API layer
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/catalog"    // Course catalog file parsing
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc" // SQLC-generated database queries
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"       // Utilities for config, password hashing, etc.
 )
@@ -51,6 +57,14 @@ func main() {
 	// Create a store (wrapper around SQLC queries)
 	store := db.NewStore(conn)
 
+	// Subcommands: `main import-courses -file courses.csv [-dry-run]`
+	if len(os.Args) > 1 && os.Args[1] == "import-courses" {
+		if err := importCourses(store, os.Args[2:]); err != nil {
+			log.Fatal("import-courses: ", err)
+		}
+		return
+	}
+
 	// Initialize the API server with configuration and store
 	server, err := api.NewServer(config, store)
 	if err != nil {
@@ -63,3 +77,52 @@ func main() {
 		log.Fatal("cannot start server", err)
 	}
 }
+
+// importCourses loads a CSV, JSON or NDJSON catalog file into the courses
+// table, the same way as POST /api/admin/courses/import, and prints the diff.
+func importCourses(store db.Store, args []string) error {
+	flags := flag.NewFlagSet("import-courses", flag.ContinueOnError)
+	file := flags.String("file", "", "catalog file (.csv, .json, .ndjson)")
+	format := flags.String("format", "", "csv | json | ndjson (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "only print the diff")
+	archiveMissing := flags.Bool("archive-missing", false, "archive active courses that are not in the file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+
+	fileFormat, err := catalog.DetectFormat(*format, *file)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	courses, err := catalog.Parse(f, fileFormat)
+	if err != nil {
+		return err
+	}
+
+	result, err := store.ImportCoursesTx(context.Background(), db.ImportCoursesTxParams{
+		Courses:        courses,
+		DryRun:         *dryRun,
+		ArchiveMissing: *archiveMissing,
+	})
+	if err != nil {
+		return err
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	if err := out.Encode(result); err != nil {
+		return err
+	}
+	log.Printf("added %d, changed %d, unchanged %d, removed %d (applied: %t)",
+		len(result.Added), len(result.Changed), len(result.Unchanged), len(result.Removed), result.Applied)
+	return nil
+}
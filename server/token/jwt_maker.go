@@ -26,10 +26,10 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-// CreateToken generates a new JWT token for a given username and duration
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	// Create a new Payload object with the provided username and duration
-	payload, err := NewPayload(username, duration)
+// CreateToken generates a new JWT token for a given username, role, token type and duration
+func (maker *JWTMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	// Create a new Payload object with the provided username, role, token type and duration
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -17,6 +17,7 @@ func TestJWTMaker(t *testing.T) {
 
 	// Generate a random username
 	username := util.RandomOwner()
+	role := util.StudentRole
 	// Set a token duration of one minute
 	duration := time.Minute
 
@@ -24,8 +25,8 @@ func TestJWTMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	// Create a new token with the username and duration
-	token, payload, err := maker.CreateToken(username, duration)
+	// Create a new token with the username, role and duration
+	token, payload, err := maker.CreateToken(username, role, TokenTypeAccess, duration)
 	require.NoError(t, err, "Failed to create token")       // Assert no errors
 	require.NotEmpty(t, token, "Empty token generated")     // Assert token is not empty
 	require.NotEmpty(t, payload, "Empty payload generated") // Assert payload is not empty
@@ -37,6 +38,8 @@ func TestJWTMaker(t *testing.T) {
 	// Assert various properties of the extracted payload
 	require.NotZero(t, payload.ID, "Payload ID is zero")
 	require.Equal(t, username, payload.Username, "Username mismatch")
+	require.Equal(t, role, payload.Role, "Role mismatch")
+	require.Equal(t, TokenTypeAccess, payload.Type, "Token type mismatch")
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second, "IssuedAt time mismatch")
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second, "ExpiredAt time mismatch")
 }
@@ -48,7 +51,7 @@ func TestExpiredJWTToken(t *testing.T) {
 	require.NoError(t, err, "Failed to create JWTMaker") // Assert no errors
 
 	// Create a token with a negative duration (expired)
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.StudentRole, TokenTypeAccess, -time.Minute)
 	require.NoError(t, err, "Failed to create token")       // Assert no errors
 	require.NotEmpty(t, token, "Empty token generated")     // Assert token is not empty
 	require.NotEmpty(t, payload, "Empty payload generated") // Assert payload is not empty
@@ -63,7 +66,7 @@ func TestExpiredJWTToken(t *testing.T) {
 // TestInvalidJWTTokenAlgNone tests handling of tokens with an invalid signing algorithm
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	// Create a payload object
-	payload, err := NewPayload(util.RandomOwner(), time.Minute)
+	payload, err := NewPayload(util.RandomOwner(), util.StudentRole, TokenTypeAccess, time.Minute)
 	require.NoError(t, err, "Failed to create payload") // Assert no errors
 
 	// Create a JWT token with the "none" signing method (invalid for HMAC)
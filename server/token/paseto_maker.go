@@ -33,10 +33,10 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	return maker, nil
 }
 
-// CreateToken generates a new token for a username and duration using PASETO encryption
-func (maker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	// Create a new Payload object with username and duration
-	payload, err := NewPayload(username, duration)
+// CreateToken generates a new token for a username, role, token type and duration using PASETO encryption
+func (maker *PasetoMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	// Create a new Payload object with username, role, token type and duration
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
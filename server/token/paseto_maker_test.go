@@ -16,6 +16,7 @@ func TestPasetoMaker(t *testing.T) {
 
 	// Generate a random username
 	username := util.RandomOwner()
+	role := util.StudentRole
 	// Set a token duration of one minute
 	duration := time.Minute
 
@@ -23,8 +24,8 @@ func TestPasetoMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	// Create a new token with the username and duration
-	token, payload, err := maker.CreateToken(username, duration)
+	// Create a new token with the username, role and duration
+	token, payload, err := maker.CreateToken(username, role, TokenTypeAccess, duration)
 	require.NoError(t, err, "Failed to create token")       // Assert no errors
 	require.NotEmpty(t, token, "Empty token generated")     // Assert token is not empty
 	require.NotEmpty(t, payload, "Empty payload generated") // Assert payload is not empty
@@ -36,6 +37,8 @@ func TestPasetoMaker(t *testing.T) {
 	// Assert various properties of the extracted payload
 	require.NotZero(t, payload.ID, "Payload ID is zero")
 	require.Equal(t, username, payload.Username, "Username mismatch")
+	require.Equal(t, role, payload.Role, "Role mismatch")
+	require.Equal(t, TokenTypeAccess, payload.Type, "Token type mismatch")
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second, "IssuedAt time mismatch")
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second, "ExpiredAt time mismatch")
 }
@@ -47,7 +50,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.NoError(t, err, "Failed to create PasetoMaker") // Assert no errors
 
 	// Create a token with a negative duration (expired)
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomOwner(), util.StudentRole, TokenTypeAccess, -time.Minute)
 	require.NoError(t, err, "Failed to create token")       // Assert no errors
 	require.NotEmpty(t, token, "Empty token generated")     // Assert token is not empty
 	require.NotEmpty(t, payload, "Empty payload generated") // Assert payload is not empty
@@ -15,16 +15,27 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// TokenType is the purpose of a token. Access tokens authorize API calls,
+// refresh tokens only renew access tokens; neither is accepted for the other
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
 // Payload represents the data contained within a token
 type Payload struct {
 	ID        uuid.UUID `json:"id"`         // Unique identifier for the token (UUID)
 	Username  string    `json:"username"`   // Username associated with the token
+	Role      string    `json:"role"`       // Role of the user (student, advisor or admin)
+	Type      TokenType `json:"type"`       // Purpose of the token (access or refresh)
 	IssuedAt  time.Time `json:"issued_at"`  // Time the token was issued
 	ExpiredAt time.Time `json:"expired_at"` // Time the token expires
 }
 
-// NewPayload creates a new Payload object with a username and duration
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// NewPayload creates a new Payload object with a username, role, token type and duration
+func NewPayload(username string, role string, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	// Generate a random UUID for the token ID
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
@@ -35,6 +46,8 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	payload := &Payload{
 		ID:        tokenID,
 		Username:  username,
+		Role:      role,
+		Type:      tokenType,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
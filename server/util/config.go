@@ -11,25 +11,102 @@ import (
 
 // Config represents the application configuration loaded from a file or environment variables
 type Config struct {
-	DBDriver            string        `mapstructure:"DB_DRIVER"`
-	DBSource            string        `mapstructure:"DB_SOURCE"`
-	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	AllowedOrigins      string        `mapstructure:"ALLOWED_ORIGINS"`
-	UploadDir           string        `mapstructure:"UPLOAD_DIR"`
+	DBDriver             string        `mapstructure:"DB_DRIVER"`
+	DBSource             string        `mapstructure:"DB_SOURCE"`
+	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	AllowedOrigins       string        `mapstructure:"ALLOWED_ORIGINS"`
+	UploadDir            string        `mapstructure:"UPLOAD_DIR"`
 
 	// AI Inference (OpenAI)
 	OpenAIAPIKey       string `mapstructure:"OPENAI_API_KEY"`
 	OpenAIModel        string `mapstructure:"OPENAI_MODEL"`
 	OCRFallbackEnabled bool   `mapstructure:"OCR_FALLBACK_ENABLED"`
 
-	// Web Search (Brave API)
-	WebSearchEnabled    bool   `mapstructure:"WEB_SEARCH_ENABLED"`
-	WebSearchMaxResults int    `mapstructure:"WEB_SEARCH_MAX_RESULTS"`
-	WebSearchProvider   string `mapstructure:"WEB_SEARCH_PROVIDER"`
-	BraveAPIKey         string `mapstructure:"BRAVE_API_KEY"`
-	BraveAPIURL         string `mapstructure:"BRAVE_API_URL"`
+	// LLM provider selection: "openai", "openai_compatible" (Ollama, vLLM, ...) or "fake"
+	LLMProvider string `mapstructure:"LLM_PROVIDER"`
+	LLMBaseURL  string `mapstructure:"LLM_BASE_URL"`
+
+	// LLM metering: every call is recorded in llm_usage, priced in USD per
+	// million prompt/completion tokens. LLM_DAILY_TOKEN_BUDGET caps each
+	// user's tokens per UTC day (0 = unlimited)
+	LLMPromptPricePerMTok     float64 `mapstructure:"LLM_PROMPT_PRICE_PER_MTOK"`
+	LLMCompletionPricePerMTok float64 `mapstructure:"LLM_COMPLETION_PRICE_PER_MTOK"`
+	LLMDailyTokenBudget       int64   `mapstructure:"LLM_DAILY_TOKEN_BUDGET"`
+
+	// Embeddings for semantic course retrieval: "hashing" (offline), "openai" or "openai_compatible".
+	// Remote embedders are metered in llm_usage at EMBEDDING_PRICE_PER_MTOK
+	EmbeddingProvider     string  `mapstructure:"EMBEDDING_PROVIDER"`
+	EmbeddingModel        string  `mapstructure:"EMBEDDING_MODEL"`
+	EmbeddingPricePerMTok float64 `mapstructure:"EMBEDDING_PRICE_PER_MTOK"`
+	RecommendationTopK    int     `mapstructure:"RECOMMENDATION_TOP_K"`
+
+	// Course ranking: "llm" (falls back to lexical on failure) or "lexical" (BM25, no LLM)
+	RecommendationEngine string `mapstructure:"RECOMMENDATION_ENGINE"`
+
+	// Chat conversations: messages beyond CHAT_HISTORY_TOKENS (estimated) are
+	// summarized by the LLM (CHAT_SUMMARIZE_HISTORY) or left out of the prompt
+	ChatHistoryTokens    int  `mapstructure:"CHAT_HISTORY_TOKENS"`
+	ChatSummarizeHistory bool `mapstructure:"CHAT_SUMMARIZE_HISTORY"`
+
+	// Chat grounding: the CHAT_RETRIEVAL_TOP_K transcript, course and
+	// scholarship chunks closest to the question are cited in the prompt
+	ChatRetrievalTopK int `mapstructure:"CHAT_RETRIEVAL_TOP_K"`
+
+	// Chat tools: the assistant may search courses, check prerequisites, edit
+	// recommendations and start scholarship searches or summary PDFs
+	ChatToolsEnabled bool `mapstructure:"CHAT_TOOLS_ENABLED"`
+
+	// Background jobs: worker pool size (0 runs jobs inside the request) and attempts per job
+	JobWorkers     int `mapstructure:"JOB_WORKERS"`
+	JobMaxAttempts int `mapstructure:"JOB_MAX_ATTEMPTS"`
+
+	// Notifications: deadline reminder scan interval (0 disables it), reminder
+	// emails over SMTP and a generic JSON webhook (optional HMAC secret)
+	ReminderInterval    time.Duration `mapstructure:"REMINDER_INTERVAL"`
+	SMTPHost            string        `mapstructure:"SMTP_HOST"`
+	SMTPPort            int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername        string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword        string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom            string        `mapstructure:"SMTP_FROM"`
+	NotifyWebhookURL    string        `mapstructure:"NOTIFY_WEBHOOK_URL"`
+	NotifyWebhookSecret string        `mapstructure:"NOTIFY_WEBHOOK_SECRET"`
+
+	// Web Search: brave | searxng | json | fixture, comma-separated to fan out
+	// across several providers (results are merged and deduplicated by URL)
+	WebSearchEnabled         bool   `mapstructure:"WEB_SEARCH_ENABLED"`
+	WebSearchMaxResults      int    `mapstructure:"WEB_SEARCH_MAX_RESULTS"`
+	WebSearchProvider        string `mapstructure:"WEB_SEARCH_PROVIDER"`
+	BraveAPIKey              string `mapstructure:"BRAVE_API_KEY"`
+	BraveAPIURL              string `mapstructure:"BRAVE_API_URL"`
+	SearXNGURL               string `mapstructure:"SEARXNG_URL"`
+	WebSearchJSONURL         string `mapstructure:"WEB_SEARCH_JSON_URL"`
+	WebSearchJSONAPIKey      string `mapstructure:"WEB_SEARCH_JSON_API_KEY"`
+	WebSearchJSONResultsPath string `mapstructure:"WEB_SEARCH_JSON_RESULTS_PATH"`
+	WebSearchJSONTitleKey    string `mapstructure:"WEB_SEARCH_JSON_TITLE_KEY"`
+	WebSearchJSONURLKey      string `mapstructure:"WEB_SEARCH_JSON_URL_KEY"`
+	WebSearchJSONSnippetKey  string `mapstructure:"WEB_SEARCH_JSON_SNIPPET_KEY"`
+	WebSearchFixtureFile     string `mapstructure:"WEB_SEARCH_FIXTURE_FILE"`
+
+	// Web search results are cached in Postgres for WEB_SEARCH_CACHE_TTL (0
+	// disables the cache); WEB_SEARCH_DAILY_QUOTA limits requests per provider
+	// and UTC day, e.g. "brave=60,searxng=500" (unlisted providers: no limit)
+	WebSearchCacheTTL   time.Duration `mapstructure:"WEB_SEARCH_CACHE_TTL"`
+	WebSearchDailyQuota string        `mapstructure:"WEB_SEARCH_DAILY_QUOTA"`
+
+	// Scholarship pages: the top SCHOLARSHIP_FETCH_MAX_PAGES results are
+	// downloaded (robots.txt, size and time limits) and kept in
+	// SCHOLARSHIP_FETCH_CACHE_DIR ("" disables the cache) for
+	// SCHOLARSHIP_FETCH_CACHE_TTL (0 keeps them forever)
+	ScholarshipFetchEnabled   bool          `mapstructure:"SCHOLARSHIP_FETCH_ENABLED"`
+	ScholarshipFetchMaxPages  int           `mapstructure:"SCHOLARSHIP_FETCH_MAX_PAGES"`
+	ScholarshipFetchTimeout   time.Duration `mapstructure:"SCHOLARSHIP_FETCH_TIMEOUT"`
+	ScholarshipFetchMaxBytes  int64         `mapstructure:"SCHOLARSHIP_FETCH_MAX_BYTES"`
+	ScholarshipFetchUserAgent string        `mapstructure:"SCHOLARSHIP_FETCH_USER_AGENT"`
+	ScholarshipFetchCacheDir  string        `mapstructure:"SCHOLARSHIP_FETCH_CACHE_DIR"`
+	ScholarshipFetchCacheTTL  time.Duration `mapstructure:"SCHOLARSHIP_FETCH_CACHE_TTL"`
 }
 
 // LoadConfig reads the application configuration from a specified file or environment variables
@@ -47,14 +124,41 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.AutomaticEnv()
 
 	// Sensible defaults
+	viper.SetDefault("REFRESH_TOKEN_DURATION", "24h")
 	viper.SetDefault("OPENAI_MODEL", "gpt-4o-mini")
+	viper.SetDefault("LLM_PROVIDER", "openai")
+	viper.SetDefault("LLM_PROMPT_PRICE_PER_MTOK", 0.15)
+	viper.SetDefault("LLM_COMPLETION_PRICE_PER_MTOK", 0.60)
+	viper.SetDefault("LLM_DAILY_TOKEN_BUDGET", 0)
+	viper.SetDefault("EMBEDDING_PROVIDER", "hashing")
+	viper.SetDefault("EMBEDDING_MODEL", "text-embedding-3-small")
+	viper.SetDefault("EMBEDDING_PRICE_PER_MTOK", 0.02)
+	viper.SetDefault("RECOMMENDATION_TOP_K", 25)
+	viper.SetDefault("RECOMMENDATION_ENGINE", "llm")
+	viper.SetDefault("CHAT_HISTORY_TOKENS", 6000)
+	viper.SetDefault("CHAT_SUMMARIZE_HISTORY", true)
+	viper.SetDefault("CHAT_RETRIEVAL_TOP_K", 6)
+	viper.SetDefault("CHAT_TOOLS_ENABLED", true)
+	viper.SetDefault("JOB_WORKERS", 4)
+	viper.SetDefault("JOB_MAX_ATTEMPTS", 3)
 	viper.SetDefault("OCR_FALLBACK_ENABLED", true)
 	viper.SetDefault("UPLOAD_DIR", "uploads")
+	viper.SetDefault("REMINDER_INTERVAL", "1h")
+	viper.SetDefault("SMTP_PORT", 587)
 
 	viper.SetDefault("WEB_SEARCH_PROVIDER", "brave")
 	viper.SetDefault("WEB_SEARCH_ENABLED", true)
 	viper.SetDefault("WEB_SEARCH_MAX_RESULTS", 5)
 	viper.SetDefault("BRAVE_API_URL", "https://api.search.brave.com/res/v1/web/search")
+	viper.SetDefault("WEB_SEARCH_CACHE_TTL", "24h")
+
+	viper.SetDefault("SCHOLARSHIP_FETCH_ENABLED", true)
+	viper.SetDefault("SCHOLARSHIP_FETCH_MAX_PAGES", 10)
+	viper.SetDefault("SCHOLARSHIP_FETCH_TIMEOUT", "10s")
+	viper.SetDefault("SCHOLARSHIP_FETCH_MAX_BYTES", 1<<20)
+	viper.SetDefault("SCHOLARSHIP_FETCH_USER_AGENT", "EduSphereBot/1.0")
+	viper.SetDefault("SCHOLARSHIP_FETCH_CACHE_DIR", "cache/pages")
+	viper.SetDefault("SCHOLARSHIP_FETCH_CACHE_TTL", "168h")
 
 	// Attempt to read the configuration file
 	err = viper.ReadInConfig()
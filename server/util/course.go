@@ -0,0 +1,13 @@
+// server/util/course.go
+
+package util
+
+import "regexp"
+
+// courseCodeRe matches catalog codes such as TJTS5012 or ITKST50.
+var courseCodeRe = regexp.MustCompile(`^[A-ZÅÄÖ]{2,8}\d{2,5}[A-Z]?$`)
+
+// IsCourseCode returns true if code is a well-formed course code
+func IsCourseCode(code string) bool {
+	return courseCodeRe.MatchString(code)
+}
@@ -2,7 +2,18 @@
 
 package util
 
+// User roles stored in users.role and carried in token payloads.
 const (
-	DepositorRole = "depositor"
-	BankerRole    = "banker"
+	StudentRole = "student"
+	AdvisorRole = "advisor"
+	AdminRole   = "admin"
 )
+
+// IsSupportedRole returns true if the role is supported
+func IsSupportedRole(role string) bool {
+	switch role {
+	case StudentRole, AdvisorRole, AdminRole:
+		return true
+	}
+	return false
+}